@@ -0,0 +1,97 @@
+package inspection
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// cleanups holds the teardown functions of the child processes and temporary files in use, so they can
+// be released when the process is about to exit while inspections are in progress
+var cleanups = &cleanupRegistry{funcs: make(map[uint64]func())}
+
+// cleanupRegistry is a set of teardown functions
+type cleanupRegistry struct {
+	mu    sync.Mutex
+	next  uint64
+	funcs map[uint64]func()
+}
+
+// add registers fn and returns a function unregistering it without running it
+func (r *cleanupRegistry) add(fn func()) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.next
+	r.next++
+	r.funcs[id] = fn
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.funcs, id)
+	}
+}
+
+// run runs and unregisters all teardown functions concurrently, and waits for them
+func (r *cleanupRegistry) run() {
+	r.mu.Lock()
+	funcs := r.funcs
+	r.funcs = make(map[uint64]func())
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, fn := range funcs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+	wg.Wait()
+}
+
+// registerCleanup registers the teardown of a child process or temporary file
+// The returned function must be called once fn has run or is no longer needed
+func registerCleanup(fn func()) func() {
+	return cleanups.add(fn)
+}
+
+// trackTempPath registers the removal of a temporary file or directory of executor
+// The returned function removes it and must be called once it is no longer used
+func trackTempPath(executor Executor, path string) func() {
+	remove := func() { _ = executor.RemoveAll(context.Background(), path) }
+	unregister := registerCleanup(remove)
+	return func() {
+		unregister()
+		remove()
+	}
+}
+
+// Cleanup stops the NBD servers and removes the sockets, password files and key files of all sessions and
+// inspections in progress
+// Inspections in progress fail afterwards. Call it before the process exits abnormally, e.g. deferred in main
+// with a recover, so a panic does not leave nbdkit processes and credentials behind
+func Cleanup() {
+	cleanups.run()
+}
+
+// CleanupOnSignal runs Cleanup when the process receives SIGINT or SIGTERM, then terminates the process
+// with the signal as if it had not been handled
+// Signals are handled until ctx is done
+func CleanupOnSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			Cleanup()
+			signal.Reset(sig)
+			if process, err := os.FindProcess(os.Getpid()); err == nil {
+				_ = process.Signal(sig)
+			}
+		case <-ctx.Done():
+		}
+	}()
+}
@@ -0,0 +1,72 @@
+package inspection
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// DecryptionKey is a key of an encrypted (LUKS or BitLocker) guest device
+// Exactly one of Key and File is set
+type DecryptionKey struct {
+	Device string // Guest device (e.g. "/dev/sda2"), LUKS UUID, or "all" to try the key on every encrypted device
+	Key    string // Passphrase or BitLocker recovery key
+	File   string // Path of a file containing the key
+}
+
+// selector returns the libguestfs --key selector of the key, reading the key from file
+func (k DecryptionKey) selector(file string) (string, error) {
+	if k.Device == "" {
+		return "", fmt.Errorf("decryption key has no device")
+	}
+	if (k.Key == "") == (k.File == "") {
+		return "", fmt.Errorf("decryption key of %s must have either a key or a file", k.Device)
+	}
+	return fmt.Sprintf("%s:file:%s", k.Device, file), nil
+}
+
+// keySecrets returns the keys given as values, to redact from logs and errors
+func keySecrets(keys []DecryptionKey) []string {
+	var secrets []string
+	for _, key := range keys {
+		if key.Key != "" {
+			secrets = append(secrets, key.Key)
+		}
+	}
+	return secrets
+}
+
+// keyArgs returns the --key arguments of libguestfs tools run by executor for keys
+// Keys are written to files in a directory only accessible by the current user, so they don't appear
+// in the process table; the returned function removes the files
+// Key files of File are read by the tools, so they must exist where executor runs them
+func keyArgs(ctx context.Context, executor Executor, keys []DecryptionKey) ([]string, func(), error) {
+	if len(keys) == 0 {
+		return nil, func() {}, nil
+	}
+	dir, err := executor.TempDir(ctx, "inspection-keys")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create key directory: %w", err)
+	}
+	cleanup := trackTempPath(executor, dir)
+
+	var args []string
+	for idx, key := range keys {
+		file := key.File
+		if key.Key != "" {
+			file = filepath.Join(dir, "key"+strconv.Itoa(idx))
+			if err := executor.WriteFile(ctx, file, []byte(key.Key)); err != nil {
+				cleanup()
+				return nil, nil, fmt.Errorf("failed to write key of %s: %w", key.Device, err)
+			}
+		}
+		selector, err := key.selector(file)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		args = append(args, "--key", selector)
+	}
+	return args, cleanup, nil
+}
@@ -0,0 +1,60 @@
+package inspection
+
+import (
+	"context"
+	"time"
+)
+
+// InspectionPhase is a step of an inspection reported to the event handler of an inspector
+type InspectionPhase string
+
+// Inspection phases, in order
+const (
+	PhaseAuthFileCreated  InspectionPhase = "auth_file_created"  // virt-v2v-inspector password file written
+	PhaseNBDServerStarted InspectionPhase = "nbd_server_started" // NBD server process of a disk started
+	PhaseNBDReady         InspectionPhase = "nbd_ready"          // NBD server of a disk serves its export
+	PhaseToolStarted      InspectionPhase = "tool_started"       // virt-inspector, virt-v2v-inspector or guestfish started
+	PhaseXMLParsed        InspectionPhase = "xml_parsed"         // Inspection output parsed
+)
+
+// InspectionEvent reports a phase transition of an inspection
+type InspectionEvent struct {
+	Phase InspectionPhase
+	Time  time.Time
+	VM    string // VM name
+	Tool  string // Tool started or parsed (PhaseToolStarted, PhaseXMLParsed)
+	Disk  string // Base disk path (PhaseNBDServerStarted, PhaseNBDReady)
+}
+
+// EventHandler receives the events of inspections
+// It is called synchronously from the inspection goroutines, so it must be fast and safe for concurrent use
+type EventHandler func(InspectionEvent)
+
+// eventsKey is the context key of the events of an inspection
+type eventsKey struct{}
+
+// inspectionEvents is the event handler of an inspection with the inspected VM
+type inspectionEvents struct {
+	handler EventHandler
+	vmName  string
+}
+
+// withEvents returns a context reporting the events of the inspection of a VM to handler, including
+// the events of the sessions opened for the inspection
+func withEvents(ctx context.Context, handler EventHandler, vmName string) context.Context {
+	if handler == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, eventsKey{}, inspectionEvents{handler: handler, vmName: vmName})
+}
+
+// emit reports an event to the handler of the context, if any
+func emit(ctx context.Context, event InspectionEvent) {
+	events, ok := ctx.Value(eventsKey{}).(inspectionEvents)
+	if !ok {
+		return
+	}
+	event.Time = time.Now().UTC()
+	event.VM = events.vmName
+	events.handler(event)
+}
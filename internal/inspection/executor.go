@@ -0,0 +1,199 @@
+package inspection
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Executor runs the NBD servers and inspection tools (nbdkit, qemu-nbd, virt-v2v-open, virt-inspector,
+// guestfish and virt-v2v-inspector) and holds the sockets, password files and key files they use
+// Paths passed to the methods are paths of the host running the commands
+type Executor interface {
+	// Command returns the command running the program name with args
+	Command(ctx context.Context, name string, args ...string) *exec.Cmd
+
+	// VDDKLibrary returns the VDDK library available to the commands in libDir (searched if empty)
+	VDDKLibrary(libDir string) (*VDDKLibrary, error)
+
+	// TempDir creates a new temporary directory only accessible by the current user
+	TempDir(ctx context.Context, prefix string) (string, error)
+
+	// WriteFile writes data to a file only readable by the current user
+	WriteFile(ctx context.Context, path string, data []byte) error
+
+	// RemoveAll removes path and any children it contains
+	RemoveAll(ctx context.Context, path string) error
+
+	// Fetch returns a local directory with the content of dir, and a function removing the local copy
+	Fetch(ctx context.Context, dir string) (string, func(), error)
+
+	// Dial connects to a unix socket or TCP address listened on by the commands
+	Dial(ctx context.Context, network string, address string) (net.Conn, error)
+}
+
+// hostFiles implements the file and network methods of executors whose commands share the file system
+// and network of the local host
+type hostFiles struct{}
+
+// TempDir creates a new temporary directory only accessible by the current user
+func (hostFiles) TempDir(_ context.Context, prefix string) (string, error) {
+	return os.MkdirTemp("", prefix+"-*")
+}
+
+// WriteFile writes data to a file only readable by the current user
+func (hostFiles) WriteFile(_ context.Context, path string, data []byte) error {
+	return os.WriteFile(path, data, 0600)
+}
+
+// RemoveAll removes path and any children it contains
+func (hostFiles) RemoveAll(_ context.Context, path string) error {
+	return os.RemoveAll(path)
+}
+
+// Fetch returns dir, which is already local
+func (hostFiles) Fetch(_ context.Context, dir string) (string, func(), error) {
+	return dir, func() {}, nil
+}
+
+// Dial connects to a local unix socket or TCP address
+func (hostFiles) Dial(ctx context.Context, network string, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, address)
+}
+
+// LocalExecutor runs the commands on the local host
+type LocalExecutor struct {
+	hostFiles
+}
+
+// Command returns the command running name with args on the local host
+func (LocalExecutor) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
+
+// identity identifies the local host
+func (LocalExecutor) identity() string {
+	return "local"
+}
+
+// VDDKLibrary returns the VDDK library installed on the local host
+func (LocalExecutor) VDDKLibrary(libDir string) (*VDDKLibrary, error) {
+	return ResolveVDDKLibrary(libDir)
+}
+
+// ContainerExecutor runs each command in a new container, for hosts without libguestfs, nbdkit or VDDK
+// The image must provide the tools and VDDK, the temporary directory of the host is mounted at the same path
+// and the host network is used, so the NBD servers are reachable from the host and the other containers
+// Rootless podman is recommended: the container root is the current user, who can access the sockets
+type ContainerExecutor struct {
+	hostFiles
+	Image   string   // Image reference, e.g. "quay.io/example/v2v-inspection:latest"
+	Runtime string   // Container runtime, "podman" (default) or "docker"
+	RunArgs []string // Extra arguments of the run command, e.g. "--device=/dev/kvm"
+}
+
+// containerEnvPrefixes are the prefixes of the environment variables of the tools forwarded to the containers
+var containerEnvPrefixes = []string{"LIBGUESTFS_", "LIBVIRT_"}
+
+// containerStopTimeout bounds the removal of the container of a cancelled command
+const containerStopTimeout = 30 * time.Second
+
+// Command returns the command running name with args in a new container of the image
+// The container reads the standard input of the command and is removed when the command exits. Signals
+// sent to the command are forwarded to the container, and cancelling ctx removes the container, since
+// killing the runtime client alone leaves the container running
+// The libguestfs settings (LIBGUESTFS_* and LIBVIRT_* variables) of the environment are forwarded to the
+// container, with the values of the command environment (cmd.Env) when set
+func (e ContainerExecutor) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	runtime := e.Runtime
+	if runtime == "" {
+		runtime = "podman"
+	}
+	containerName := newContainerName()
+	tmpDir := os.TempDir()
+	runArgs := []string{
+		"run", "--rm", "--interactive", "--sig-proxy=true",
+		"--name=" + containerName,
+		"--network=host",
+		"--security-opt=label=disable", // Access the mounted temporary directory under SELinux
+		"--volume=" + tmpDir + ":" + tmpDir,
+		"--env=TMPDIR=" + tmpDir,
+	}
+	for _, variable := range os.Environ() {
+		variableName, _, _ := strings.Cut(variable, "=")
+		for _, prefix := range containerEnvPrefixes {
+			if strings.HasPrefix(variableName, prefix) {
+				// Without a value, the runtime copies the variable from its own environment
+				runArgs = append(runArgs, "--env="+variableName)
+				break
+			}
+		}
+	}
+	runArgs = append(runArgs, e.RunArgs...)
+	runArgs = append(runArgs, e.Image, name)
+	runArgs = append(runArgs, args...)
+
+	cmd := exec.CommandContext(ctx, runtime, runArgs...)
+	cmd.Cancel = func() error {
+		removeCtx, cancel := context.WithTimeout(context.Background(), containerStopTimeout)
+		defer cancel()
+		_ = exec.CommandContext(removeCtx, runtime, "rm", "--force", containerName).Run()
+		return cmd.Process.Kill()
+	}
+	return cmd
+}
+
+// newContainerName returns a unique name of a command container
+func newContainerName() string {
+	suffix := make([]byte, 8)
+	_, _ = rand.Read(suffix)
+	return "v2v-inspection-" + hex.EncodeToString(suffix)
+}
+
+// identity identifies the image and its runtime, all the containers of the image provide the same tools
+func (e ContainerExecutor) identity() string {
+	return fmt.Sprintf("container %s %s", cmp.Or(e.Runtime, "podman"), e.Image)
+}
+
+// VDDKLibrary returns the VDDK library of the image in libDir, the first default location if empty
+// The library cannot be verified from the host, nbdkit fails to load it if it is missing from the image
+func (e ContainerExecutor) VDDKLibrary(libDir string) (*VDDKLibrary, error) {
+	if e.Image == "" {
+		return nil, fmt.Errorf("container image is not configured")
+	}
+	return unverifiedVDDKLibrary(libDir), nil
+}
+
+// unverifiedVDDKLibrary returns the VDDK library in libDir, the first default location if empty, for
+// executors that cannot verify it
+func unverifiedVDDKLibrary(libDir string) *VDDKLibrary {
+	if libDir == "" {
+		libDir = defaultVDDKLibDirs[0]
+	}
+	return &VDDKLibrary{Dir: libDir}
+}
+
+// killCommand stops cmd immediately, with its Cancel function when set, so executors also stop what the
+// process runs (e.g. the container of the command)
+func killCommand(cmd *exec.Cmd) error {
+	if cmd.Cancel != nil {
+		return cmd.Cancel()
+	}
+	return cmd.Process.Kill()
+}
+
+// executorOrLocal returns executor, or the local executor if nil
+func executorOrLocal(executor Executor) Executor {
+	if executor == nil {
+		return LocalExecutor{}
+	}
+	return executor
+}
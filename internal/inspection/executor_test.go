@@ -0,0 +1,93 @@
+package inspection
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// containerName returns the name given to the container by the --name argument of args
+func containerName(args []string) string {
+	for _, arg := range args {
+		if name, ok := strings.CutPrefix(arg, "--name="); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func TestContainerExecutorCommand(t *testing.T) {
+	t.Setenv("LIBGUESTFS_BACKEND", "direct")
+	t.Setenv("LIBVIRT_DEFAULT_URI", "qemu:///session")
+	t.Setenv("V2V_UNRELATED", "1")
+	executor := ContainerExecutor{Image: "quay.io/example/v2v:latest", Runtime: "docker", RunArgs: []string{"--device=/dev/kvm"}}
+
+	cmd := executor.Command(context.Background(), "virt-inspector", "--format=raw", "-a", "nbd://x")
+	if cmd.Args[0] != "docker" || cmd.Args[1] != "run" {
+		t.Fatalf("Args = %q, want a docker run command", cmd.Args)
+	}
+	args := cmd.Args[1:]
+	for _, want := range []string{"--rm", "--sig-proxy=true", "--env=LIBGUESTFS_BACKEND", "--env=LIBVIRT_DEFAULT_URI", "--device=/dev/kvm"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("Args = %q, missing %s", args, want)
+		}
+	}
+	if slices.Contains(args, "--env=V2V_UNRELATED") {
+		t.Errorf("Args = %q, forward unrelated variables", args)
+	}
+	tail := args[len(args)-5:]
+	if strings.Join(tail, " ") != "quay.io/example/v2v:latest virt-inspector --format=raw -a nbd://x" {
+		t.Errorf("Args end with %q, want the image and the tool command", tail)
+	}
+
+	name := containerName(args)
+	if name == "" {
+		t.Fatalf("Args = %q, want a container name", args)
+	}
+	if other := containerName(executor.Command(context.Background(), "nbdkit").Args); other == name {
+		t.Errorf("two commands share container name %s", name)
+	}
+}
+
+func TestContainerExecutorCancelRemovesContainer(t *testing.T) {
+	// The fake runtime runs a container until it is removed, and records the removed containers
+	dir := t.TempDir()
+	removed := filepath.Join(dir, "removed")
+	runtime := filepath.Join(dir, "runtime")
+	script := `#!/bin/sh
+if [ "$1" = rm ]; then
+	echo "$@" >> ` + removed + `
+	exit 0
+fi
+exec sleep 60
+`
+	if err := os.WriteFile(runtime, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := ContainerExecutor{Image: "image", Runtime: runtime}.Command(ctx, "nbdkit")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	startedAt := time.Now()
+	cancel()
+	if err := cmd.Wait(); err == nil {
+		t.Error("Wait() = nil for a cancelled command")
+	}
+	if elapsed := time.Since(startedAt); elapsed > 10*time.Second {
+		t.Errorf("cancelled command stopped after %s", elapsed)
+	}
+
+	output, err := os.ReadFile(removed)
+	if err != nil {
+		t.Fatalf("container not removed: %v", err)
+	}
+	if want := "rm --force " + containerName(cmd.Args); strings.TrimSpace(string(output)) != want {
+		t.Errorf("runtime ran %q, want %q", output, want)
+	}
+}
@@ -0,0 +1,96 @@
+package inspection
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// statvfsMarker separates the statvfs output of individual mount points in guestfish output
+const statvfsMarker = "@@@statvfs "
+
+// FilesystemUsage collects usage statistics for the given guest mount points using guestfish statvfs
+// The result maps mount points to their usage; mount points that cannot be queried are omitted.
+func (i *VirtInspector) FilesystemUsage(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+	mountPoints []string,
+) (map[string]types.FilesystemUsage, error) {
+	if len(mountPoints) == 0 {
+		return map[string]types.FilesystemUsage{}, nil
+	}
+
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionCloser()
+
+	i.logger.WithFields(logrus.Fields{
+		"nbd_urls":     nbdURLs,
+		"mount_points": mountPoints,
+	}).Info("Collecting filesystem usage on NBD")
+
+	var script strings.Builder
+	for _, mountPoint := range mountPoints {
+		fmt.Fprintf(&script, "echo %s\n", guestfishQuote(statvfsMarker+mountPoint))
+		fmt.Fprintf(&script, "-statvfs %s\n", guestfishQuote(mountPoint))
+	}
+	output, err := i.runGuestfish(ctx, nbdURLs, script.String())
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := splitMarkedOutput(output, statvfsMarker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statvfs output: %w", err)
+	}
+
+	result := make(map[string]types.FilesystemUsage)
+	for mountPoint, lines := range sections {
+		usage, ok := parseStatvfs(lines)
+		if !ok {
+			continue
+		}
+		usage.MountPoint = mountPoint
+		result[mountPoint] = usage
+	}
+	return result, nil
+}
+
+// parseStatvfs parses guestfish statvfs output ("key: value" lines)
+func parseStatvfs(lines []string) (types.FilesystemUsage, bool) {
+	values := make(map[string]uint64)
+	for _, line := range lines {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSpace(key)] = n
+	}
+
+	frsize, ok := values["frsize"]
+	if !ok || frsize == 0 {
+		return types.FilesystemUsage{}, false
+	}
+	return types.FilesystemUsage{
+		TotalBytes:     values["blocks"] * frsize,
+		FreeBytes:      values["bfree"] * frsize,
+		AvailableBytes: values["bavail"] * frsize,
+	}, true
+}
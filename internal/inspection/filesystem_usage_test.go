@@ -0,0 +1,54 @@
+package inspection
+
+import (
+	"testing"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+func TestParseStatvfs(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  types.FilesystemUsage
+		ok    bool
+	}{
+		{
+			name: "guestfish statvfs",
+			lines: []string{
+				"bsize: 4096", "frsize: 4096", "blocks: 10475520", "bfree: 8126464", "bavail: 8126464",
+				"files: 20971520", "ffree: 20905123", "favail: 20905123", "fsid: 64768", "flag: 4096", "namemax: 255",
+			},
+			want: types.FilesystemUsage{TotalBytes: 10475520 * 4096, FreeBytes: 8126464 * 4096, AvailableBytes: 8126464 * 4096},
+			ok:   true,
+		},
+		{
+			name:  "reserved blocks",
+			lines: []string{"frsize: 1024", "blocks: 1000", "bfree: 300", "bavail: 250"},
+			want:  types.FilesystemUsage{TotalBytes: 1024000, FreeBytes: 307200, AvailableBytes: 256000},
+			ok:    true,
+		},
+		{
+			name:  "malformed lines ignored",
+			lines: []string{"libguestfs: warning", "frsize: 512", "blocks: two", "bfree: 2"},
+			want:  types.FilesystemUsage{FreeBytes: 1024},
+			ok:    true,
+		},
+		{
+			name:  "statvfs failed",
+			lines: []string{"libguestfs: error: statvfs: /data: No such file or directory"},
+		},
+		{
+			name:  "zero fragment size",
+			lines: []string{"frsize: 0", "blocks: 10"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, ok := parseStatvfs(tt.lines)
+			if ok != tt.ok || usage != tt.want {
+				t.Errorf("parseStatvfs() = %+v, %v, want %+v, %v", usage, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package inspection
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// guestfishPath is the guestfish executable used to read guest files (resolved from system PATH)
+const guestfishPath = "guestfish"
+
+// ReadFiles reads files from the guest filesystems of a VM snapshot using guestfish
+// Each path may be a file or a directory; directories are read recursively.
+// Symbolic links are returned with their link target as content.
+// The result maps guest file paths to their content. Paths that do not exist
+// in the guest are omitted from the result rather than returned as an error.
+func (i *VirtInspector) ReadFiles(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+	paths []string,
+) (map[string][]byte, error) {
+	if len(paths) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionCloser()
+
+	// guestfish copies the files next to it, on the host of the executor
+	executor := i.sessions.options.executor()
+	outputDir, err := executor.TempDir(ctx, "v2v-guest-files")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer trackTempPath(executor, outputDir)()
+
+	// Copy each path into its own numbered directory to avoid name collisions, created by the local
+	// shell of guestfish ("!" lines)
+	// A leading "-" makes guestfish ignore errors for paths missing in the guest
+	var script strings.Builder
+	copyDirs := make([]string, len(paths))
+	for idx := range paths {
+		copyDirs[idx] = shellQuote(filepath.Join(outputDir, strconv.Itoa(idx)))
+	}
+	fmt.Fprintf(&script, "!mkdir -- %s\n", strings.Join(copyDirs, " "))
+	for idx, guestPath := range paths {
+		copyDir := filepath.Join(outputDir, strconv.Itoa(idx))
+		fmt.Fprintf(&script, "-copy-out %s %s\n", guestfishQuote(guestPath), guestfishQuote(copyDir))
+	}
+
+	i.logger.WithFields(logrus.Fields{
+		"nbd_urls": nbdURLs,
+		"paths":    paths,
+	}).Info("Reading guest files on NBD")
+
+	if _, err := i.runGuestfish(ctx, nbdURLs, script.String()); err != nil {
+		return nil, err
+	}
+	copiedDir, removeCopied, err := executor.Fetch(ctx, outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch copied guest files: %w", err)
+	}
+	defer removeCopied()
+
+	files := make(map[string][]byte)
+	for idx, guestPath := range paths {
+		localDir := filepath.Join(copiedDir, strconv.Itoa(idx))
+		guestParent := path.Dir(guestPath)
+		err := filepath.WalkDir(localDir, func(localPath string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(localDir, localPath)
+			if err != nil {
+				return err
+			}
+			var content []byte
+			if d.Type()&fs.ModeSymlink != 0 {
+				// Guest symlinks point into the guest filesystem, so report their target instead
+				target, err := os.Readlink(localPath)
+				if err != nil {
+					return err
+				}
+				content = []byte(target)
+			} else {
+				content, err = os.ReadFile(localPath)
+				if err != nil {
+					return err
+				}
+			}
+			files[path.Join(guestParent, filepath.ToSlash(rel))] = content
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read copied guest files for %s: %w", guestPath, err)
+		}
+	}
+
+	return files, nil
+}
+
+// ListDirectory lists the entry names of a directory in the guest filesystems
+// A directory missing in the guest results in an empty list rather than an error
+func (i *VirtInspector) ListDirectory(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+	dir string,
+) ([]string, error) {
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionCloser()
+
+	output, err := i.runGuestfish(ctx, nbdURLs, fmt.Sprintf("-ls %s\n", guestfishQuote(dir)))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			entries = append(entries, name)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// existsMarker separates the result for individual paths in guestfish output
+const existsMarker = "@@@exists "
+
+// PathsExist reports whether the given paths exist in the guest filesystems without copying them
+// This is useful for large files (e.g., databases) where only presence matters.
+func (i *VirtInspector) PathsExist(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+	paths []string,
+) (map[string]bool, error) {
+	result := make(map[string]bool, len(paths))
+	if len(paths) == 0 {
+		return result, nil
+	}
+
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionCloser()
+
+	var script strings.Builder
+	for _, guestPath := range paths {
+		fmt.Fprintf(&script, "echo %s\n", guestfishQuote(existsMarker+guestPath))
+		fmt.Fprintf(&script, "-exists %s\n", guestfishQuote(guestPath))
+	}
+	output, err := i.runGuestfish(ctx, nbdURLs, script.String())
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := splitMarkedOutput(output, existsMarker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read guestfish output: %w", err)
+	}
+	for _, guestPath := range paths {
+		lines := sections[guestPath]
+		result[guestPath] = len(lines) > 0 && strings.TrimSpace(lines[0]) == "true"
+	}
+	return result, nil
+}
+
+// runGuestfish runs a guestfish script read-only against the NBD URL with guest filesystems mounted
+// Returns the standard output of the script; transient failures are retried with the session retry policy
+func (i *VirtInspector) runGuestfish(ctx context.Context, nbdURLs []string, script string) ([]byte, error) {
+	startedAt := time.Now()
+	var output []byte
+	err := i.sessions.options.Retry.do(ctx, i.logger, "guestfish", func() (err error) {
+		output, err = i.runGuestfishOnce(ctx, nbdURLs, script)
+		return err
+	})
+	i.options.Metrics.observeInspection("guestfish", time.Since(startedAt), err)
+	return output, err
+}
+
+// runGuestfishOnce runs a guestfish script once against the NBD URL
+func (i *VirtInspector) runGuestfishOnce(ctx context.Context, nbdURLs []string, script string) ([]byte, error) {
+	runCtx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+
+	executor := i.sessions.options.executor()
+	if len(i.options.Keys) > 0 {
+		if err := requireVersion(runCtx, executor, "decryption keys", guestfishPath, minKeySelectorVersion); err != nil {
+			return nil, err
+		}
+	}
+	keys, removeKeys, err := keyArgs(runCtx, executor, i.options.Keys)
+	if err != nil {
+		return nil, err
+	}
+	defer removeKeys()
+
+	args := append([]string{"--ro", "--format=raw"}, diskArgs(nbdURLs)...)
+	args = append(args, keys...)
+	args = append(args, "-i")
+	guestfishCmd := executor.Command(runCtx, guestfishPath, args...)
+	guestfishCmd.Env = libguestfsEnv()
+	emit(ctx, InspectionEvent{Phase: PhaseToolStarted, Tool: "guestfish"})
+	guestfishCmd.Stdin = strings.NewReader(script)
+	stderr := newOutputBuffer(maxToolOutput)
+	guestfishCmd.Stderr = stderr
+
+	output, err := guestfishCmd.Output()
+	if err != nil {
+		toolErr := newToolError("guestfish", err, stderr)
+		i.logger.WithFields(logrus.Fields{
+			"stderr":    toolErr.Stderr,
+			"exit_code": toolErr.ExitCode,
+			"nbd_urls":  nbdURLs,
+			"command":   guestfishPath,
+			"args":      args,
+		}).Error("guestfish failed")
+		return nil, i.secrets.redactError(toolErr)
+	}
+	return output, nil
+}
+
+// splitMarkedOutput splits guestfish output into sections started by lines beginning with marker
+// The result maps the text following the marker to the non-empty lines of its section
+func splitMarkedOutput(output []byte, marker string) (map[string][]string, error) {
+	sections := make(map[string][]string)
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, marker); ok {
+			current = name
+			sections[current] = []string{}
+			continue
+		}
+		if current != "" && line != "" {
+			sections[current] = append(sections[current], line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// guestfishQuote quotes s as a double-quoted argument of a guestfish script
+// guestfish only understands C escapes, so control characters are written as \xHH and other bytes
+// (including UTF-8 sequences) are kept as they are
+func guestfishQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for idx := 0; idx < len(s); idx++ {
+		switch c := s[idx]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
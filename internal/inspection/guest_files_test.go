@@ -0,0 +1,70 @@
+package inspection
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGuestfishQuote(t *testing.T) {
+	tests := map[string]string{
+		"/etc/fstab":                    `"/etc/fstab"`,
+		"/boot/grub2/grub.cfg":          `"/boot/grub2/grub.cfg"`,
+		"/home/user/My Documents":       `"/home/user/My Documents"`,
+		`/srv/"quoted"`:                 `"/srv/\"quoted\""`,
+		`C:\Windows\System32`:           `"C:\\Windows\\System32"`,
+		"/tmp/it's":                     `"/tmp/it's"`,
+		"/tmp/$HOME `id`":               "\"/tmp/$HOME `id`\"",
+		"/tmp/line\nbreak\ttab":         `"/tmp/line\x0abreak\x09tab"`,
+		"/srv/données/日本":               `"/srv/données/日本"`,
+		"@@@exists /var/lib/pgsql/data": `"@@@exists /var/lib/pgsql/data"`,
+		"":                              `""`,
+	}
+	for input, want := range tests {
+		if got := guestfishQuote(input); got != want {
+			t.Errorf("guestfishQuote(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestSplitMarkedOutput(t *testing.T) {
+	output := []byte(`ignored before the first marker
+@@@exists /etc/fstab
+true
+@@@exists /var/lib/pgsql
+
+false
+@@@exists /empty
+@@@exists path with spaces
+  indented line
+`)
+	sections, err := splitMarkedOutput(output, existsMarker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"/etc/fstab":       {"true"},
+		"/var/lib/pgsql":   {"false"},
+		"/empty":           {},
+		"path with spaces": {"  indented line"},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("splitMarkedOutput() = %q, want %d sections", sections, len(want))
+	}
+	for name, lines := range want {
+		got, found := sections[name]
+		if !found || strings.Join(got, "\n") != strings.Join(lines, "\n") || (got == nil) {
+			t.Errorf("section %q = %q, want %q", name, got, lines)
+		}
+	}
+}
+
+func TestSplitMarkedOutputLongLine(t *testing.T) {
+	line := strings.Repeat("x", 512*1024)
+	sections, err := splitMarkedOutput([]byte("@@@df /\n"+line+"\n"), "@@@df ")
+	if err != nil || len(sections["/"]) != 1 || len(sections["/"][0]) != len(line) {
+		t.Errorf("splitMarkedOutput() error = %v, lost the long line", err)
+	}
+	if _, err := splitMarkedOutput([]byte("@@@df /\n"+strings.Repeat("x", 2*1024*1024)), "@@@df "); err == nil {
+		t.Error("splitMarkedOutput() accepted a line above the 1 MiB limit")
+	}
+}
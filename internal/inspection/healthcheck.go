@@ -0,0 +1,149 @@
+package inspection
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// HealthcheckConfig is the environment verified by Healthcheck
+type HealthcheckConfig struct {
+	VirtInspectorPath    string        // Uses system PATH if empty
+	VirtV2vInspectorPath string        // Uses system PATH if empty
+	VDDK                 VDDKOptions   // VDDK library location and vCenter certificate verification
+	VCenterURL           string        // vCenter to connect to, not checked if empty
+	Timeout              time.Duration // Limit of each check (30s if zero)
+	Executor             Executor      // Runs the tools (on the local host if nil)
+}
+
+// ComponentHealth is the health of a component needed by inspections
+type ComponentHealth struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"` // Empty if the component is healthy
+}
+
+// HealthReport is the result of Healthcheck
+type HealthReport struct {
+	Healthy    bool              `json:"healthy"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// Err returns an error listing the unhealthy components, nil if all are healthy
+func (r *HealthReport) Err() error {
+	var errs []error
+	for _, component := range r.Components {
+		if component.Error != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", component.Name, component.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Healthcheck verifies that virt-inspector, virt-v2v-inspector, nbdkit, its VDDK plugin and the VDDK library
+// are present and runnable, and that vCenter accepts TLS connections with the certificate verification of the sessions
+// Deployments can run it at startup to fail before accepting validations that cannot succeed
+func Healthcheck(ctx context.Context, config HealthcheckConfig) *HealthReport {
+	if config.VirtInspectorPath == "" {
+		config.VirtInspectorPath = "virt-inspector"
+	}
+	if config.VirtV2vInspectorPath == "" {
+		config.VirtV2vInspectorPath = "virt-v2v-inspector"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	executor := executorOrLocal(config.Executor)
+
+	checks := []componentCheck{
+		{"virt-inspector", func(ctx context.Context) (string, error) {
+			return toolVersion(ctx, executor, config.VirtInspectorPath, "--version")
+		}},
+		{"virt-v2v-inspector", func(ctx context.Context) (string, error) {
+			return toolVersion(ctx, executor, config.VirtV2vInspectorPath, "--version")
+		}},
+		{"nbdkit", func(ctx context.Context) (string, error) {
+			return toolVersion(ctx, executor, "nbdkit", "--version")
+		}},
+		{"vddk-library", func(ctx context.Context) (string, error) {
+			library, err := executor.VDDKLibrary(config.VDDK.LibDir)
+			if err != nil {
+				return "", err
+			}
+			return library.Version, nil
+		}},
+		{"nbdkit-vddk-plugin", func(ctx context.Context) (string, error) {
+			return checkVDDKPlugin(ctx, executor, config.VDDK)
+		}},
+	}
+	if config.VCenterURL != "" {
+		checks = append(checks, componentCheck{"vcenter", func(ctx context.Context) (string, error) {
+			return "", checkVCenter(ctx, config.VCenterURL, config.VDDK)
+		}})
+	}
+
+	report := &HealthReport{Healthy: true}
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, config.Timeout)
+		version, err := c.check(checkCtx)
+		cancel()
+		component := ComponentHealth{Name: c.name, Version: version}
+		if err != nil {
+			component.Error = redact(err.Error())
+			report.Healthy = false
+		}
+		report.Components = append(report.Components, component)
+	}
+	return report
+}
+
+// componentCheck checks a component and returns its version
+type componentCheck struct {
+	name  string
+	check func(ctx context.Context) (string, error)
+}
+
+// checkVDDKPlugin loads the VDDK plugin of nbdkit with the VDDK library and returns the plugin version
+func checkVDDKPlugin(ctx context.Context, executor Executor, vddk VDDKOptions) (string, error) {
+	library, err := executor.VDDKLibrary(vddk.LibDir)
+	if err != nil {
+		return "", err
+	}
+	cmd := executor.Command(ctx, "nbdkit", "vddk", "libdir="+library.Dir, "--dump-plugin")
+	stderr := newOutputBuffer(maxToolOutput)
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", newToolError("nbdkit", err, stderr)
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if version, found := strings.CutPrefix(line, "version="); found {
+			return version, nil
+		}
+	}
+	return "", nil
+}
+
+// checkVCenter opens a TLS connection to vCenter on port 443, like VDDK sessions, verifying its certificate
+// with the thumbprint options of the sessions
+func checkVCenter(ctx context.Context, vcenterURL string, vddk VDDKOptions) error {
+	return dialVCenter(ctx, extractHostname(vcenterURL), "443", vddk)
+}
+
+// dialVCenter opens a TLS connection to port of the vCenter host, verifying its certificate with vddk
+func dialVCenter(ctx context.Context, vcenterHost string, port string, vddk VDDKOptions) error {
+	config, err := vddk.tlsConfig(vcenterHost)
+	if err != nil {
+		return err
+	}
+	dialer := &tls.Dialer{Config: config}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(vcenterHost, port))
+	if err != nil {
+		return fmt.Errorf("failed to connect to vCenter: %w", err)
+	}
+	return conn.Close()
+}
@@ -0,0 +1,63 @@
+package inspection
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/pem"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDialVCenter(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Rejected handshakes are expected
+	server.StartTLS()
+	defer server.Close()
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := server.Certificate()
+	digest := sha1.Sum(cert.Raw)
+	thumbprint := formatThumbprint(digest[:])
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	otherDigest := sha1.Sum([]byte("other certificate"))
+
+	tests := []struct {
+		name    string
+		vddk    VDDKOptions
+		wantErr string
+	}{
+		{name: "matching thumbprint", vddk: VDDKOptions{Thumbprint: strings.ToLower(thumbprint)}},
+		{name: "other thumbprint", vddk: VDDKOptions{Thumbprint: formatThumbprint(otherDigest[:])}, wantErr: "does not match"},
+		{name: "invalid thumbprint", vddk: VDDKOptions{Thumbprint: "AB:CD"}, wantErr: "invalid vCenter thumbprint"},
+		{name: "trusted CA", vddk: VDDKOptions{ComputeThumbprint: true, CACertFile: caCertFile}},
+		{name: "untrusted CA", vddk: VDDKOptions{ComputeThumbprint: true}, wantErr: "certificate"},
+		{name: "required thumbprint", vddk: VDDKOptions{RequireThumbprint: true}, wantErr: "thumbprint is required"},
+		{name: "no verification", vddk: VDDKOptions{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dialVCenter(context.Background(), host, port, tt.vddk)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("dialVCenter() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("dialVCenter() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package inspection
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// initramfsMarker separates the listings of individual initramfs images in guestfish output
+const initramfsMarker = "@@@initramfs "
+
+// ListInitramfsFiles lists the contents of every initramfs image found in the guest /boot directory
+// The result maps initramfs paths (e.g., "/boot/initramfs-5.14.0.img") to the files they contain.
+// kdump and rescue images are skipped.
+func (i *VirtInspector) ListInitramfsFiles(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) (map[string][]string, error) {
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionCloser()
+
+	// First pass: find initramfs images in /boot
+	output, err := i.runGuestfish(ctx, nbdURLs, "-ls /boot\n")
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if isInitramfsImage(name) {
+			images = append(images, path.Join("/boot", name))
+		}
+	}
+
+	result := make(map[string][]string)
+	if len(images) == 0 {
+		return result, nil
+	}
+
+	i.logger.WithFields(logrus.Fields{
+		"nbd_urls": nbdURLs,
+		"images":   images,
+	}).Info("Listing initramfs contents on NBD")
+
+	// Second pass: list each image, separated by markers so the output can be split per image
+	var script strings.Builder
+	for _, image := range images {
+		fmt.Fprintf(&script, "echo %s\n", guestfishQuote(initramfsMarker+image))
+		fmt.Fprintf(&script, "-initrd-list %s\n", guestfishQuote(image))
+	}
+	output, err = i.runGuestfish(ctx, nbdURLs, script.String())
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := splitMarkedOutput(output, initramfsMarker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read initramfs listing: %w", err)
+	}
+	for image, lines := range sections {
+		result[image] = lines
+	}
+
+	return result, nil
+}
+
+// isInitramfsImage returns true for initramfs image names used by dracut, mkinitrd and initramfs-tools
+func isInitramfsImage(name string) bool {
+	if !strings.HasPrefix(name, "initramfs-") && !strings.HasPrefix(name, "initrd.img-") && !strings.HasPrefix(name, "initrd-") {
+		return false
+	}
+	if strings.Contains(name, "kdump") || strings.Contains(name, "rescue") {
+		return false
+	}
+	return !strings.HasSuffix(name, ".old")
+}
@@ -0,0 +1,159 @@
+package inspection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// lvmMarker separates the sections of the LVM layout guestfish output
+const lvmMarker = "@@@lvm "
+
+// lvmReportArgs are the lvm arguments printing a JSON report with sizes in bytes
+// Warnings about missing physical volumes go to stderr, which is discarded so the report stays valid JSON
+const lvmReportArgs = "--reportformat json --units b --nosuffix 2>/dev/null"
+
+// LVMLayout reports the LVM physical and logical volumes of the VM snapshot using guestfish
+// The libguestfs API does not report thin pool usage or missing physical volumes, so lvm runs in the
+// appliance with debug sh. Guests without LVM have an empty layout
+func (i *VirtInspector) LVMLayout(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) (*types.LVMLayout, error) {
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionCloser()
+
+	i.logger.WithFields(logrus.Fields{
+		"nbd_urls": nbdURLs,
+	}).Info("Reading LVM layout on NBD")
+
+	var script strings.Builder
+	fmt.Fprintf(&script, "echo %s\n", guestfishQuote(lvmMarker+"devices"))
+	script.WriteString("list-devices\n")
+	fmt.Fprintf(&script, "echo %s\n", guestfishQuote(lvmMarker+"pvs"))
+	fmt.Fprintf(&script, "debug sh %s\n", guestfishQuote("lvm pvs -o pv_name,vg_name,pv_uuid,pv_attr "+lvmReportArgs))
+	fmt.Fprintf(&script, "echo %s\n", guestfishQuote(lvmMarker+"lvs"))
+	fmt.Fprintf(&script, "debug sh %s\n", guestfishQuote("lvm lvs -a -o vg_name,lv_name,lv_attr,lv_size,pool_lv,data_percent,metadata_percent "+lvmReportArgs))
+	output, err := i.runGuestfish(ctx, nbdURLs, script.String())
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := splitMarkedOutput(output, lvmMarker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LVM layout output: %w", err)
+	}
+	return parseLVMLayout(sections["devices"], strings.Join(sections["pvs"], "\n"), strings.Join(sections["lvs"], "\n"))
+}
+
+// lvmReport is the JSON report of lvm pvs and lvs
+type lvmReport struct {
+	Report []struct {
+		PV []map[string]string `json:"pv"`
+		LV []map[string]string `json:"lv"`
+	} `json:"report"`
+}
+
+// parseLVMLayout parses the appliance devices and the JSON reports of lvm pvs and lvs
+func parseLVMLayout(devices []string, pvsJSON string, lvsJSON string) (*types.LVMLayout, error) {
+	layout := &types.LVMLayout{
+		PhysicalVolumes: []types.LVMPhysicalVolume{},
+		LogicalVolumes:  []types.LVMLogicalVolume{},
+	}
+	for _, device := range devices {
+		if device = strings.TrimSpace(device); device != "" {
+			layout.Disks = append(layout.Disks, device)
+		}
+	}
+
+	pvs, err := parseLVMReport(pvsJSON, "pvs")
+	if err != nil {
+		return nil, err
+	}
+	for _, report := range pvs.Report {
+		for _, pv := range report.PV {
+			// pv_attr is "<allocatable><exported><missing>", e.g. "a--" or "a-m"
+			missing := len(pv["pv_attr"]) > 2 && pv["pv_attr"][2] == 'm'
+			volume := types.LVMPhysicalVolume{
+				VolumeGroup: pv["vg_name"],
+				UUID:        pv["pv_uuid"],
+				Missing:     missing,
+			}
+			if !missing {
+				volume.Device = pv["pv_name"]
+				volume.Disk = deviceDisk(layout.Disks, volume.Device)
+			}
+			layout.PhysicalVolumes = append(layout.PhysicalVolumes, volume)
+		}
+	}
+
+	lvs, err := parseLVMReport(lvsJSON, "lvs")
+	if err != nil {
+		return nil, err
+	}
+	for _, report := range lvs.Report {
+		for _, lv := range report.LV {
+			size, _ := strconv.ParseUint(lv["lv_size"], 10, 64)
+			layout.LogicalVolumes = append(layout.LogicalVolumes, types.LVMLogicalVolume{
+				VolumeGroup:     lv["vg_name"],
+				Name:            strings.Trim(lv["lv_name"], "[]"), // Hidden volumes such as pool metadata are bracketed
+				Attributes:      lv["lv_attr"],
+				SizeBytes:       size,
+				Pool:            lv["pool_lv"],
+				DataPercent:     parsePercent(lv["data_percent"]),
+				MetadataPercent: parsePercent(lv["metadata_percent"]),
+			})
+		}
+	}
+	return layout, nil
+}
+
+// parseLVMReport parses the JSON report of an lvm command, an empty output is an empty report
+func parseLVMReport(output string, command string) (*lvmReport, error) {
+	var report lvmReport
+	if strings.TrimSpace(output) == "" {
+		return &report, nil
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse lvm %s report: %w", command, err)
+	}
+	return &report, nil
+}
+
+// parsePercent parses a percentage reported by lvm, nil if not reported
+func parsePercent(value string) *float64 {
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &percent
+}
+
+// deviceDisk returns the disk of disks holding device, a disk or one of its partitions
+func deviceDisk(disks []string, device string) string {
+	if slices.Contains(disks, device) {
+		return device
+	}
+	// Partitions are the disk name followed by the partition number (e.g., "/dev/sda2")
+	partitionDisk := strings.TrimRight(device, "0123456789")
+	if slices.Contains(disks, partitionDisk) {
+		return partitionDisk
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package inspection
+
+import (
+	"testing"
+)
+
+func TestParseLVMLayout(t *testing.T) {
+	devices := []string{"/dev/sda", "/dev/sdb", ""}
+	pvs := `{
+  "report": [
+    {
+      "pv": [
+        {"pv_name":"/dev/sda2", "vg_name":"rhel", "pv_uuid":"aaa", "pv_attr":"a--"},
+        {"pv_name":"/dev/sdb", "vg_name":"data", "pv_uuid":"bbb", "pv_attr":"a--"},
+        {"pv_name":"[unknown]", "vg_name":"data", "pv_uuid":"ccc", "pv_attr":"a-m"}
+      ]
+    }
+  ]
+}`
+	lvs := `{
+  "report": [
+    {
+      "lv": [
+        {"vg_name":"rhel", "lv_name":"root", "lv_attr":"-wi-ao----", "lv_size":"42949672960", "pool_lv":"", "data_percent":"", "metadata_percent":""},
+        {"vg_name":"data", "lv_name":"pool", "lv_attr":"twi-aotzp-", "lv_size":"107374182400", "pool_lv":"", "data_percent":"61.20", "metadata_percent":"87.50"},
+        {"vg_name":"data", "lv_name":"[pool_tmeta]", "lv_attr":"ewi-ao----", "lv_size":"1073741824", "pool_lv":"", "data_percent":"", "metadata_percent":""}
+      ]
+    }
+  ]
+}`
+
+	layout, err := parseLVMLayout(devices, pvs, lvs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layout.Disks) != 2 {
+		t.Errorf("Disks = %v, want 2 disks", layout.Disks)
+	}
+
+	wantPVs := []struct {
+		device, disk, vg string
+		missing          bool
+	}{
+		{"/dev/sda2", "/dev/sda", "rhel", false},
+		{"/dev/sdb", "/dev/sdb", "data", false},
+		{"", "", "data", true},
+	}
+	if len(layout.PhysicalVolumes) != len(wantPVs) {
+		t.Fatalf("PhysicalVolumes = %+v, want %d", layout.PhysicalVolumes, len(wantPVs))
+	}
+	for idx, want := range wantPVs {
+		pv := layout.PhysicalVolumes[idx]
+		if pv.Device != want.device || pv.Disk != want.disk || pv.VolumeGroup != want.vg || pv.Missing != want.missing {
+			t.Errorf("PhysicalVolumes[%d] = %+v, want %+v", idx, pv, want)
+		}
+	}
+
+	if len(layout.LogicalVolumes) != 3 {
+		t.Fatalf("LogicalVolumes = %+v, want 3", layout.LogicalVolumes)
+	}
+	root, pool, meta := layout.LogicalVolumes[0], layout.LogicalVolumes[1], layout.LogicalVolumes[2]
+	if root.SizeBytes != 40<<30 || root.MetadataPercent != nil || root.ThinPool() {
+		t.Errorf("root = %+v", root)
+	}
+	if !pool.ThinPool() || !pool.Partial() || pool.MetadataPercent == nil || *pool.MetadataPercent != 87.5 {
+		t.Errorf("pool = %+v", pool)
+	}
+	if meta.Name != "pool_tmeta" {
+		t.Errorf("hidden volume name = %q, want pool_tmeta", meta.Name)
+	}
+}
+
+func TestParseLVMLayoutWithoutLVM(t *testing.T) {
+	layout, err := parseLVMLayout([]string{"/dev/sda"}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layout.PhysicalVolumes) != 0 || len(layout.LogicalVolumes) != 0 {
+		t.Errorf("layout = %+v, want no volumes", layout)
+	}
+}
+
+func TestParseLVMLayoutInvalidReport(t *testing.T) {
+	if _, err := parseLVMLayout(nil, "  WARNING: not json", ""); err == nil {
+		t.Fatal("parseLVMLayout() accepted an invalid report")
+	}
+}
+
+func TestDeviceDisk(t *testing.T) {
+	disks := []string{"/dev/sda", "/dev/sdb", "/dev/sdaa"}
+	tests := map[string]string{
+		"/dev/sda":   "/dev/sda",
+		"/dev/sda1":  "/dev/sda",
+		"/dev/sdb12": "/dev/sdb",
+		"/dev/sdaa3": "/dev/sdaa",
+		"/dev/sdc1":  "",
+		"/dev/md0":   "",
+	}
+	for device, want := range tests {
+		if got := deviceDisk(disks, device); got != want {
+			t.Errorf("deviceDisk(%q) = %q, want %q", device, got, want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package inspection
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Inspection steps that can fail, reported as failure reasons
+const (
+	reasonSession  = "session"  // Opening the NBD session or the vCenter connection failed
+	reasonTool     = "tool"     // The inspection tool failed
+	reasonParse    = "parse"    // The tool output could not be parsed
+	reasonTimeout  = "timeout"  // A timeout expired
+	reasonCanceled = "canceled" // The caller canceled the inspection
+	reasonOther    = "other"
+)
+
+// inspectionBuckets are the upper bounds in seconds of the inspection duration histograms
+var inspectionBuckets = []float64{5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+// sessionStartBuckets are the upper bounds in seconds of the NBD session startup histogram
+var sessionStartBuckets = []float64{0.5, 1, 2, 5, 10, 30, 60, 120, 300}
+
+// Metrics collects metrics of inspections and NBD sessions, it is a Prometheus collector to register with
+// the registry of the metrics endpoint:
+//
+//	prometheus.MustRegister(metrics)
+//	http.Handle("/metrics", promhttp.Handler())
+//
+// All methods are safe for concurrent use, a nil Metrics collects nothing
+type Metrics struct {
+	inspections  *prometheus.CounterVec   // Tool, result
+	failures     *prometheus.CounterVec   // Tool, reason
+	durations    *prometheus.HistogramVec // Tool
+	sessionStart *prometheus.HistogramVec // Result
+	sessionsOpen prometheus.Gauge
+}
+
+// NewMetrics creates a new Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		inspections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "v2v_inspections_total",
+			Help: "Inspections and guestfish runs, by tool and result.",
+		}, []string{"tool", "result"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "v2v_inspection_failures_total",
+			Help: "Failed inspections and guestfish runs, by tool and failed step.",
+		}, []string{"tool", "reason"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "v2v_inspection_duration_seconds",
+			Help:    "Duration of inspections and guestfish runs, including opening the NBD session of inspections.",
+			Buckets: inspectionBuckets,
+		}, []string{"tool"}),
+		sessionStart: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "v2v_nbd_session_start_seconds",
+			Help:    "Time taken to open the NBD session of a VM snapshot, by result.",
+			Buckets: sessionStartBuckets,
+		}, []string{"result"}),
+		sessionsOpen: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "v2v_nbd_sessions_open",
+			Help: "NBD sessions of VM snapshots currently open.",
+		}),
+	}
+}
+
+// Describe sends the descriptors of the metrics to ch
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range m.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect sends the current values of the metrics to ch
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range m.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	if m == nil {
+		return nil
+	}
+	return []prometheus.Collector{m.inspections, m.failures, m.durations, m.sessionStart, m.sessionsOpen}
+}
+
+// observeInspection records an inspection run by tool and its outcome
+func (m *Metrics) observeInspection(tool string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+		m.failures.WithLabelValues(tool, failureReason(err)).Inc()
+	}
+	m.inspections.WithLabelValues(tool, result).Inc()
+	m.durations.WithLabelValues(tool).Observe(duration.Seconds())
+}
+
+// observeSessionStart records the time taken to open the NBD session of a VM snapshot
+func (m *Metrics) observeSessionStart(duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.sessionStart.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// sessionOpened and sessionClosed track the NBD sessions currently open
+func (m *Metrics) sessionOpened() {
+	if m != nil {
+		m.sessionsOpen.Inc()
+	}
+}
+
+func (m *Metrics) sessionClosed() {
+	if m != nil {
+		m.sessionsOpen.Dec()
+	}
+}
+
+// stepError marks the step of an inspection that failed, reported as the failure reason in metrics
+type stepError struct {
+	step string
+	err  error
+}
+
+func (e *stepError) Error() string { return e.err.Error() }
+func (e *stepError) Unwrap() error { return e.err }
+
+// failedStep wraps err with the step of the inspection that failed, nil if err is nil
+func failedStep(step string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stepError{step: step, err: err}
+}
+
+// failureReason returns the failure reason of an inspection error
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return reasonTimeout
+	case errors.Is(err, context.Canceled):
+		return reasonCanceled
+	}
+	var step *stepError
+	if errors.As(err, &step) {
+		return step.step
+	}
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return reasonTool
+	}
+	return reasonOther
+}
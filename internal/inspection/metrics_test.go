@@ -0,0 +1,87 @@
+package inspection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	metrics := NewMetrics()
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(metrics); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	metrics.observeInspection("virt-inspector", 42*time.Second, nil)
+	metrics.observeInspection("virt-inspector", 3*time.Second, failedStep(reasonSession, errors.New("vCenter unreachable")))
+	metrics.observeInspection("guestfish", time.Second, context.DeadlineExceeded)
+	metrics.observeSessionStart(1500*time.Millisecond, nil)
+	metrics.sessionOpened()
+	metrics.sessionOpened()
+	metrics.sessionClosed()
+
+	want := `
+# HELP v2v_inspection_failures_total Failed inspections and guestfish runs, by tool and failed step.
+# TYPE v2v_inspection_failures_total counter
+v2v_inspection_failures_total{reason="session",tool="virt-inspector"} 1
+v2v_inspection_failures_total{reason="timeout",tool="guestfish"} 1
+# HELP v2v_inspections_total Inspections and guestfish runs, by tool and result.
+# TYPE v2v_inspections_total counter
+v2v_inspections_total{result="failure",tool="guestfish"} 1
+v2v_inspections_total{result="failure",tool="virt-inspector"} 1
+v2v_inspections_total{result="success",tool="virt-inspector"} 1
+# HELP v2v_nbd_sessions_open NBD sessions of VM snapshots currently open.
+# TYPE v2v_nbd_sessions_open gauge
+v2v_nbd_sessions_open 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"v2v_inspections_total", "v2v_inspection_failures_total", "v2v_nbd_sessions_open"); err != nil {
+		t.Error(err)
+	}
+	if count := testutil.CollectAndCount(metrics, "v2v_inspection_duration_seconds"); count != 2 {
+		t.Errorf("inspection duration series = %d, want one per tool", count)
+	}
+	if count := testutil.CollectAndCount(metrics, "v2v_nbd_session_start_seconds"); count != 1 {
+		t.Errorf("session start series = %d, want 1", count)
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var metrics *Metrics
+	metrics.observeInspection("virt-inspector", time.Second, nil)
+	metrics.observeSessionStart(time.Second, nil)
+	metrics.sessionOpened()
+	metrics.sessionClosed()
+	if count := testutil.CollectAndCount(metrics); count != 0 {
+		t.Errorf("nil Metrics collected %d series", count)
+	}
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{context.DeadlineExceeded, reasonTimeout},
+		{fmt.Errorf("inspection: %w", context.Canceled), reasonCanceled},
+		{failedStep(reasonParse, errors.New("XML syntax error")), reasonParse},
+		{failedStep(reasonSession, context.DeadlineExceeded), reasonTimeout},
+		{fmt.Errorf("run: %w", &ToolError{Tool: "guestfish"}), reasonTool},
+		{errors.New("unexpected"), reasonOther},
+	}
+	for _, tt := range tests {
+		if got := failureReason(tt.err); got != tt.want {
+			t.Errorf("failureReason(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+	if failedStep(reasonTool, nil) != nil {
+		t.Error("failedStep(nil) != nil")
+	}
+}
@@ -0,0 +1,236 @@
+package inspection
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// NBD protocol constants used by the readiness probe (see the NBD protocol specification)
+const (
+	nbdMagic             = 0x4e42444d41474943 // "NBDMAGIC"
+	nbdOptionMagic       = 0x49484156454f5054 // "IHAVEOPT"
+	nbdOptionReplyMagic  = 0x0003e889045565a9
+	nbdRequestMagic      = 0x25609513
+	nbdFlagFixedNewstyle = 1 << 0
+	nbdFlagNoZeroes      = 1 << 1
+	nbdOptGo             = 7
+	nbdRepAck            = 1
+	nbdRepInfo           = 3
+	nbdRepFlagError      = 1 << 31
+	nbdInfoExport        = 0
+	nbdCmdDisc           = 2
+
+	// nbdProbeInterval is the delay between connection attempts while the server is not listening
+	nbdProbeInterval = 100 * time.Millisecond
+)
+
+// nbdReplyErrors names the NBD option reply error types
+var nbdReplyErrors = map[uint32]string{
+	nbdRepFlagError | 1: "unsupported option",
+	nbdRepFlagError | 2: "denied by server policy",
+	nbdRepFlagError | 3: "invalid option",
+	nbdRepFlagError | 4: "not supported on this platform",
+	nbdRepFlagError | 5: "TLS required",
+	nbdRepFlagError | 6: "unknown export",
+	nbdRepFlagError | 7: "server shutting down",
+	nbdRepFlagError | 8: "block size constraints required",
+	nbdRepFlagError | 9: "request too big",
+}
+
+// NBDProtocolError is an error reported by an NBD server during the handshake
+type NBDProtocolError struct {
+	ReplyType uint32
+	Message   string // Message sent by the server, if any
+}
+
+func (e *NBDProtocolError) Error() string {
+	name, found := nbdReplyErrors[e.ReplyType]
+	if !found {
+		name = fmt.Sprintf("error reply 0x%x", e.ReplyType)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("NBD server error: %s: %s", name, e.Message)
+	}
+	return fmt.Sprintf("NBD server error: %s", name)
+}
+
+// waitForNBD waits until the NBD server of nbdURL completes a handshake for its export
+// Connection attempts are retried while the server is not listening yet and exited reports no
+// error; handshake failures are returned immediately
+// exited returns a non-nil error once the server process has exited (can be nil)
+// The server is reached through executor, which runs it
+func waitForNBD(ctx context.Context, executor Executor, nbdURL string, timeout time.Duration, exited func() error) (uint64, error) {
+	network, address, export, err := parseNBDURL(nbdURL)
+	if err != nil {
+		return 0, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		if exited != nil {
+			if err := exited(); err != nil {
+				return 0, err
+			}
+		}
+
+		conn, err := executor.Dial(waitCtx, network, address)
+		if err == nil {
+			size, err := probeNBD(waitCtx, conn, export)
+			conn.Close()
+			return size, err
+		}
+		// The dialer can report the timeout before waitCtx is done, it is reported below as the wait timeout
+		if waitCtx.Err() == nil && !isNotListening(err) && !errors.Is(err, context.DeadlineExceeded) {
+			return 0, fmt.Errorf("failed to connect to NBD server %s: %w", address, err)
+		}
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			return 0, fmt.Errorf("NBD server %s not listening after %v", address, timeout)
+		case <-time.After(nbdProbeInterval):
+		}
+	}
+}
+
+// probeNBD performs the fixed newstyle handshake and opens the export with NBD_OPT_GO, which makes
+// the server open its backend (e.g., the VDDK connection to vCenter), then disconnects
+// Returns the export size
+// The handshake is implemented here rather than with libnbd or a Go NBD client library: the probe only needs
+// NBD_OPT_GO, over connections dialed by the executor (e.g., forwarded over SSH) that libnbd cannot use, and
+// libnbd would add a cgo dependency on the host of the validation service
+func probeNBD(ctx context.Context, conn net.Conn, export string) (uint64, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	// The connection is also closed when ctx ends, in case its deadline is not enforced
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	size, err := handshakeNBD(conn, export)
+	if err != nil && ctx.Err() != nil {
+		return 0, fmt.Errorf("NBD handshake not completed: %w", ctx.Err())
+	}
+	return size, err
+}
+
+// handshakeNBD performs the handshake of probeNBD on conn
+func handshakeNBD(conn net.Conn, export string) (uint64, error) {
+
+	var greeting struct {
+		Magic       uint64
+		OptionMagic uint64
+		Flags       uint16
+	}
+	if err := binary.Read(conn, binary.BigEndian, &greeting); err != nil {
+		return 0, fmt.Errorf("failed to read NBD greeting: %w", err)
+	}
+	if greeting.Magic != nbdMagic || greeting.OptionMagic != nbdOptionMagic {
+		return 0, fmt.Errorf("not an NBD newstyle server (magic 0x%x 0x%x)", greeting.Magic, greeting.OptionMagic)
+	}
+	if greeting.Flags&nbdFlagFixedNewstyle == 0 {
+		return 0, fmt.Errorf("NBD server does not support the fixed newstyle handshake")
+	}
+	clientFlags := uint32(nbdFlagFixedNewstyle)
+	if greeting.Flags&nbdFlagNoZeroes != 0 {
+		clientFlags |= nbdFlagNoZeroes
+	}
+
+	// NBD_OPT_GO data: export name length, export name, number of info requests (none)
+	data := binary.BigEndian.AppendUint32(nil, uint32(len(export)))
+	data = append(data, export...)
+	data = binary.BigEndian.AppendUint16(data, 0)
+
+	request := binary.BigEndian.AppendUint32(nil, clientFlags)
+	request = binary.BigEndian.AppendUint64(request, nbdOptionMagic)
+	request = binary.BigEndian.AppendUint32(request, nbdOptGo)
+	request = binary.BigEndian.AppendUint32(request, uint32(len(data)))
+	request = append(request, data...)
+	if _, err := conn.Write(request); err != nil {
+		return 0, fmt.Errorf("failed to send NBD handshake: %w", err)
+	}
+
+	var size uint64
+	for {
+		var reply struct {
+			Magic  uint64
+			Option uint32
+			Type   uint32
+			Length uint32
+		}
+		if err := binary.Read(conn, binary.BigEndian, &reply); err != nil {
+			return 0, fmt.Errorf("failed to read NBD option reply: %w", err)
+		}
+		if reply.Magic != nbdOptionReplyMagic {
+			return 0, fmt.Errorf("invalid NBD option reply magic 0x%x", reply.Magic)
+		}
+		payload := make([]byte, reply.Length)
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return 0, fmt.Errorf("failed to read NBD option reply: %w", err)
+		}
+
+		switch {
+		case reply.Type&nbdRepFlagError != 0:
+			return 0, &NBDProtocolError{ReplyType: reply.Type, Message: string(payload)}
+		case reply.Type == nbdRepInfo:
+			if len(payload) >= 10 && binary.BigEndian.Uint16(payload) == nbdInfoExport {
+				size = binary.BigEndian.Uint64(payload[2:])
+			}
+		case reply.Type == nbdRepAck:
+			// The export is open, leave the transmission phase cleanly
+			disconnect := binary.BigEndian.AppendUint32(nil, nbdRequestMagic)
+			disconnect = binary.BigEndian.AppendUint16(disconnect, 0)
+			disconnect = binary.BigEndian.AppendUint16(disconnect, nbdCmdDisc)
+			disconnect = append(disconnect, make([]byte, 20)...) // Handle, offset and length
+			_, _ = conn.Write(disconnect)
+			return size, nil
+		default:
+			return 0, fmt.Errorf("unexpected NBD option reply type %d", reply.Type)
+		}
+	}
+}
+
+// parseNBDURL returns the network, address and export name of an NBD URL
+// (nbd://host[:port]/export or nbd+unix:///export?socket=path)
+func parseNBDURL(nbdURL string) (string, string, string, error) {
+	parsed, err := url.Parse(nbdURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid NBD URL %q: %w", nbdURL, err)
+	}
+	export := strings.TrimPrefix(parsed.Path, "/")
+
+	switch parsed.Scheme {
+	case "nbd":
+		host := parsed.Hostname()
+		port := parsed.Port()
+		if port == "" {
+			port = "10809"
+		}
+		return "tcp", net.JoinHostPort(host, port), export, nil
+	case "nbd+unix":
+		socket := parsed.Query().Get("socket")
+		if socket == "" {
+			return "", "", "", fmt.Errorf("NBD URL %q has no socket", nbdURL)
+		}
+		return "unix", socket, export, nil
+	default:
+		return "", "", "", fmt.Errorf("unsupported NBD URL scheme %q", parsed.Scheme)
+	}
+}
+
+// isNotListening returns true for connection errors of a server that is not listening yet
+func isNotListening(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ENOENT)
+}
@@ -0,0 +1,283 @@
+package inspection
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// nbdGreeting returns the fixed newstyle greeting of a server with handshake flags
+func nbdGreeting(flags uint16) []byte {
+	greeting := binary.BigEndian.AppendUint64(nil, nbdMagic)
+	greeting = binary.BigEndian.AppendUint64(greeting, nbdOptionMagic)
+	return binary.BigEndian.AppendUint16(greeting, flags)
+}
+
+// nbdOptionReply returns an option reply to NBD_OPT_GO of replyType with payload
+func nbdOptionReply(replyType uint32, payload []byte) []byte {
+	reply := binary.BigEndian.AppendUint64(nil, nbdOptionReplyMagic)
+	reply = binary.BigEndian.AppendUint32(reply, nbdOptGo)
+	reply = binary.BigEndian.AppendUint32(reply, replyType)
+	reply = binary.BigEndian.AppendUint32(reply, uint32(len(payload)))
+	return append(reply, payload...)
+}
+
+// nbdExportInfo returns the payload of the NBD_INFO_EXPORT reply of an export of size bytes
+func nbdExportInfo(size uint64) []byte {
+	info := binary.BigEndian.AppendUint16(nil, nbdInfoExport)
+	info = binary.BigEndian.AppendUint64(info, size)
+	return binary.BigEndian.AppendUint16(info, 0) // Transmission flags
+}
+
+// serveNBD writes greeting to the client of conn, reads its NBD_OPT_GO request if replies is not nil and
+// answers it with replies, then closes conn. Returns the request sent by the client
+func serveNBD(conn net.Conn, greeting []byte, replies [][]byte) <-chan []byte {
+	requests := make(chan []byte, 1)
+	go func() {
+		defer close(requests)
+		defer conn.Close()
+		if _, err := conn.Write(greeting); err != nil || replies == nil {
+			return
+		}
+		header := make([]byte, 20) // Client flags, option magic, option and data length
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		data := make([]byte, binary.BigEndian.Uint32(header[16:]))
+		if _, err := io.ReadFull(conn, data); err != nil {
+			return
+		}
+		requests <- append(header, data...)
+		for _, reply := range replies {
+			if _, err := conn.Write(reply); err != nil {
+				return
+			}
+		}
+	}()
+	return requests
+}
+
+func TestProbeNBD(t *testing.T) {
+	fixedNewstyle := nbdGreeting(nbdFlagFixedNewstyle | nbdFlagNoZeroes)
+	ack := nbdOptionReply(nbdRepAck, nil)
+
+	tests := []struct {
+		name      string
+		greeting  []byte
+		replies   [][]byte
+		wantSize  uint64
+		wantErr   string
+		wantReply uint32 // Type of the expected NBDProtocolError
+	}{
+		{
+			name:     "export opened",
+			greeting: fixedNewstyle,
+			replies:  [][]byte{nbdOptionReply(nbdRepInfo, nbdExportInfo(40<<30)), ack},
+			wantSize: 40 << 30,
+		},
+		{
+			name:     "other information replies ignored",
+			greeting: fixedNewstyle,
+			replies:  [][]byte{nbdOptionReply(nbdRepInfo, []byte{0, 3, 0, 0, 2, 0}), nbdOptionReply(nbdRepInfo, nbdExportInfo(512)), ack},
+			wantSize: 512,
+		},
+		{
+			name:     "acknowledged without export information",
+			greeting: fixedNewstyle,
+			replies:  [][]byte{ack},
+		},
+		{
+			name:     "truncated greeting",
+			greeting: fixedNewstyle[:10],
+			wantErr:  "failed to read NBD greeting",
+		},
+		{
+			name:     "oldstyle server",
+			greeting: append(binary.BigEndian.AppendUint64(nil, nbdMagic), 0, 0, 0, 0, 0, 0x02, 0x81, 0x86, 0, 0),
+			wantErr:  "not an NBD newstyle server",
+		},
+		{
+			name:     "newstyle server without fixed newstyle",
+			greeting: nbdGreeting(nbdFlagNoZeroes),
+			wantErr:  "does not support the fixed newstyle handshake",
+		},
+		{
+			name:      "unknown export",
+			greeting:  fixedNewstyle,
+			replies:   [][]byte{nbdOptionReply(nbdRepFlagError|6, []byte("export 'disk' not found"))},
+			wantErr:   "NBD server error: unknown export: export 'disk' not found",
+			wantReply: nbdRepFlagError | 6,
+		},
+		{
+			name:      "invalid option",
+			greeting:  fixedNewstyle,
+			replies:   [][]byte{nbdOptionReply(nbdRepFlagError|3, nil)},
+			wantErr:   "NBD server error: invalid option",
+			wantReply: nbdRepFlagError | 3,
+		},
+		{
+			name:      "unknown error type",
+			greeting:  fixedNewstyle,
+			replies:   [][]byte{nbdOptionReply(nbdRepFlagError|42, nil)},
+			wantErr:   "NBD server error: error reply 0x8000002a",
+			wantReply: nbdRepFlagError | 42,
+		},
+		{
+			name:     "invalid reply magic",
+			greeting: fixedNewstyle,
+			replies:  [][]byte{append([]byte{0xde, 0xad}, ack[2:]...)},
+			wantErr:  "invalid NBD option reply magic",
+		},
+		{
+			name:     "unexpected reply type",
+			greeting: fixedNewstyle,
+			replies:  [][]byte{nbdOptionReply(2, []byte("export"))},
+			wantErr:  "unexpected NBD option reply type 2",
+		},
+		{
+			name:     "truncated reply",
+			greeting: fixedNewstyle,
+			replies:  [][]byte{nbdOptionReply(nbdRepInfo, nbdExportInfo(512))[:30]},
+			wantErr:  "failed to read NBD option reply",
+		},
+		{
+			name:     "connection closed before the acknowledgement",
+			greeting: fixedNewstyle,
+			replies:  [][]byte{nbdOptionReply(nbdRepInfo, nbdExportInfo(512))},
+			wantErr:  "failed to read NBD option reply",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+			requests := serveNBD(server, tt.greeting, tt.replies)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			size, err := probeNBD(ctx, client, "disk")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("probeNBD() error = %v, want %q", err, tt.wantErr)
+				}
+				var protocolErr *NBDProtocolError
+				if errors.As(err, &protocolErr) != (tt.wantReply != 0) || (protocolErr != nil && protocolErr.ReplyType != tt.wantReply) {
+					t.Errorf("probeNBD() error = %#v, want reply type 0x%x", err, tt.wantReply)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("probeNBD() error = %v", err)
+			}
+			if size != tt.wantSize {
+				t.Errorf("probeNBD() size = %d, want %d", size, tt.wantSize)
+			}
+			client.Close()
+
+			// Client flags, IHAVEOPT, NBD_OPT_GO and its data: export name and no information requests
+			want := binary.BigEndian.AppendUint32(nil, nbdFlagFixedNewstyle|nbdFlagNoZeroes)
+			want = binary.BigEndian.AppendUint64(want, nbdOptionMagic)
+			want = binary.BigEndian.AppendUint32(want, nbdOptGo)
+			want = binary.BigEndian.AppendUint32(want, 10)
+			want = append(binary.BigEndian.AppendUint32(want, 4), "disk"...)
+			want = binary.BigEndian.AppendUint16(want, 0)
+			if request := <-requests; !bytes.Equal(request, want) {
+				t.Errorf("NBD_OPT_GO request = %x, want %x", request, want)
+			}
+		})
+	}
+}
+
+// noDeadlineConn is a connection ignoring deadlines, like connections forwarded by a process
+type noDeadlineConn struct {
+	net.Conn
+}
+
+func (noDeadlineConn) SetDeadline(t time.Time) error { return nil }
+
+func TestProbeNBDStalledHandshake(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	startedAt := time.Now()
+	_, err := probeNBD(ctx, noDeadlineConn{client}, "") // The server never sends its greeting
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("probeNBD() error = %v, want the context deadline", err)
+	}
+	if elapsed := time.Since(startedAt); elapsed > 5*time.Second {
+		t.Errorf("stalled handshake returned after %s", elapsed)
+	}
+}
+
+func TestParseNBDURL(t *testing.T) {
+	tests := []struct {
+		url                      string
+		network, address, export string
+		wantErr                  bool
+	}{
+		{url: "nbd://127.0.0.1:10810/disk", network: "tcp", address: "127.0.0.1:10810", export: "disk"},
+		{url: "nbd://localhost", network: "tcp", address: "localhost:10809"},
+		{url: "nbd://[::1]:10811/", network: "tcp", address: "[::1]:10811"},
+		{url: "nbd+unix:///disk?socket=/tmp/nbd-1/sock", network: "unix", address: "/tmp/nbd-1/sock", export: "disk"},
+		{url: "nbd+unix:///?socket=/tmp/sock", network: "unix", address: "/tmp/sock"},
+		{url: "nbd+unix:///disk", wantErr: true},
+		{url: "nbds://host/disk", wantErr: true},
+		{url: "nbd://host:port:x/", wantErr: true},
+	}
+	for _, tt := range tests {
+		network, address, export, err := parseNBDURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNBDURL(%q) error = %v, want error %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if network != tt.network || address != tt.address || export != tt.export {
+			t.Errorf("parseNBDURL(%q) = %q, %q, %q, want %q, %q, %q", tt.url, network, address, export, tt.network, tt.address, tt.export)
+		}
+	}
+}
+
+func TestWaitForNBD(t *testing.T) {
+	socket := t.TempDir() + "/nbd.sock"
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			serveNBD(conn, nbdGreeting(nbdFlagFixedNewstyle), [][]byte{
+				nbdOptionReply(nbdRepInfo, nbdExportInfo(1<<30)), nbdOptionReply(nbdRepAck, nil),
+			})
+		}
+	}()
+
+	size, err := waitForNBD(context.Background(), LocalExecutor{}, nbdUnixURL(socket), 5*time.Second, nil)
+	if err != nil || size != 1<<30 {
+		t.Errorf("waitForNBD() = %d, %v, want the export size", size, err)
+	}
+}
+
+func TestWaitForNBDServerExited(t *testing.T) {
+	exited := errors.New("nbdkit process exited")
+	_, err := waitForNBD(context.Background(), LocalExecutor{}, "nbd+unix:///?socket="+t.TempDir()+"/missing", time.Second,
+		func() error { return exited })
+	if !errors.Is(err, exited) {
+		t.Errorf("waitForNBD() error = %v, want the exit error", err)
+	}
+}
+
+func TestWaitForNBDNotListening(t *testing.T) {
+	_, err := waitForNBD(context.Background(), LocalExecutor{}, "nbd+unix:///?socket="+t.TempDir()+"/missing", 300*time.Millisecond, nil)
+	if err == nil || !strings.Contains(err.Error(), "not listening after") {
+		t.Errorf("waitForNBD() error = %v, want a not listening error", err)
+	}
+}
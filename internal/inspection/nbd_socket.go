@@ -0,0 +1,27 @@
+package inspection
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+)
+
+// newNBDSocket returns the path of a unix socket for an NBD server of executor in a new directory only
+// accessible by the current user, so other local users cannot connect to the exported disks
+func newNBDSocket(ctx context.Context, executor Executor, prefix string) (string, error) {
+	dir, err := executor.TempDir(ctx, prefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to create NBD socket directory: %w", err)
+	}
+	return filepath.Join(dir, "nbd.sock"), nil
+}
+
+// removeNBDSocket removes a socket created with newNBDSocket and its directory
+func removeNBDSocket(executor Executor, socketPath string) {
+	_ = executor.RemoveAll(context.Background(), filepath.Dir(socketPath))
+}
+
+// nbdUnixURL returns the NBD URL of the default export of a unix socket
+func nbdUnixURL(socketPath string) string {
+	return "nbd+unix:///?socket=" + socketPath
+}
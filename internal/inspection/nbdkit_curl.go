@@ -0,0 +1,111 @@
+package inspection
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// HTTPSOptions configures reading the disks through the vSphere HTTPS datastore file API, used when
+// the VDDK session of a snapshot cannot be opened
+type HTTPSOptions struct {
+	Fallback           bool   // Read the disks over HTTPS when VDDK fails
+	CAFile             string // CA bundle verifying the vCenter certificate, system CAs if empty
+	InsecureSkipVerify bool   // Do not verify the vCenter certificate
+}
+
+// OpenWithNBDKitCurl serves a snapshot disk downloaded from the vSphere HTTPS datastore file API
+// (https://vcenter/folder/...) with nbdkit-curl, as a slower fallback when VDDK cannot be used
+// Only the raw extent of a disk that is not a snapshot delta can be served, so diskPath must be the
+// frozen base disk of the snapshot (true for the first snapshot of a VM)
+func OpenWithNBDKitCurl(
+	ctx context.Context,
+	diskPath string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	options SessionOptions,
+	logger *logrus.Logger,
+) (*NBDKitSession, error) {
+	fileURL, err := datastoreFileURL(vcenterURL, datacenter, diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	socketPath, err := newNBDSocket(ctx, options.executor(), "nbdkit")
+	if err != nil {
+		return nil, err
+	}
+
+	filters, filterParams := options.Filters.nbdkitArgs()
+	nbdkitArgs := []string{
+		"-U", socketPath,
+		"--foreground",
+		"--exit-with-parent",
+	}
+	if !options.Filters.COW {
+		nbdkitArgs = append(nbdkitArgs, "-r")
+	}
+	nbdkitArgs = append(nbdkitArgs, filters...)
+	nbdkitArgs = append(nbdkitArgs,
+		"curl", // curl plugin
+		fmt.Sprintf("url=%s", fileURL),
+		fmt.Sprintf("user=%s", username),
+		"password=-", // Read from stdin, keeping it out of the process table
+	)
+	if options.HTTPS.CAFile != "" {
+		nbdkitArgs = append(nbdkitArgs, fmt.Sprintf("cainfo=%s", options.HTTPS.CAFile))
+	}
+	if options.HTTPS.InsecureSkipVerify {
+		nbdkitArgs = append(nbdkitArgs, "sslverify=false")
+	}
+	nbdkitArgs = append(nbdkitArgs, filterParams...)
+
+	if logger != nil {
+		logger.WithFields(logrus.Fields{
+			"command":     "nbdkit",
+			"args":        nbdkitArgs,
+			"socket_path": socketPath,
+			"disk_path":   diskPath,
+		}).Info("Starting nbdkit with curl plugin")
+	}
+	return startNBDKit(ctx, options.executor(), nbdkitArgs, password, socketPath, logger)
+}
+
+// datastoreFileURL returns the HTTPS datastore file API URL of the raw extent of a disk
+// e.g. "[datastore1] vm/vm.vmdk" is read from https://vcenter/folder/vm/vm-flat.vmdk?dcPath=dc&dsName=datastore1
+func datastoreFileURL(vcenterURL, datacenter, diskPath string) (string, error) {
+	if datacenter == "" {
+		return "", fmt.Errorf("datacenter is required to read disks over HTTPS")
+	}
+	datastore, file, err := parseDatastorePath(diskPath)
+	if err != nil {
+		return "", err
+	}
+	if types.IsDeltaDisk(file) {
+		return "", fmt.Errorf("disk %s is a snapshot delta, only base disks can be read over HTTPS", diskPath)
+	}
+	if !strings.HasSuffix(file, ".vmdk") {
+		return "", fmt.Errorf("disk %s is not a VMDK", diskPath)
+	}
+
+	parsed, err := url.Parse(vcenterURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse vCenter URL: %w", err)
+	}
+	fileURL := url.URL{
+		Scheme: "https",
+		Host:   parsed.Host,
+		Path:   "/folder/" + strings.TrimSuffix(file, ".vmdk") + "-flat.vmdk",
+		RawQuery: url.Values{
+			"dcPath": {datacenter},
+			"dsName": {datastore},
+		}.Encode(),
+	}
+	return fileURL.String(), nil
+}
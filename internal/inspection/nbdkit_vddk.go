@@ -1,20 +1,18 @@
 package inspection
 
 import (
-	"bytes"
 	"context"
-	"crypto/sha256"
+	"crypto/sha1"
 	"crypto/tls"
-	"encoding/hex"
+	"crypto/x509"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"os/exec"
-	"path/filepath"
-	"syscall"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,10 +20,14 @@ import (
 type NBDKitSession struct {
 	NBDURL     string // Unix socket path or NBD URL
 	socketPath string // Unix socket path (if using Unix socket)
+	executor   Executor
 	cmd        *exec.Cmd
 	logger     *logrus.Logger
-	stderrBuf  *bytes.Buffer
-	stdoutBuf  *bytes.Buffer
+	stderrBuf  *outputBuffer
+	stdoutBuf  *outputBuffer
+	done       chan struct{} // Closed once the nbdkit process has exited
+	waitErr    error         // Exit error of the nbdkit process, set before done is closed
+	unregister func()        // Unregisters Close from the cleanup registry
 }
 
 // OpenWithNBDKitVDDK opens a VMware snapshot using nbdkit with VDDK plugin directly
@@ -36,6 +38,7 @@ type NBDKitSession struct {
 //   - vcenterURL: vCenter URL (e.g., "https://vcenter.example.com")
 //   - username: vCenter username
 //   - password: vCenter password
+//   - options: nbdkit filters and VDDK settings of the session
 //   - logger: Logger instance
 func OpenWithNBDKitVDDK(
 	ctx context.Context,
@@ -45,6 +48,7 @@ func OpenWithNBDKitVDDK(
 	vcenterURL string,
 	username string,
 	password string,
+	options SessionOptions,
 	logger *logrus.Logger,
 ) (*NBDKitSession, error) {
 	// Parse vCenter URL to extract hostname
@@ -55,53 +59,50 @@ func OpenWithNBDKitVDDK(
 	vcenterHost := parsedURL.Hostname()
 
 	// Get vCenter SSL thumbprint
-	var thumbprint string
 	if logger != nil {
 		logger.Debug("Getting vCenter SSL thumbprint")
 	}
-	thumbprint, err = getVCenterThumbprint(vcenterHost)
+	thumbprint, err := options.VDDK.thumbprint(vcenterHost, logger)
 	if err != nil {
-		if logger != nil {
-			logger.WithError(err).Warn("Failed to get thumbprint, proceeding without SSL verification")
-		}
-		thumbprint = ""
+		return nil, err
 	}
-	if thumbprint != "" && logger != nil {
-		logger.WithField("thumbprint", thumbprint).Debug("Got vCenter thumbprint")
+
+	// Serve on a unix socket in a private directory (more reliable than TCP port)
+	socketPath, err := newNBDSocket(ctx, options.executor(), "nbdkit")
+	if err != nil {
+		return nil, err
 	}
-	// Create temporary Unix socket for nbdkit (more reliable than TCP port)
-	socketPath := filepath.Join("/tmp", fmt.Sprintf("nbdkit-%s.sock", uuid.New().String()))
 
 	// Determine VDDK library directory
-	vddkLibDir := "/opt/vmware-vix-disklib"
-	if _, err := os.Stat(vddkLibDir); err != nil {
-		altPaths := []string{
-			"/usr/lib64/vmware-vix-disklib",
-			"/usr/local/vmware-vix-disklib",
-		}
-		for _, altPath := range altPaths {
-			if _, err := os.Stat(altPath); err == nil {
-				vddkLibDir = altPath
-				break
-			}
-		}
+	vddkLibrary, err := options.executor().VDDKLibrary(options.VDDK.LibDir)
+	if err != nil {
+		removeNBDSocket(options.executor(), socketPath)
+		return nil, err
 	}
+	vddkLibDir := vddkLibrary.Dir
 
 	// Build nbdkit command with VDDK plugin
+	filters, filterParams := options.Filters.nbdkitArgs()
 	nbdkitArgs := []string{
 		"-U", socketPath, // Unix socket path
 		"--foreground",       // Run in foreground
 		"--exit-with-parent", // Exit when parent process exits
-		"-r",                 // Read-only mode for snapshots
-		"vddk",               // VDDK plugin
+	}
+	if !options.Filters.COW {
+		nbdkitArgs = append(nbdkitArgs, "-r") // Read-only mode for snapshots, the cow filter never writes to the plugin
+	}
+	nbdkitArgs = append(nbdkitArgs, filters...)
+	nbdkitArgs = append(nbdkitArgs,
+		"vddk", // VDDK plugin
 		fmt.Sprintf("server=%s", vcenterHost),
 		fmt.Sprintf("user=%s", username),
-		fmt.Sprintf("password=%s", password),
+		"password=-",                              // Read from stdin, keeping it out of the process table
 		fmt.Sprintf("vm=moref=%s", vmMoref),       // VM moref (required)
 		fmt.Sprintf("snapshot=%s", snapshotMoref), // Snapshot moref to read from
 		fmt.Sprintf("file=%s", baseDiskPath),      // Base VMDK file path
 		fmt.Sprintf("libdir=%s", vddkLibDir),      // VDDK library location
-	}
+	)
+	nbdkitArgs = append(nbdkitArgs, filterParams...)
 
 	// Add thumbprint if available (for SSL verification)
 	if thumbprint != "" {
@@ -111,94 +112,64 @@ func OpenWithNBDKitVDDK(
 	// Add verbose for debugging
 	// nbdkitArgs = append(nbdkitArgs, "--verbose")
 
-	// Log the command (without password)
 	if logger != nil {
-		logArgs := make([]string, len(nbdkitArgs))
-		copy(logArgs, nbdkitArgs)
-		// Mask password in log
-		for i, arg := range logArgs {
-			if len(arg) > 8 && arg[:8] == "password=" {
-				logArgs[i] = "password=***"
-			}
-		}
 		logger.WithFields(logrus.Fields{
 			"command":        "nbdkit",
-			"args":           logArgs,
+			"args":           nbdkitArgs,
 			"socket_path":    socketPath,
 			"vm_moref":       vmMoref,
 			"snapshot_moref": snapshotMoref,
 			"disk_path":      baseDiskPath,
+			"vddk_libdir":    vddkLibDir,
+			"vddk_version":   vddkLibrary.Version,
 		}).Info("Starting nbdkit with VDDK plugin")
 	}
 
 	// Start nbdkit with VDDK plugin
-	cmd := exec.CommandContext(ctx, "nbdkit", nbdkitArgs...)
+	return startNBDKit(ctx, options.executor(), nbdkitArgs, password, socketPath, logger)
+}
+
+// startNBDKit starts nbdkit serving on socketPath, which is removed if nbdkit fails to start
+// password is written to the standard input of nbdkit, for plugins configured with "password=-"
+func startNBDKit(ctx context.Context, executor Executor, nbdkitArgs []string, password string, socketPath string, logger *logrus.Logger) (*NBDKitSession, error) {
+	cmd := executor.Command(ctx, "nbdkit", nbdkitArgs...)
+	cmd.Stdin = strings.NewReader(password + "\n")
 
 	// Preserve environment - the nbdkit wrapper (created in Dockerfile) will set LD_LIBRARY_PATH
 	// for VDDK libraries, so we don't need to set it here
 	cmd.Env = os.Environ()
 
 	// Capture both stdout and stderr to check for errors
-	stdoutBuf := &bytes.Buffer{}
-	stderrBuf := &bytes.Buffer{}
+	stdoutBuf := newOutputBuffer(maxToolOutput)
+	stderrBuf := newOutputBuffer(maxToolOutput)
 	cmd.Stderr = stderrBuf
 	cmd.Stdout = stdoutBuf
 
 	// Start nbdkit
 	if err := cmd.Start(); err != nil {
+		removeNBDSocket(executor, socketPath)
 		return nil, fmt.Errorf("failed to start nbdkit: %w", err)
 	}
 
-	// Wait a moment for nbdkit to start
-	time.Sleep(2 * time.Second)
-
-	// Check if process is still running
-	// ProcessState is only set after Wait(), so we need to check the process directly
-	if cmd.Process == nil {
-		return nil, fmt.Errorf("nbdkit process is nil after start")
-	}
-
-	// Check if process has exited by sending signal 0 (doesn't kill, just checks)
-	if err := cmd.Process.Signal(os.Signal(syscall.Signal(0))); err != nil {
-		// Process has exited, read stderr and stdout for error messages
-		stderrOutput := stderrBuf.String()
-		stdoutOutput := stdoutBuf.String()
-		if logger != nil {
-			logger.WithFields(logrus.Fields{
-				"stderr":      stderrOutput,
-				"stdout":      stdoutOutput,
-				"socket_path": socketPath,
-			}).Error("nbdkit process exited immediately")
-		}
-		errorMsg := "nbdkit process exited immediately"
-		if stderrOutput != "" {
-			errorMsg += fmt.Sprintf(" (stderr: %s)", stderrOutput)
-		}
-		if stdoutOutput != "" {
-			errorMsg += fmt.Sprintf(" (stdout: %s)", stdoutOutput)
-		}
-		return nil, fmt.Errorf("%s", errorMsg)
-	}
-
-	// Log initial output for debugging (use Info level so it's visible)
-	if logger != nil && (stderrBuf.Len() > 0 || stdoutBuf.Len() > 0) {
-		logger.WithFields(logrus.Fields{
-			"stderr": stderrBuf.String(),
-			"stdout": stdoutBuf.String(),
-		}).Info("nbdkit initial output")
-	}
-
 	// Build NBD URL using Unix socket format (matching origin/main)
-	nbdURL := fmt.Sprintf("nbd+unix:///?socket=%s", socketPath)
+	nbdURL := nbdUnixURL(socketPath)
 
-	return &NBDKitSession{
+	session := &NBDKitSession{
 		NBDURL:     nbdURL,
 		socketPath: socketPath,
+		executor:   executor,
 		cmd:        cmd,
 		logger:     logger,
 		stderrBuf:  stderrBuf,
 		stdoutBuf:  stdoutBuf,
-	}, nil
+		done:       make(chan struct{}),
+	}
+	go func() {
+		session.waitErr = cmd.Wait()
+		close(session.done)
+	}()
+	session.unregister = registerCleanup(session.Close)
+	return session, nil
 }
 
 // Close stops the nbdkit process and cleans up
@@ -206,177 +177,117 @@ func (s *NBDKitSession) Close() {
 	if s == nil {
 		return
 	}
+	if s.unregister != nil {
+		s.unregister()
+	}
 
 	if s.cmd != nil && s.cmd.Process != nil {
-		// Send SIGTERM first for graceful shutdown
+		// Send SIGINT first for graceful shutdown
 		_ = s.cmd.Process.Signal(os.Interrupt)
 
-		// Wait a bit for graceful shutdown
-		done := make(chan error, 1)
-		go func() {
-			done <- s.cmd.Wait()
-		}()
-
 		select {
-		case <-done:
+		case <-s.done:
 			// Process exited gracefully
 		case <-time.After(5 * time.Second):
 			// Force kill if it doesn't exit
-			_ = s.cmd.Process.Kill()
-			_, _ = s.cmd.Process.Wait()
+			_ = killCommand(s.cmd)
+			<-s.done
 		}
 	}
 
 	// Clean up Unix socket file
 	if s.socketPath != "" {
-		_ = os.Remove(s.socketPath)
+		removeNBDSocket(s.executor, s.socketPath)
 	}
 }
 
-// WaitForReady waits for the NBD server to be ready by checking if the Unix socket exists
-func (s *NBDKitSession) WaitForReady(timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-
-	// First, verify the process is still running
-	if s.cmd != nil && s.cmd.Process != nil {
-		// Check if process is still alive
-		if err := s.cmd.Process.Signal(os.Signal(syscall.Signal(0))); err != nil {
-			return fmt.Errorf("nbdkit process died before NBD server was ready: %w", err)
-		}
+// WaitForReady waits until the NBD server completes a handshake for its export
+// Opening the export makes the VDDK plugin connect to vCenter, so connection and authentication
+// failures are returned as soon as they happen
+func (s *NBDKitSession) WaitForReady(ctx context.Context, timeout time.Duration) error {
+	_, err := waitForNBD(ctx, s.executor, s.NBDURL, timeout, s.exited)
+	if err == nil {
+		return nil
 	}
 
-	checkCount := 0
-	for time.Now().Before(deadline) {
-		checkCount++
-
-		// Check if process is still running
-		if s.cmd != nil && s.cmd.Process != nil {
-			if err := s.cmd.Process.Signal(os.Signal(syscall.Signal(0))); err != nil {
-				// Process died, get error output
-				errorDetails := ""
-				if s.stderrBuf != nil {
-					errorDetails = s.stderrBuf.String()
-				}
-				if s.stdoutBuf != nil && errorDetails == "" {
-					errorDetails = s.stdoutBuf.String()
-				}
-				if s.logger != nil {
-					s.logger.WithFields(logrus.Fields{
-						"stderr":      errorDetails,
-						"socket_path": s.socketPath,
-					}).Error("nbdkit process died while waiting for NBD server")
-				}
-				if errorDetails != "" {
-					return fmt.Errorf("nbdkit process died while waiting for NBD server: %w (output: %s)", err, errorDetails)
-				}
-				return fmt.Errorf("nbdkit process died while waiting for NBD server: %w", err)
-			}
-		}
-
-		// Periodically log nbdkit output for debugging (every 5 seconds)
-		if s.logger != nil && checkCount%10 == 0 {
-			if s.stderrBuf != nil && s.stderrBuf.Len() > 0 {
-				s.logger.WithField("stderr", s.stderrBuf.String()).Debug("nbdkit stderr output")
-			}
-			if s.stdoutBuf != nil && s.stdoutBuf.Len() > 0 {
-				s.logger.WithField("stdout", s.stdoutBuf.String()).Debug("nbdkit stdout output")
-			}
-		}
-
-		// Check if Unix socket exists
-		if _, err := os.Stat(s.socketPath); err == nil {
-			// Socket exists, give it a moment to fully initialize
-			time.Sleep(500 * time.Millisecond)
-			return nil
-		}
-		time.Sleep(500 * time.Millisecond)
+	// The server may have failed because the process is exiting, prefer its output
+	select {
+	case <-s.done:
+		err = s.exited()
+	case <-time.After(nbdProbeInterval):
 	}
-
-	// Final check - did the process die?
-	if s.cmd != nil && s.cmd.Process != nil {
-		if err := s.cmd.Process.Signal(os.Signal(syscall.Signal(0))); err != nil {
-			// Try to read any error output
-			errorDetails := ""
-			if s.stderrBuf != nil {
-				errorDetails = s.stderrBuf.String()
-			}
-			if s.stdoutBuf != nil && errorDetails == "" {
-				errorDetails = s.stdoutBuf.String()
-			}
-			if s.logger != nil {
-				s.logger.WithFields(logrus.Fields{
-					"stderr":      errorDetails,
-					"socket_path": s.socketPath,
-				}).Error("nbdkit process died while waiting for NBD server")
-			}
-			if errorDetails != "" {
-				return fmt.Errorf("nbdkit process died: %w (NBD server not ready after %v, error: %s)", err, timeout, errorDetails)
-			}
-			return fmt.Errorf("nbdkit process died: %w (NBD server not ready after %v)", err, timeout)
-		}
+	if s.logger != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":       err,
+			"socket_path": s.socketPath,
+			"stderr":      s.output(),
+		}).Error("NBD server not ready")
 	}
+	return err
+}
 
-	// Log that process is running but socket is not accessible
-	errorDetails := ""
-	if s.stderrBuf != nil {
-		errorDetails = s.stderrBuf.String()
+// exited returns an error including the process output once the nbdkit process has exited
+func (s *NBDKitSession) exited() error {
+	select {
+	case <-s.done:
+	default:
+		return nil
 	}
-	if s.stdoutBuf != nil && errorDetails == "" {
-		errorDetails = s.stdoutBuf.String()
+	reason := "nbdkit process exited"
+	if s.waitErr != nil {
+		reason += ": " + s.waitErr.Error()
 	}
+	if output := s.output(); output != "" {
+		return fmt.Errorf("%s (output: %s)", reason, output)
+	}
+	return fmt.Errorf("%s", reason)
+}
 
-	if s.logger != nil {
-		s.logger.WithFields(logrus.Fields{
-			"socket_path": s.socketPath,
-			"stderr":      errorDetails,
-			"stdout": func() string {
-				if s.stdoutBuf != nil {
-					return s.stdoutBuf.String()
-				}
-				return ""
-			}(),
-		}).Error("NBD server process running but socket not accessible")
+// output returns the end of the output of the nbdkit process, with credentials redacted
+func (s *NBDKitSession) output() string {
+	if s.stderrBuf.Len() > 0 {
+		return s.stderrBuf.String()
 	}
+	return s.stdoutBuf.String()
+}
 
-	// Include nbdkit error output in the error message
-	if errorDetails != "" {
-		return fmt.Errorf("NBD server not ready after %v (process still running, but socket %s not accessible). nbdkit output: %s", timeout, s.socketPath, errorDetails)
+// caTLSConfig returns the TLS configuration verifying the vCenter certificate against the system roots, or the
+// CAs of caCertFile if set
+func caTLSConfig(vcenterHost string, caCertFile string) (*tls.Config, error) {
+	config := &tls.Config{ServerName: vcenterHost}
+	if caCertFile != "" {
+		pem, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificates: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no CA certificates found in %s", caCertFile)
+		}
 	}
-	return fmt.Errorf("NBD server not ready after %v (process still running, but socket %s not accessible)", timeout, s.socketPath)
+	return config, nil
 }
 
-// getVCenterThumbprint gets the SSL certificate thumbprint from vCenter
-func getVCenterThumbprint(vcenterHost string) (string, error) {
-	// Connect to vCenter to get SSL certificate
-	conn, err := tls.Dial("tcp", vcenterHost+":443", &tls.Config{
-		InsecureSkipVerify: true, // We just need the cert, not to verify it
-	})
+// getVCenterThumbprint returns the SHA-1 thumbprint of the vCenter certificate
+// The certificate is verified against the system roots, or the CAs of caCertFile if set
+func getVCenterThumbprint(vcenterHost string, caCertFile string) (string, error) {
+	config, err := caTLSConfig(vcenterHost, caCertFile)
+	if err != nil {
+		return "", err
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(vcenterHost, "443"), config)
 	if err != nil {
 		return "", fmt.Errorf("failed to connect to vCenter: %w", err)
 	}
 	defer conn.Close()
 
-	// Get the certificate chain
+	// The first certificate is the vCenter certificate, the others its chain
 	certs := conn.ConnectionState().PeerCertificates
 	if len(certs) == 0 {
 		return "", fmt.Errorf("no certificates found")
 	}
-
-	// Use the first certificate (server certificate)
-	cert := certs[0]
-
-	// Calculate SHA-256 thumbprint
-	thumbprint := sha256.Sum256(cert.Raw)
-
-	// Format as colon-separated hex string (VMware format)
-	hexThumbprint := hex.EncodeToString(thumbprint[:])
-	formatted := ""
-	for i := 0; i < len(hexThumbprint); i += 2 {
-		if i > 0 {
-			formatted += ":"
-		}
-		formatted += hexThumbprint[i : i+2]
-	}
-
-	return formatted, nil
+	digest := sha1.Sum(certs[0].Raw)
+	return formatThumbprint(digest[:]), nil
 }
@@ -0,0 +1,82 @@
+package inspection
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// parttypeMarker separates the partition table type of individual devices in guestfish output
+const parttypeMarker = "@@@parttype "
+
+// PartitionTables reports the partition table type of every disk of the VM snapshot using guestfish
+func (i *VirtInspector) PartitionTables(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]types.PartitionTable, error) {
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionCloser()
+
+	// First pass: list the disks attached to the appliance
+	output, err := i.runGuestfish(ctx, nbdURLs, "list-devices\n")
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if device := strings.TrimSpace(scanner.Text()); device != "" {
+			devices = append(devices, device)
+		}
+	}
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("no disks found in the guest")
+	}
+
+	i.logger.WithFields(logrus.Fields{
+		"nbd_urls": nbdURLs,
+		"devices":  devices,
+	}).Info("Reading partition tables on NBD")
+
+	// Second pass: query each disk, unpartitioned disks make part-get-parttype fail and are ignored
+	var script strings.Builder
+	for _, device := range devices {
+		fmt.Fprintf(&script, "echo %s\n", guestfishQuote(parttypeMarker+device))
+		fmt.Fprintf(&script, "-part-get-parttype %s\n", guestfishQuote(device))
+	}
+	output, err = i.runGuestfish(ctx, nbdURLs, script.String())
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := splitMarkedOutput(output, parttypeMarker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partition table output: %w", err)
+	}
+
+	tables := make([]types.PartitionTable, 0, len(devices))
+	for _, device := range devices {
+		table := types.PartitionTable{Device: device}
+		if lines := sections[device]; len(lines) > 0 {
+			table.Type = strings.TrimSpace(lines[0])
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
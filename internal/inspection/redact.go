@@ -0,0 +1,171 @@
+package inspection
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// secretPatterns match credentials in tool output, command lines and errors, the first group is kept
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(password=)[^\s&'"]+`),                    // nbdkit parameters and vpx:// query
+	regexp.MustCompile(`(?i)("?password"?\s*:\s*"?)[^\s"',}]+`),       // key: value and JSON forms
+	regexp.MustCompile(`([a-z][a-z0-9+.-]*://[^/\s:@]+:)[^@\s/]+(@)`), // URL user info
+	regexp.MustCompile(`(?i)(vmware_soap_session="?)[^;\s"']+`),       // vCenter session cookie, quoted or not
+	regexp.MustCompile(`(?i)(ticket[=:]\s*"?)[^\s"',;&]+`),            // VDDK and NFC session tickets
+}
+
+// minSecretLength avoids redacting short strings that are likely to appear in unrelated text
+const minSecretLength = 4
+
+// redactor redacts the credentials of a session or inspection (e.g., its vCenter password and LUKS keys)
+// from its logs and errors, in addition to the secretPatterns
+// A redactor is immutable, its secrets are released with the session or inspection holding it
+type redactor struct {
+	secrets []string
+}
+
+// newRedactor creates a redactor of secrets, ignoring secrets too short to be redacted
+func newRedactor(secrets ...string) *redactor {
+	return (&redactor{}).with(secrets...)
+}
+
+// with returns a redactor of the secrets of r and secrets
+func (r *redactor) with(secrets ...string) *redactor {
+	combined := &redactor{secrets: slices.Clone(r.secrets)}
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength && !slices.Contains(combined.secrets, secret) {
+			combined.secrets = append(combined.secrets, secret)
+		}
+	}
+	return combined
+}
+
+// redact replaces the secrets of r and the credentials matching secretPatterns in text with "***"
+func (r *redactor) redact(text string) string {
+	for _, secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, "***")
+	}
+	return redact(text)
+}
+
+// redact replaces credentials matching secretPatterns in text with "***"
+func redact(text string) string {
+	for _, pattern := range secretPatterns {
+		if pattern.NumSubexp() == 2 {
+			text = pattern.ReplaceAllString(text, "${1}***${2}")
+		} else {
+			text = pattern.ReplaceAllString(text, "${1}***")
+		}
+	}
+	return text
+}
+
+// redactedError is an error whose message has credentials redacted, wrapping the original error
+type redactedError struct {
+	err      error
+	message  string
+	redactor *redactor
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// As returns the *ToolError of the wrapped errors with credentials redacted from its output
+// The wrapped error may be shared (e.g., the error of a session), so a redacted copy is returned
+func (e *redactedError) As(target any) bool {
+	toolErrTarget, ok := target.(**ToolError)
+	if !ok {
+		return false
+	}
+	var toolErr *ToolError
+	if !errors.As(e.err, &toolErr) {
+		return false
+	}
+	redacted := *toolErr
+	redacted.Stderr = e.redactor.redact(toolErr.Stderr)
+	*toolErrTarget = &redacted
+	return true
+}
+
+// redactError returns err with credentials redacted from its message
+// Typed errors of the package (e.g., *ToolError) remain reachable with errors.As
+func (r *redactor) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := r.redact(err.Error())
+	if message == err.Error() {
+		return err
+	}
+	return &redactedError{err: err, message: message, redactor: r}
+}
+
+// redactHook is a logrus hook redacting credentials from messages and fields
+type redactHook struct {
+	redactor *redactor
+}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire redacts the entry, which logrus duplicates before firing hooks
+func (h redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.redactor.redact(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = h.redactor.redact(v)
+		case []string:
+			redacted := make([]string, len(v))
+			for idx, s := range v {
+				redacted[idx] = h.redactor.redact(s)
+			}
+			entry.Data[key] = redacted
+		case error:
+			entry.Data[key] = h.redactor.redactError(v)
+		case fmt.Stringer:
+			entry.Data[key] = h.redactor.redact(v.String())
+		}
+	}
+	return nil
+}
+
+// logger returns a logger writing like logger with the credentials of r redacted from every entry
+// The logger of the caller is not modified; nil is returned for a nil logger
+func (r *redactor) logger(logger *logrus.Logger) *logrus.Logger {
+	if logger == nil {
+		return nil
+	}
+	for _, hooks := range logger.Hooks {
+		for _, hook := range hooks {
+			if hook, ok := hook.(redactHook); ok && hook.redactor == r {
+				return logger
+			}
+		}
+	}
+	redacting := &logrus.Logger{
+		Out:          logger.Out,
+		Hooks:        make(logrus.LevelHooks),
+		Formatter:    logger.Formatter,
+		ReportCaller: logger.ReportCaller,
+		Level:        logger.GetLevel(),
+		ExitFunc:     logger.ExitFunc,
+	}
+	// Redaction runs first so the hooks of the caller only see redacted entries
+	redacting.AddHook(redactHook{redactor: r})
+	for level, hooks := range logger.Hooks {
+		redacting.Hooks[level] = append(redacting.Hooks[level], hooks...)
+	}
+	return redacting
+}
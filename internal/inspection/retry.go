@@ -0,0 +1,124 @@
+package inspection
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RetryPolicy retries operations failing with transient errors (e.g., VDDK busy, connection reset)
+// with exponential backoff and jitter
+// The zero value performs a single attempt
+type RetryPolicy struct {
+	MaxAttempts    int           // Attempts including the first one, a single attempt if zero
+	InitialBackoff time.Duration // Delay before the first retry, defaults to 2 seconds
+	MaxBackoff     time.Duration // Maximum delay between attempts, defaults to 1 minute
+	Multiplier     float64       // Backoff growth per attempt, defaults to 2
+	Jitter         float64       // Random fraction of the backoff added or removed (0 to 1), defaults to 0.2
+}
+
+// transientErrorPatterns are lowercase error message fragments of failures worth retrying
+var transientErrorPatterns = []string{
+	"busy",
+	"connection reset",
+	"broken pipe",
+	"temporarily unavailable",
+	"too many connections",
+	"server shutting down",
+}
+
+// RetryError reports all attempts of an operation that failed after retries
+type RetryError struct {
+	Operation string
+	Attempts  []error
+}
+
+func (e *RetryError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s failed after %d attempts", e.Operation, len(e.Attempts))
+	for idx, err := range e.Attempts {
+		fmt.Fprintf(&b, "\n  attempt %d: %v", idx+1, err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the error of the last attempt
+func (e *RetryError) Unwrap() error {
+	return e.Attempts[len(e.Attempts)-1]
+}
+
+// isTransient returns true for errors that may succeed when retried
+func isTransient(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, pattern := range transientErrorPatterns {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// do runs fn until it succeeds, fails with an error that is not transient, or the attempts are exhausted
+func (p RetryPolicy) do(ctx context.Context, logger *logrus.Logger, operation string, fn func() error) error {
+	var attempts []error
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = 2 * time.Second
+	}
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		attempts = append(attempts, err)
+		if len(attempts) >= p.MaxAttempts || !isTransient(err) || ctx.Err() != nil {
+			if len(attempts) == 1 {
+				return err
+			}
+			return &RetryError{Operation: operation, Attempts: attempts}
+		}
+
+		delay := p.jitter(backoff)
+		if logger != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"operation": operation,
+				"attempt":   len(attempts),
+				"delay":     delay,
+			}).Warn("Transient failure, retrying")
+		}
+		select {
+		case <-ctx.Done():
+			attempts = append(attempts, ctx.Err())
+			return &RetryError{Operation: operation, Attempts: attempts}
+		case <-time.After(delay):
+		}
+		backoff = p.next(backoff)
+	}
+}
+
+// next returns the backoff following backoff
+func (p RetryPolicy) next(backoff time.Duration) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+	return min(time.Duration(float64(backoff)*multiplier), maxBackoff)
+}
+
+// jitter randomizes a backoff by the jitter fraction of the policy
+func (p RetryPolicy) jitter(backoff time.Duration) time.Duration {
+	jitter := p.Jitter
+	if jitter <= 0 {
+		jitter = 0.2
+	}
+	jitter = min(jitter, 1)
+	return time.Duration(float64(backoff) * (1 + jitter*(2*rand.Float64()-1)))
+}
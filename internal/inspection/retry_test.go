@@ -0,0 +1,125 @@
+package inspection
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDo(t *testing.T) {
+	busy := errors.New("VDDK: device or resource busy")
+	denied := errors.New("permission denied")
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Jitter: 0.01}
+
+	tests := []struct {
+		name         string
+		policy       RetryPolicy
+		failures     []error // Errors of the attempts before the first success
+		wantAttempts int
+		wantErr      error
+		wantRetryErr bool
+	}{
+		{name: "success", policy: policy, wantAttempts: 1},
+		{name: "transient then success", policy: policy, failures: []error{busy, busy}, wantAttempts: 3},
+		{name: "permanent", policy: policy, failures: []error{denied}, wantAttempts: 1, wantErr: denied},
+		{name: "transient then permanent", policy: policy, failures: []error{busy, denied}, wantAttempts: 2, wantErr: denied, wantRetryErr: true},
+		{name: "attempts exhausted", policy: policy, failures: []error{busy, busy, busy, busy}, wantAttempts: 3, wantErr: busy, wantRetryErr: true},
+		{name: "zero policy", failures: []error{busy}, wantAttempts: 1, wantErr: busy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := tt.policy.do(context.Background(), nil, "VDDK session", func() error {
+				attempts++
+				if attempts <= len(tt.failures) {
+					return tt.failures[attempts-1]
+				}
+				return nil
+			})
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Errorf("do() error = %v, want %v", err, tt.wantErr)
+			}
+			var retryErr *RetryError
+			if errors.As(err, &retryErr) != tt.wantRetryErr {
+				t.Errorf("do() error = %#v, want a RetryError %v", err, tt.wantRetryErr)
+			}
+			if retryErr != nil && (len(retryErr.Attempts) != attempts || !strings.HasPrefix(retryErr.Error(), "VDDK session failed after")) {
+				t.Errorf("RetryError = %v", retryErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}.do(ctx, nil, "guestfish", func() error {
+		attempts++
+		cancel()
+		return errors.New("connection reset by peer")
+	})
+	if attempts != 1 || err == nil || errors.As(err, new(*RetryError)) {
+		t.Fatalf("do() = %v after %d attempts, want the error of the single attempt", err, attempts)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	err = RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}.do(ctx, nil, "guestfish", func() error {
+		return errors.New("connection reset by peer")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("do() error = %v, want the cancellation during the backoff", err)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		policy  RetryPolicy
+		backoff time.Duration
+		want    time.Duration
+	}{
+		{RetryPolicy{}, 2 * time.Second, 4 * time.Second},
+		{RetryPolicy{}, 45 * time.Second, time.Minute},
+		{RetryPolicy{Multiplier: 1.5, MaxBackoff: 10 * time.Second}, 4 * time.Second, 6 * time.Second},
+		{RetryPolicy{Multiplier: 3, MaxBackoff: 10 * time.Second}, 4 * time.Second, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.next(tt.backoff); got != tt.want {
+			t.Errorf("%+v.next(%v) = %v, want %v", tt.policy, tt.backoff, got, tt.want)
+		}
+	}
+
+	for _, jitter := range []float64{0, 0.5, 3} {
+		policy := RetryPolicy{Jitter: jitter}
+		fraction := map[float64]float64{0: 0.2, 0.5: 0.5, 3: 1}[jitter]
+		for range 100 {
+			delay := policy.jitter(10 * time.Second)
+			if low, high := time.Duration(float64(10*time.Second)*(1-fraction)), time.Duration(float64(10*time.Second)*(1+fraction)); delay < low || delay > high {
+				t.Fatalf("jitter %v: delay %v outside [%v, %v]", jitter, delay, low, high)
+			}
+		}
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := map[string]bool{
+		"VixDiskLib: Device or resource busy":            true,
+		"read: connection reset by peer":                 true,
+		"write: broken pipe":                             true,
+		"Resource temporarily unavailable":               true,
+		"NBD server error: server shutting down":         true,
+		"nbdkit: vddk: too many connections to the host": true,
+		"permission denied":                              false,
+		"NBD server error: unknown export":               false,
+	}
+	for message, want := range tests {
+		if got := isTransient(errors.New(message)); got != want {
+			t.Errorf("isTransient(%q) = %v, want %v", message, got, want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package inspection
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"path"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// systemdUnitDir is the guest directory holding administrator enabled systemd units
+const systemdUnitDir = "/etc/systemd/system"
+
+// ListEnabledServices lists the systemd units enabled in the guest using guestfish
+// Units are enabled by symlinks in the .wants and .requires directories of systemdUnitDir.
+// A guest without systemd results in an empty list rather than an error.
+func (i *VirtInspector) ListEnabledServices(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]types.EnabledService, error) {
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer sessionCloser()
+
+	i.logger.WithFields(logrus.Fields{
+		"nbd_urls": nbdURLs,
+	}).Info("Listing enabled services on NBD")
+
+	output, err := i.runGuestfish(ctx, nbdURLs, "-find "+guestfishQuote(systemdUnitDir)+"\n")
+	if err != nil {
+		return nil, err
+	}
+
+	var services []types.EnabledService
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		// find prints paths relative to systemdUnitDir (e.g., "multi-user.target.wants/vmtoolsd.service")
+		dir, name := path.Split(strings.TrimSpace(scanner.Text()))
+		dir = strings.TrimSuffix(dir, "/")
+		if name == "" || strings.Contains(dir, "/") {
+			continue
+		}
+		wantedBy, ok := strings.CutSuffix(dir, ".wants")
+		if !ok {
+			wantedBy, ok = strings.CutSuffix(dir, ".requires")
+		}
+		if !ok {
+			continue
+		}
+		services = append(services, types.EnabledService{Name: name, WantedBy: wantedBy})
+	}
+	return services, scanner.Err()
+}
@@ -0,0 +1,554 @@
+package inspection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// SessionManager opens the NBD sessions of VM snapshots once and shares them between inspections
+// A session is opened by the first inspection of a VM snapshot and kept open while inspections use it
+// and for an idle timeout afterwards, so consecutive inspections of the same snapshot reuse its NBD endpoint
+// The sessions are used by VirtInspector, and by VirtV2vInspector as the disks of a libvirt domain
+type SessionManager struct {
+	idleTimeout time.Duration
+	options     SessionOptions
+	logger      *logrus.Logger
+	open        sessionOpener
+
+	mu       sync.Mutex
+	sessions map[string]*sharedSession
+}
+
+// sessionOpener opens the NBD sessions of the disks of a VM snapshot, openDiskSessions outside of tests
+type sessionOpener func(
+	ctx context.Context,
+	logger *logrus.Logger,
+	options SessionOptions,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]string, func(), error)
+
+// sharedSession is an NBD session used by one or more inspections
+type sharedSession struct {
+	ready   chan struct{} // Closed once the session is open or failed to open
+	nbdURLs []string      // NBD URL of each disk of the VM snapshot
+	close   func()
+	err     error
+	refs    int
+	idle    *time.Timer
+}
+
+// NewSessionManager creates a new SessionManager
+// idleTimeout: how long a session stays open after its last inspection (closed immediately if zero)
+// options: options of the nbdkit-vddk sessions (defaults if nil)
+// logger: logger instance for logging (can be nil)
+func NewSessionManager(idleTimeout time.Duration, options *SessionOptions, logger *logrus.Logger) *SessionManager {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	logger = newRedactor().logger(logger)
+	if options == nil {
+		options = &SessionOptions{}
+	}
+	return &SessionManager{
+		idleTimeout: idleTimeout,
+		options:     *options,
+		logger:      logger,
+		open:        openDiskSessions,
+		sessions:    make(map[string]*sharedSession),
+	}
+}
+
+// Acquire returns the NBD URLs of the disks of the VM snapshot, opening the session if needed
+// Concurrent calls for the same VM snapshot wait for the first call to open the session
+// The returned function releases the session and must be called once the NBD URLs are no longer used
+func (m *SessionManager) Acquire(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]string, func(), error) {
+	// The password is redacted from the logs and errors of the session, including tool output
+	secrets := newRedactor(password)
+	nbdURLs, release, err := m.acquire(ctx, secrets.logger(m.logger), vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	return nbdURLs, release, secrets.redactError(err)
+}
+
+// acquire returns the NBD URLs of the disks of the VM snapshot, opening the session if needed
+func (m *SessionManager) acquire(
+	ctx context.Context,
+	logger *logrus.Logger,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]string, func(), error) {
+	key := sessionKey(vmName, snapshotName, vcenterURL, datacenter, username, diskInfo)
+
+	m.mu.Lock()
+	session, found := m.sessions[key]
+	if found {
+		session.refs++
+		if session.idle != nil {
+			session.idle.Stop()
+			session.idle = nil
+		}
+	} else {
+		session = &sharedSession{ready: make(chan struct{}), refs: 1}
+		m.sessions[key] = session
+	}
+	m.mu.Unlock()
+
+	if !found {
+		// The session outlives the inspection opening it, so it is not bound to its context
+		startedAt := time.Now()
+		session.nbdURLs, session.close, session.err = m.open(
+			context.WithoutCancel(ctx), logger, m.options, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+		m.options.Metrics.observeSessionStart(time.Since(startedAt), session.err)
+		if session.err != nil {
+			m.mu.Lock()
+			delete(m.sessions, key)
+			m.mu.Unlock()
+		} else {
+			m.options.Metrics.sessionOpened()
+			closeSession := session.close
+			session.close = func() {
+				closeSession()
+				m.options.Metrics.sessionClosed()
+			}
+		}
+		close(session.ready)
+	} else {
+		m.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+		}).Debug("Reusing NBD session")
+
+		select {
+		case <-session.ready:
+		case <-ctx.Done():
+			m.release(key, session)
+			return nil, nil, ctx.Err()
+		}
+	}
+	if session.err != nil {
+		return nil, nil, session.err
+	}
+
+	var once sync.Once
+	return session.nbdURLs, func() { once.Do(func() { m.release(key, session) }) }, nil
+}
+
+// release drops a reference to a session and closes it, or schedules its closing, once unused
+func (m *SessionManager) release(key string, session *sharedSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session.refs--
+	if session.refs > 0 || session.err != nil || m.sessions[key] != session {
+		return
+	}
+	if m.idleTimeout <= 0 {
+		delete(m.sessions, key)
+		go session.close()
+		return
+	}
+	session.idle = time.AfterFunc(m.idleTimeout, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if session.refs == 0 && m.sessions[key] == session {
+			delete(m.sessions, key)
+			go session.close()
+		}
+	})
+}
+
+// Close closes all unused sessions immediately
+// Sessions in use are closed after they are released, like with no Close call
+func (m *SessionManager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, session := range m.sessions {
+		select {
+		case <-session.ready:
+		default:
+			continue // Still opening
+		}
+		if session.refs > 0 {
+			continue
+		}
+		if session.idle != nil {
+			session.idle.Stop()
+		}
+		delete(m.sessions, key)
+		session.close()
+	}
+}
+
+// sessionKey identifies the NBD session of a VM snapshot
+func sessionKey(vmName, snapshotName, vcenterURL, datacenter, username string, diskInfo *types.SnapshotDiskInfo) string {
+	parts := []string{vcenterURL, username, datacenter, vmName, snapshotName}
+	if diskInfo != nil {
+		parts = append(parts, diskInfo.VMMoref, diskInfo.SnapshotMoref, diskInfo.BaseDiskPath)
+		for _, disk := range diskInfo.ExtraDisks {
+			parts = append(parts, disk.BaseDiskPath)
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// openDiskSessions opens an NBD session for each disk of the VM snapshot
+// Opening is canceled if the sessions are not ready within StartTimeout, including retries and fallbacks
+func openDiskSessions(
+	ctx context.Context,
+	logger *logrus.Logger,
+	options SessionOptions,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]string, func(), error) {
+	if options.StartTimeout <= 0 {
+		return startDiskSessions(ctx, logger, options, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	}
+
+	// The session processes are bound to openCtx, which is canceled on expiry or when the sessions are closed
+	openCtx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(options.StartTimeout, cancel)
+	nbdURLs, sessionCloser, err := startDiskSessions(openCtx, logger, options, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if !timer.Stop() {
+		if err == nil {
+			sessionCloser()
+		}
+		cancel()
+		return nil, nil, fmt.Errorf("NBD session not ready after %s: %w", options.StartTimeout, context.DeadlineExceeded)
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return nbdURLs, func() {
+		sessionCloser()
+		cancel()
+	}, nil
+}
+
+// startDiskSessions starts an NBD session for each disk of the VM snapshot
+// Up to MaxParallelDisks sessions are started and waited for concurrently, so VMs with many disks don't
+// wait for each disk in turn; if any disk fails, the sessions of the other disks are closed
+func startDiskSessions(
+	ctx context.Context,
+	logger *logrus.Logger,
+	options SessionOptions,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]string, func(), error) {
+	// virt-v2v-open serves the VM, not a disk
+	if UseVirtV2VOpen && options.Transport != TransportSSH {
+		nbdURL, sessionCloser, err := openNBDSession(ctx, logger, options, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []string{nbdURL}, sessionCloser, nil
+	}
+	// The other NBD servers serve the disk files of the snapshot
+	if diskInfo == nil {
+		return nil, nil, fmt.Errorf("snapshot disk info is required to open the NBD sessions of the disks")
+	}
+
+	disks := diskInfo.Disks()
+	maxParallel := options.MaxParallelDisks
+	if maxParallel <= 0 {
+		maxParallel = defaultMaxParallelDisks
+	}
+
+	nbdURLs := make([]string, len(disks))
+	closers := make([]func(), len(disks))
+	errs := make([]error, len(disks))
+	slots := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+	for idx, disk := range disks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			nbdURLs[idx], closers[idx], errs[idx] = openNBDSession(ctx, logger, options, vmName, snapshotName, vcenterURL, datacenter, username, password, disk)
+		}()
+	}
+	wg.Wait()
+
+	sessionCloser := func() {
+		for _, closer := range closers {
+			if closer != nil {
+				closer()
+			}
+		}
+	}
+	for idx, err := range errs {
+		if err != nil {
+			sessionCloser()
+			if len(disks) > 1 {
+				err = fmt.Errorf("disk %s: %w", disks[idx].BaseDiskPath, err)
+			}
+			return nil, nil, err
+		}
+	}
+	return nbdURLs, sessionCloser, nil
+}
+
+// openNBDSession opens an NBD session for the VM snapshot with the transport of the options: VDDK
+// (virt-v2v-open or nbdkit-vddk) with an optional HTTPS fallback, or SSH
+// The session lives until the returned function closes it or ctx is canceled
+func openNBDSession(
+	ctx context.Context,
+	logger *logrus.Logger,
+	options SessionOptions,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) (string, func(), error) {
+	switch options.Transport {
+	case "", TransportVDDK:
+	case TransportSSH:
+		return openSSHSession(ctx, logger, options, vmName, snapshotName, diskInfo)
+	default:
+		return "", nil, fmt.Errorf("unknown disk transport %q", options.Transport)
+	}
+
+	var nbdURL string
+	var sessionCloser func()
+	err := options.Retry.do(ctx, logger, "VDDK session", func() (err error) {
+		nbdURL, sessionCloser, err = openVDDKSession(ctx, logger, options, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+		return err
+	})
+	// The HTTPS fallback downloads the disk files of diskInfo, virt-v2v-open sessions may have none
+	if err == nil || !options.HTTPS.Fallback || diskInfo == nil {
+		return nbdURL, sessionCloser, err
+	}
+
+	logger.WithError(err).Warn("VDDK session failed, falling back to the HTTPS datastore file API")
+	nbdURL, sessionCloser, httpsErr := openHTTPSSession(ctx, logger, options, vcenterURL, datacenter, username, password, diskInfo)
+	if httpsErr != nil {
+		return "", nil, fmt.Errorf("%w (HTTPS fallback failed: %v)", err, httpsErr)
+	}
+	return nbdURL, sessionCloser, nil
+}
+
+// openVDDKSession opens an NBD session for the VM snapshot using virt-v2v-open or nbdkit-vddk
+func openVDDKSession(
+	ctx context.Context,
+	logger *logrus.Logger,
+	options SessionOptions,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) (string, func(), error) {
+	var nbdURL string
+	var sessionCloser func()
+
+	if UseVirtV2VOpen {
+		logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+			"vcenter_url":   vcenterURL,
+			"datacenter":    datacenter,
+		}).Info("Opening NBD session using virt-v2v-open (VDDK + snapshot)")
+
+		// The session process is bound to openCtx, so cancel it only when the session is closed
+		openCtx, cancel := context.WithCancel(ctx)
+
+		v2vSession, err := OpenWithVirtV2V(
+			openCtx,
+			vmName,
+			datacenter,
+			snapshotName,
+			vcenterURL,
+			username,
+			password,
+			options,
+			logger,
+		)
+		if err != nil {
+			cancel()
+			return "", nil, err
+		}
+		nbdURL = v2vSession.NBDURL
+		sessionCloser = func() {
+			v2vSession.Close()
+			cancel()
+		}
+
+		emit(ctx, InspectionEvent{Phase: PhaseNBDServerStarted, Disk: diskName(diskInfo)})
+		if err := v2vSession.WaitForReady(ctx, options.readyTimeout()); err != nil {
+			logger.WithError(err).Error("NBD server not ready")
+			sessionCloser()
+			return "", nil, fmt.Errorf("NBD server not ready: %w", err)
+		}
+		emit(ctx, InspectionEvent{Phase: PhaseNBDReady, Disk: diskName(diskInfo)})
+	} else {
+		logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+			"vcenter_url":   vcenterURL,
+			"datacenter":    datacenter,
+		}).Info("Opening NBD session using nbdkit-vddk (VDDK + snapshot)")
+
+		// Use diskInfo passed from vm_service (no need to query vSphere here)
+		logger.WithFields(logrus.Fields{
+			"vm_moref":       diskInfo.VMMoref,
+			"snapshot_moref": diskInfo.SnapshotMoref,
+			"disk_path":      diskInfo.DiskPath,
+			"base_disk_path": diskInfo.BaseDiskPath,
+		}).Debug("Using snapshot disk info from vm_service")
+
+		// The session process is bound to openCtx, so cancel it only when the session is closed
+		openCtx, cancel := context.WithCancel(ctx)
+
+		nbdkitSession, err := OpenWithNBDKitVDDK(
+			openCtx,
+			diskInfo.VMMoref,
+			diskInfo.SnapshotMoref,
+			diskInfo.BaseDiskPath,
+			vcenterURL,
+			username,
+			password,
+			options,
+			logger,
+		)
+		if err != nil {
+			cancel()
+			return "", nil, err
+		}
+		nbdURL = nbdkitSession.NBDURL
+		sessionCloser = func() {
+			nbdkitSession.Close()
+			cancel()
+		}
+
+		emit(ctx, InspectionEvent{Phase: PhaseNBDServerStarted, Disk: diskName(diskInfo)})
+		if err := nbdkitSession.WaitForReady(ctx, options.readyTimeout()); err != nil {
+			logger.WithError(err).Error("NBD server not ready")
+			sessionCloser()
+			return "", nil, fmt.Errorf("NBD server not ready: %w", err)
+		}
+		emit(ctx, InspectionEvent{Phase: PhaseNBDReady, Disk: diskName(diskInfo)})
+	}
+
+	return nbdURL, sessionCloser, nil
+}
+
+// openSSHSession opens an NBD session for the VM snapshot reading its disk files over SSH
+func openSSHSession(
+	ctx context.Context,
+	logger *logrus.Logger,
+	options SessionOptions,
+	vmName string,
+	snapshotName string,
+	diskInfo *types.SnapshotDiskInfo,
+) (string, func(), error) {
+	logger.WithFields(logrus.Fields{
+		"vm_name":       vmName,
+		"snapshot_name": snapshotName,
+		"esxi_host":     options.SSH.Host,
+		"disk_path":     diskInfo.DiskPath,
+	}).Info("Opening NBD session using qemu-nbd (SSH + snapshot)")
+
+	// The session process is bound to openCtx, so cancel it only when the session is closed
+	openCtx, cancel := context.WithCancel(ctx)
+
+	qemuNBDSession, err := OpenWithQemuNBDSSH(openCtx, diskInfo.DiskPath, options.SSH, options.Executor, logger)
+	if err != nil {
+		cancel()
+		return "", nil, err
+	}
+	sessionCloser := func() {
+		qemuNBDSession.Close()
+		cancel()
+	}
+
+	emit(ctx, InspectionEvent{Phase: PhaseNBDServerStarted, Disk: diskName(diskInfo)})
+	if err := qemuNBDSession.WaitForReady(ctx, options.readyTimeout()); err != nil {
+		logger.WithError(err).Error("NBD server not ready")
+		sessionCloser()
+		return "", nil, fmt.Errorf("NBD server not ready: %w", err)
+	}
+	emit(ctx, InspectionEvent{Phase: PhaseNBDReady, Disk: diskName(diskInfo)})
+	return qemuNBDSession.NBDURL, sessionCloser, nil
+}
+
+// openHTTPSSession opens an NBD session for the VM snapshot reading its disk with the HTTPS datastore file API
+func openHTTPSSession(
+	ctx context.Context,
+	logger *logrus.Logger,
+	options SessionOptions,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) (string, func(), error) {
+	// The session process is bound to openCtx, so cancel it only when the session is closed
+	openCtx, cancel := context.WithCancel(ctx)
+
+	curlSession, err := OpenWithNBDKitCurl(openCtx, diskInfo.DiskPath, vcenterURL, datacenter, username, password, options, logger)
+	if err != nil {
+		cancel()
+		return "", nil, err
+	}
+	sessionCloser := func() {
+		curlSession.Close()
+		cancel()
+	}
+
+	emit(ctx, InspectionEvent{Phase: PhaseNBDServerStarted, Disk: diskName(diskInfo)})
+	if err := curlSession.WaitForReady(ctx, options.readyTimeout()); err != nil {
+		sessionCloser()
+		return "", nil, fmt.Errorf("NBD server not ready: %w", err)
+	}
+	emit(ctx, InspectionEvent{Phase: PhaseNBDReady, Disk: diskName(diskInfo)})
+	return curlSession.NBDURL, sessionCloser, nil
+}
+
+// diskName returns the disk path of the disk info reported in events
+func diskName(diskInfo *types.SnapshotDiskInfo) string {
+	if diskInfo == nil {
+		return ""
+	}
+	return diskInfo.BaseDiskPath
+}
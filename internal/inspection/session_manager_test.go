@@ -0,0 +1,225 @@
+package inspection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSessionManagerRequiresDiskInfo(t *testing.T) {
+	tests := map[string]SessionOptions{
+		"nbdkit-vddk":    {},
+		"https fallback": {HTTPS: HTTPSOptions{Fallback: true}},
+		"ssh":            {Transport: TransportSSH},
+		"start timeout":  {StartTimeout: time.Minute},
+	}
+	for name, options := range tests {
+		t.Run(name, func(t *testing.T) {
+			manager := NewSessionManager(0, &options, nil)
+			for range 2 { // The failed session is not kept
+				_, _, err := manager.Acquire(context.Background(), "vm", "snapshot", "https://vcenter", "DC1", "user", "secret", nil)
+				if err == nil || !strings.Contains(err.Error(), "snapshot disk info is required") {
+					t.Fatalf("Acquire() error = %v, want a missing disk info error", err)
+				}
+			}
+		})
+	}
+}
+
+// stubSessions opens stub NBD sessions and counts the sessions opened and closed
+type stubSessions struct {
+	opened atomic.Int32
+	closed atomic.Int32
+	gate   chan struct{} // Opening waits until gate is closed (nil to open immediately)
+	err    error         // Error of the next opening
+}
+
+func (s *stubSessions) open(ctx context.Context, logger *logrus.Logger, options SessionOptions, vmName, snapshotName, vcenterURL, datacenter, username, password string, diskInfo *types.SnapshotDiskInfo) ([]string, func(), error) {
+	if s.gate != nil {
+		<-s.gate
+	}
+	if err := s.err; err != nil {
+		s.err = nil
+		return nil, nil, err
+	}
+	opened := s.opened.Add(1)
+	return []string{fmt.Sprintf("nbd+unix:///?socket=/run/%s-%s-%d.sock", vmName, snapshotName, opened)}, func() { s.closed.Add(1) }, nil
+}
+
+// waitFor waits until condition is true
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// newStubSessionManager returns a session manager opening the sessions of stub
+func newStubSessionManager(idleTimeout time.Duration, stub *stubSessions) *SessionManager {
+	manager := NewSessionManager(idleTimeout, nil, nil)
+	manager.open = stub.open
+	return manager
+}
+
+// acquire acquires the session of the snapshot of vm-1, it can be called from other goroutines
+func acquire(t *testing.T, manager *SessionManager, snapshotName string) (string, func()) {
+	t.Helper()
+	nbdURLs, release, err := manager.Acquire(context.Background(), "vm-1", snapshotName, "https://vcenter", "DC1", "user", "secret", &types.SnapshotDiskInfo{})
+	if err != nil {
+		t.Errorf("Acquire() error = %v", err)
+		return "", func() {}
+	}
+	return nbdURLs[0], release
+}
+
+func TestSessionManagerSharesConcurrentAcquires(t *testing.T) {
+	stub := &stubSessions{gate: make(chan struct{})}
+	manager := newStubSessionManager(0, stub)
+
+	const acquires = 5
+	urls := make(chan string, acquires)
+	releases := make(chan func(), acquires)
+	for range acquires {
+		go func() {
+			nbdURL, release := acquire(t, manager, "snap-1")
+			urls <- nbdURL
+			releases <- release
+		}()
+	}
+	waitFor(t, "all acquires", func() bool {
+		manager.mu.Lock()
+		defer manager.mu.Unlock()
+		session := manager.sessions[sessionKey("vm-1", "snap-1", "https://vcenter", "DC1", "user", &types.SnapshotDiskInfo{})]
+		return session != nil && session.refs == acquires
+	})
+	close(stub.gate)
+
+	first := <-urls
+	for range acquires - 1 {
+		if nbdURL := <-urls; nbdURL != first {
+			t.Errorf("Acquire() = %s, want the shared session %s", nbdURL, first)
+		}
+	}
+	if opened := stub.opened.Load(); opened != 1 {
+		t.Fatalf("%d sessions opened, want 1", opened)
+	}
+
+	for range acquires - 1 {
+		(<-releases)()
+	}
+	time.Sleep(10 * time.Millisecond)
+	if closed := stub.closed.Load(); closed != 0 {
+		t.Fatal("session closed while in use")
+	}
+	release := <-releases
+	release()
+	release() // Releasing twice drops one reference
+	waitFor(t, "the session to close", func() bool { return stub.closed.Load() == 1 })
+}
+
+func TestSessionManagerSeparatesSnapshots(t *testing.T) {
+	stub := &stubSessions{}
+	manager := newStubSessionManager(0, stub)
+
+	url1, release1 := acquire(t, manager, "snap-1")
+	url2, release2 := acquire(t, manager, "snap-2")
+	if url1 == url2 {
+		t.Errorf("snapshots share session %s", url1)
+	}
+	release1()
+	release2()
+	waitFor(t, "both sessions to close", func() bool { return stub.closed.Load() == 2 })
+}
+
+func TestSessionManagerIdleTimeout(t *testing.T) {
+	stub := &stubSessions{}
+	manager := newStubSessionManager(50*time.Millisecond, stub)
+
+	url1, release := acquire(t, manager, "snap-1")
+	release()
+	url2, release := acquire(t, manager, "snap-1") // Within the idle timeout
+	if url2 != url1 || stub.opened.Load() != 1 {
+		t.Errorf("Acquire() = %s after release, want the idle session %s", url2, url1)
+	}
+	release()
+
+	waitFor(t, "the idle session to close", func() bool { return stub.closed.Load() == 1 })
+	if url3, release := acquire(t, manager, "snap-1"); url3 == url1 {
+		t.Error("Acquire() returned a closed session")
+	} else {
+		release()
+	}
+}
+
+func TestSessionManagerDoesNotKeepFailedSessions(t *testing.T) {
+	stub := &stubSessions{err: errors.New("connection refused")}
+	manager := newStubSessionManager(time.Hour, stub)
+
+	if _, _, err := manager.Acquire(context.Background(), "vm-1", "snap-1", "https://vcenter", "DC1", "user", "secret", &types.SnapshotDiskInfo{}); err == nil {
+		t.Fatal("Acquire() error = nil for a failed session")
+	}
+	_, release := acquire(t, manager, "snap-1")
+	defer release()
+	if opened := stub.opened.Load(); opened != 1 {
+		t.Errorf("%d sessions opened after a failed session, want 1", opened)
+	}
+}
+
+func TestSessionManagerAcquireCancelled(t *testing.T) {
+	stub := &stubSessions{gate: make(chan struct{})}
+	manager := newStubSessionManager(0, stub)
+
+	opening := make(chan func())
+	go func() {
+		_, release := acquire(t, manager, "snap-1")
+		opening <- release
+	}()
+	waitFor(t, "the session to be opening", func() bool {
+		manager.mu.Lock()
+		defer manager.mu.Unlock()
+		return len(manager.sessions) == 1
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := manager.Acquire(ctx, "vm-1", "snap-1", "https://vcenter", "DC1", "user", "secret", &types.SnapshotDiskInfo{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire() error = %v, want context.Canceled", err)
+	}
+	close(stub.gate)
+	release := <-opening
+	release()
+	waitFor(t, "the session to close", func() bool { return stub.closed.Load() == 1 })
+}
+
+func TestSessionManagerClose(t *testing.T) {
+	stub := &stubSessions{}
+	manager := newStubSessionManager(time.Hour, stub)
+
+	_, releaseIdle := acquire(t, manager, "snap-1")
+	releaseIdle()
+	_, releaseInUse := acquire(t, manager, "snap-2")
+
+	manager.Close()
+	if closed := stub.closed.Load(); closed != 1 {
+		t.Fatalf("Close() closed %d sessions, want the idle session", closed)
+	}
+	releaseInUse()
+	if closed := stub.closed.Load(); closed != 1 {
+		t.Errorf("released session closed before its idle timeout")
+	}
+	manager.Close()
+	if closed := stub.closed.Load(); closed != 2 {
+		t.Errorf("Close() closed %d sessions, want both", closed)
+	}
+}
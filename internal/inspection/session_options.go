@@ -0,0 +1,105 @@
+package inspection
+
+import (
+	"fmt"
+	"time"
+)
+
+// Transports used to read the snapshot disks
+const (
+	// TransportVDDK reads the disks through vCenter with VDDK (nbdkit-vddk or virt-v2v-open)
+	TransportVDDK = "vddk"
+	// TransportSSH reads the disk files from the ESXi datastore over SSH with qemu-nbd
+	TransportSSH = "ssh"
+)
+
+// SessionOptions configures the NBD sessions opened to read the snapshot disks
+type SessionOptions struct {
+	Transport string // TransportVDDK (default) or TransportSSH
+	VDDK      VDDKOptions
+	Filters   NBDKitFilters // Only used by nbdkit (VDDK and HTTPS)
+	SSH       SSHOptions    // Only used by TransportSSH
+	HTTPS     HTTPSOptions  // Fallback of TransportVDDK
+
+	// Retry retries opening sessions and running virt-inspector and guestfish on transient failures
+	Retry RetryPolicy
+
+	// MaxParallelDisks limits the disk sessions of a VM snapshot started concurrently (4 if zero)
+	MaxParallelDisks int
+
+	// StartTimeout limits opening the session of a VM snapshot, including retries and fallbacks (no limit if zero)
+	// The inspections using the session are limited by the timeout of the inspector
+	StartTimeout time.Duration
+	// ReadyTimeout limits the wait for a started NBD server to open its export (30s if zero)
+	ReadyTimeout time.Duration
+
+	// Metrics collects the startup times and the number of open sessions (optional)
+	Metrics *Metrics
+
+	// Executor runs the NBD servers, virt-inspector and guestfish (on the local host if nil)
+	Executor Executor
+}
+
+// defaultMaxParallelDisks is the number of disk sessions started concurrently if not configured
+const defaultMaxParallelDisks = 4
+
+// defaultReadyTimeout is how long a new NBD server may take to open its export if not configured
+const defaultReadyTimeout = 30 * time.Second
+
+// executor returns the executor of the NBD servers and libguestfs tools
+func (o SessionOptions) executor() Executor {
+	return executorOrLocal(o.Executor)
+}
+
+// readyTimeout returns how long a new NBD server may take to open its export
+func (o SessionOptions) readyTimeout() time.Duration {
+	if o.ReadyTimeout > 0 {
+		return o.ReadyTimeout
+	}
+	return defaultReadyTimeout
+}
+
+// NBDKitFilters selects the nbdkit filters layered on the VDDK plugin
+// Caching and readahead speed up the many small reads of virt-inspector and guestfish over
+// high-latency vCenter links
+type NBDKitFilters struct {
+	// Cache keeps blocks read from vCenter in a local temporary file (nbdkit-cache-filter)
+	Cache          bool
+	CacheMaxSize   string // Maximum size of the cache (e.g., "2G"), unlimited if empty
+	CacheBlockSize string // Minimum block size read and cached (e.g., "256K"), nbdkit default if empty
+
+	// Readahead prefetches the data following sequential reads (nbdkit-readahead-filter)
+	// Prefetched data is kept by the cache filter, so Readahead is most useful with Cache
+	Readahead bool
+
+	// COW serves a writable copy-on-write overlay of the read-only snapshot (nbdkit-cow-filter),
+	// so tools replaying filesystem journals can write without touching the snapshot
+	COW          bool
+	COWBlockSize string // Block size of the overlay (e.g., "64K"), nbdkit default if empty
+}
+
+// nbdkitArgs returns the nbdkit options enabling the filters and their parameters
+// Filters are listed from the client side: cow, readahead, then cache above the plugin,
+// so readahead fills the cache
+func (f NBDKitFilters) nbdkitArgs() (filters []string, params []string) {
+	if f.COW {
+		filters = append(filters, "--filter=cow")
+		if f.COWBlockSize != "" {
+			params = append(params, fmt.Sprintf("cow-block-size=%s", f.COWBlockSize))
+		}
+	}
+	if f.Readahead {
+		filters = append(filters, "--filter=readahead")
+	}
+	if f.Cache {
+		filters = append(filters, "--filter=cache")
+		params = append(params, "cache-on-read=true")
+		if f.CacheMaxSize != "" {
+			params = append(params, fmt.Sprintf("cache-max-size=%s", f.CacheMaxSize))
+		}
+		if f.CacheBlockSize != "" {
+			params = append(params, fmt.Sprintf("cache-min-block-size=%s", f.CacheBlockSize))
+		}
+	}
+	return filters, params
+}
@@ -0,0 +1,258 @@
+package inspection
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// SSHExecutor runs the commands on a remote conversion host over SSH, for validation services that cannot
+// reach the VDDK network of vCenter and the ESXi hosts
+// The conversion host must provide the tools and VDDK. Sockets, password files and key files are created in
+// its temporary directory, the NBD servers are probed and the tool output is streamed back over SSH
+// Authentication uses the keys of the running ssh-agent and IdentityFile, and the host key must be in
+// known_hosts. Sharing connections (e.g. SSHArgs "-o", "ControlMaster=auto", "-o", "ControlPath=...",
+// "-o", "ControlPersist=60") avoids an SSH handshake per command and NBD probe
+type SSHExecutor struct {
+	Host         string   // Conversion host name or address
+	User         string   // Defaults to the local user
+	Port         int      // Defaults to 22
+	IdentityFile string   // Private key file (optional)
+	SSHArgs      []string // Extra ssh arguments
+}
+
+// sshArgs returns the ssh arguments connecting to the conversion host, with options before the destination
+func (e SSHExecutor) sshArgs(options ...string) []string {
+	args := []string{"-o", "BatchMode=yes"} // Fail instead of prompting for passwords or host keys
+	if e.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(e.Port))
+	}
+	if e.IdentityFile != "" {
+		args = append(args, "-i", e.IdentityFile)
+	}
+	args = append(args, e.SSHArgs...)
+	args = append(args, options...)
+	destination := e.Host
+	if e.User != "" {
+		destination = e.User + "@" + e.Host
+	}
+	return append(args, "--", destination)
+}
+
+// identity identifies the conversion host and the user running the commands
+func (e SSHExecutor) identity() string {
+	return fmt.Sprintf("ssh %s@%s:%d", e.User, e.Host, cmp.Or(e.Port, 22))
+}
+
+// Command returns the command running name with args on the conversion host
+// The standard streams are forwarded over SSH. The remote command is not signaled when the command is
+// stopped: nbdkit exits with its SSH session (--exit-with-parent), the other tools once they write to it
+func (e SSHExecutor) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	words := []string{"exec", shellQuote(name)}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	return exec.CommandContext(ctx, "ssh", append(e.sshArgs(), strings.Join(words, " "))...)
+}
+
+// VDDKLibrary returns the VDDK library of the conversion host in libDir, the first default location if empty
+// The library is not verified, nbdkit fails to load it if it is missing from the conversion host
+func (e SSHExecutor) VDDKLibrary(libDir string) (*VDDKLibrary, error) {
+	if e.Host == "" {
+		return nil, fmt.Errorf("conversion host is not configured")
+	}
+	return unverifiedVDDKLibrary(libDir), nil
+}
+
+// TempDir creates a new temporary directory on the conversion host, only accessible by the remote user
+func (e SSHExecutor) TempDir(ctx context.Context, prefix string) (string, error) {
+	output, err := e.run(ctx, nil, `mktemp -d "${TMPDIR:-/tmp}"/`+shellQuote(prefix+"-XXXXXX"))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory on %s: %w", e.Host, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// WriteFile writes data to a file on the conversion host, only readable by the remote user
+// data is sent on the standard input of the remote shell, so it does not appear in process tables
+func (e SSHExecutor) WriteFile(ctx context.Context, path string, data []byte) error {
+	if _, err := e.run(ctx, data, "umask 077 && cat > "+shellQuote(path)); err != nil {
+		return fmt.Errorf("failed to write %s on %s: %w", path, e.Host, err)
+	}
+	return nil
+}
+
+// RemoveAll removes path and any children it contains from the conversion host
+func (e SSHExecutor) RemoveAll(ctx context.Context, path string) error {
+	if _, err := e.run(ctx, nil, "rm -rf -- "+shellQuote(path)); err != nil {
+		return fmt.Errorf("failed to remove %s on %s: %w", path, e.Host, err)
+	}
+	return nil
+}
+
+// Fetch copies dir from the conversion host to a new local temporary directory, streamed as a tar archive
+// Symbolic links are copied as links, they are not followed
+func (e SSHExecutor) Fetch(ctx context.Context, dir string) (string, func(), error) {
+	localDir, err := os.MkdirTemp("", "ssh-fetch-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	remove := trackTempPath(LocalExecutor{}, localDir)
+
+	remote := e.Command(ctx, "tar", "-C", dir, "-cf", "-", ".")
+	remoteStderr := newOutputBuffer(maxToolOutput)
+	remote.Stderr = remoteStderr
+	archive, err := remote.StdoutPipe()
+	if err != nil {
+		remove()
+		return "", nil, err
+	}
+	local := exec.CommandContext(ctx, "tar", "-C", localDir, "-xf", "-")
+	local.Stdin = archive
+	localStderr := newOutputBuffer(maxToolOutput)
+	local.Stderr = localStderr
+
+	if err := remote.Start(); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to start ssh: %w", err)
+	}
+	localErr := local.Run()
+	if localErr != nil {
+		_ = remote.Process.Kill()
+	}
+	remoteErr := remote.Wait()
+	switch {
+	case remoteErr != nil && (localErr == nil || remoteStderr.Len() > 0):
+		// A remote failure (e.g. a missing directory) also fails the local tar, report its cause
+		remove()
+		return "", nil, newToolError("ssh", remoteErr, remoteStderr)
+	case localErr != nil:
+		remove()
+		return "", nil, newToolError("tar", localErr, localStderr)
+	}
+	return localDir, remove, nil
+}
+
+// Dial connects to a unix socket or TCP address of the conversion host, forwarding the connection over SSH
+// The connection is closed when ctx is done, deadlines are not supported
+func (e SSHExecutor) Dial(ctx context.Context, network string, address string) (net.Conn, error) {
+	cmd := exec.CommandContext(ctx, "ssh", e.sshArgs("-W", address)...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := newOutputBuffer(maxToolOutput)
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ssh: %w", err)
+	}
+
+	// ssh starts before the remote connection is opened, so wait for the server to send its first bytes
+	// (the NBD greeting) to report servers that are not listening yet
+	reader := bufio.NewReader(stdout)
+	if _, err := reader.Peek(1); err != nil {
+		_ = cmd.Process.Kill()
+		waitErr := cmd.Wait()
+		if strings.Contains(stderr.String(), "open failed") {
+			return nil, fmt.Errorf("failed to connect to %s on %s: %w", address, e.Host, syscall.ECONNREFUSED)
+		}
+		if waitErr != nil {
+			return nil, newToolError("ssh", waitErr, stderr)
+		}
+		return nil, fmt.Errorf("failed to connect to %s on %s: %w", address, e.Host, err)
+	}
+	return &sshConn{cmd: cmd, reader: reader, writer: stdin, addr: sshAddr{network: network, address: address}}, nil
+}
+
+// run runs a shell script on the conversion host with stdin as its standard input and returns its output
+func (e SSHExecutor) run(ctx context.Context, stdin []byte, script string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "ssh", append(e.sshArgs(), script)...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	stderr := newOutputBuffer(maxToolOutput)
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, newToolError("ssh", err, stderr)
+	}
+	return output, nil
+}
+
+// shellQuote quotes s as a single word of a POSIX shell command line
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sshConn is a connection forwarded by an ssh process over its standard streams
+type sshConn struct {
+	cmd    *exec.Cmd
+	reader io.Reader
+	writer io.WriteCloser
+	addr   sshAddr
+
+	closeOnce sync.Once
+	mu        sync.Mutex
+	deadline  *time.Timer // Closes the connection at its deadline
+}
+
+func (c *sshConn) Read(p []byte) (int, error)  { return c.reader.Read(p) }
+func (c *sshConn) Write(p []byte) (int, error) { return c.writer.Write(p) }
+
+// Close closes the connection and stops the ssh process
+func (c *sshConn) Close() error {
+	c.closeOnce.Do(func() {
+		_ = c.writer.Close()
+		_ = c.cmd.Process.Kill()
+		_ = c.cmd.Wait()
+	})
+	return nil
+}
+
+func (c *sshConn) LocalAddr() net.Addr  { return c.addr }
+func (c *sshConn) RemoteAddr() net.Addr { return c.addr }
+
+// SetDeadline closes the connection at t, since the standard streams of ssh have no deadlines
+// Reads and writes pending at t fail, and unlike network connections the connection cannot be used afterwards
+func (c *sshConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deadline != nil {
+		c.deadline.Stop()
+		c.deadline = nil
+	}
+	if !t.IsZero() {
+		c.deadline = time.AfterFunc(time.Until(t), func() { _ = c.Close() })
+	}
+	return nil
+}
+
+// SetReadDeadline sets the deadline of reads and writes, they share the ssh process
+func (c *sshConn) SetReadDeadline(t time.Time) error { return c.SetDeadline(t) }
+
+// SetWriteDeadline sets the deadline of reads and writes, they share the ssh process
+func (c *sshConn) SetWriteDeadline(t time.Time) error { return c.SetDeadline(t) }
+
+// sshAddr is the address of a connection forwarded over SSH
+type sshAddr struct {
+	network string
+	address string
+}
+
+func (a sshAddr) Network() string { return a.network }
+func (a sshAddr) String() string  { return a.address }
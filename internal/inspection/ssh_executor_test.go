@@ -0,0 +1,90 @@
+package inspection
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"":                    `''`,
+		"virt-inspector":      `'virt-inspector'`,
+		"/tmp/my dir/sock":    `'/tmp/my dir/sock'`,
+		"it's":                `'it'\''s'`,
+		"$(rm -rf /)":         `'$(rm -rf /)'`,
+		"a\nb":                "'a\nb'",
+		`back\slash "double"`: `'back\slash "double"'`,
+	}
+	for input, want := range tests {
+		if got := shellQuote(input); got != want {
+			t.Errorf("shellQuote(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestShellQuoteRoundTrip(t *testing.T) {
+	words := []string{"", "plain", "two words", "it's", `'''`, "$HOME `id` $(id) ;|&<>*?~", "tab\tnew\nline", `\\"`, "ünïcode"}
+	quoted := make([]string, len(words))
+	for idx, word := range words {
+		quoted[idx] = shellQuote(word)
+	}
+	// printf prints each argument on its own line terminated by a NUL, so empty words and newlines survive
+	output, err := exec.CommandContext(context.Background(), "sh", "-c", `printf '%s\0' `+strings.Join(quoted, " ")).Output()
+	if err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	if strings.Join(got, "|") != strings.Join(words, "|") || len(got) != len(words) {
+		t.Errorf("sh parsed %q, want %q", got, words)
+	}
+}
+
+func TestSSHExecutorCommand(t *testing.T) {
+	executor := SSHExecutor{Host: "conversion.example.com", User: "v2v", Port: 2222, IdentityFile: "/keys/id_ed25519", SSHArgs: []string{"-o", "ControlMaster=auto"}}
+	cmd := executor.Command(context.Background(), "nbdkit", "--exit-with-parent", "vddk", "file=[ds] vm/vm.vmdk")
+	want := []string{
+		"ssh", "-o", "BatchMode=yes", "-p", "2222", "-i", "/keys/id_ed25519", "-o", "ControlMaster=auto", "--", "v2v@conversion.example.com",
+		`exec 'nbdkit' '--exit-with-parent' 'vddk' 'file=[ds] vm/vm.vmdk'`,
+	}
+	if strings.Join(cmd.Args, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Args = %q, want %q", cmd.Args, want)
+	}
+}
+
+func TestSSHConnDeadline(t *testing.T) {
+	// A process that never writes stands in for a stalled ssh forwarding
+	cmd := exec.Command("sleep", "60")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	conn := &sshConn{cmd: cmd, reader: stdout, writer: stdin}
+	defer conn.Close()
+
+	// A cleared deadline does not close the connection
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Millisecond))
+	_ = conn.SetDeadline(time.Time{})
+	time.Sleep(50 * time.Millisecond)
+	if _, err := conn.Write([]byte("x")); err != nil {
+		t.Fatalf("Write() error = %v after the deadline was cleared", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	startedAt := time.Now()
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Error("Read() = nil after the deadline")
+	}
+	if elapsed := time.Since(startedAt); elapsed > 5*time.Second {
+		t.Errorf("Read() returned after %s", elapsed)
+	}
+}
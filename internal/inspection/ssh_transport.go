@@ -0,0 +1,169 @@
+package inspection
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SSHOptions configures access to the ESXi host storing the VM for the SSH transport
+// Authentication uses the keys of the running ssh-agent and the host key must be in known_hosts,
+// like virt-v2v -it ssh
+type SSHOptions struct {
+	Host string // ESXi host name or address
+	User string // Defaults to root
+	Port int    // Defaults to 22
+}
+
+// QemuNBDSession represents an NBD server session created by qemu-nbd reading a VMDK over SSH
+type QemuNBDSession struct {
+	NBDURL     string
+	socketPath string
+	executor   Executor
+	cmd        *exec.Cmd
+	stderrBuf  *outputBuffer
+	done       chan struct{} // Closed once the qemu-nbd process has exited
+	waitErr    error         // Exit error of the qemu-nbd process, set before done is closed
+	unregister func()        // Unregisters Close from the cleanup registry
+}
+
+// OpenWithQemuNBDSSH serves a snapshot disk read over SSH from the ESXi datastore as NBD, for
+// environments where VDDK cannot be installed
+// qemu-nbd opens the snapshot VMDK (e.g., "[datastore] vm/vm-000001.vmdk") and follows its chain of
+// parent VMDKs on the datastore, so the export is the disk content at the time of the snapshot
+// executor runs qemu-nbd (on the local host if nil)
+func OpenWithQemuNBDSSH(ctx context.Context, diskPath string, ssh SSHOptions, executor Executor, logger *logrus.Logger) (*QemuNBDSession, error) {
+	if ssh.Host == "" {
+		return nil, fmt.Errorf("ESXi host is required for the SSH transport")
+	}
+	datastorePath, err := datastoreFilePath(diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	host := ssh.Host
+	if ssh.Port != 0 {
+		host += ":" + strconv.Itoa(ssh.Port)
+	}
+	user := ssh.User
+	if user == "" {
+		user = "root"
+	}
+	diskURL := (&url.URL{Scheme: "ssh", User: url.User(user), Host: host, Path: datastorePath}).String()
+
+	executor = executorOrLocal(executor)
+	socketPath, err := newNBDSocket(ctx, executor, "qemu-nbd")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"--read-only",
+		"--persistent", // Serve several clients, like nbdkit
+		"--shared=0",   // No limit of concurrent clients
+		"--format=vmdk",
+		"--socket=" + socketPath,
+		diskURL,
+	}
+	if logger != nil {
+		logger.WithFields(logrus.Fields{
+			"command":     "qemu-nbd",
+			"args":        args,
+			"socket_path": socketPath,
+		}).Info("Starting qemu-nbd over SSH")
+	}
+
+	cmd := executor.Command(ctx, "qemu-nbd", args...)
+	stderrBuf := newOutputBuffer(maxToolOutput)
+	cmd.Stderr = stderrBuf
+	if err := cmd.Start(); err != nil {
+		removeNBDSocket(executor, socketPath)
+		return nil, fmt.Errorf("failed to start qemu-nbd: %w", err)
+	}
+
+	session := &QemuNBDSession{
+		NBDURL:     nbdUnixURL(socketPath),
+		socketPath: socketPath,
+		executor:   executor,
+		cmd:        cmd,
+		stderrBuf:  stderrBuf,
+		done:       make(chan struct{}),
+	}
+	go func() {
+		session.waitErr = cmd.Wait()
+		close(session.done)
+	}()
+	session.unregister = registerCleanup(session.Close)
+	return session, nil
+}
+
+// WaitForReady waits until the NBD server completes a handshake for its export
+func (s *QemuNBDSession) WaitForReady(ctx context.Context, timeout time.Duration) error {
+	_, err := waitForNBD(ctx, s.executor, s.NBDURL, timeout, func() error {
+		select {
+		case <-s.done:
+		default:
+			return nil
+		}
+		reason := "qemu-nbd process exited"
+		if s.waitErr != nil {
+			reason += ": " + s.waitErr.Error()
+		}
+		if s.stderrBuf.Len() > 0 {
+			return fmt.Errorf("%s (output: %s)", reason, s.stderrBuf.String())
+		}
+		return fmt.Errorf("%s", reason)
+	})
+	return err
+}
+
+// Close stops the qemu-nbd process and cleans up
+func (s *QemuNBDSession) Close() {
+	if s == nil {
+		return
+	}
+	if s.unregister != nil {
+		s.unregister()
+	}
+	if s.cmd != nil && s.cmd.Process != nil {
+		_ = s.cmd.Process.Signal(os.Interrupt)
+		select {
+		case <-s.done:
+		case <-time.After(5 * time.Second):
+			_ = s.cmd.Process.Kill()
+			<-s.done
+		}
+	}
+	if s.socketPath != "" {
+		removeNBDSocket(s.executor, s.socketPath)
+	}
+}
+
+// datastoreFilePath converts a datastore path ("[datastore] vm/disk.vmdk") to its path on the ESXi host
+func datastoreFilePath(diskPath string) (string, error) {
+	datastore, file, err := parseDatastorePath(diskPath)
+	if err != nil {
+		return "", err
+	}
+	return "/vmfs/volumes/" + datastore + "/" + file, nil
+}
+
+// parseDatastorePath splits a datastore path ("[datastore] vm/disk.vmdk") into datastore and file path
+func parseDatastorePath(diskPath string) (string, string, error) {
+	if !strings.HasPrefix(diskPath, "[") {
+		return "", "", fmt.Errorf("invalid datastore path %q", diskPath)
+	}
+	datastore, file, found := strings.Cut(diskPath[1:], "]")
+	file = strings.TrimSpace(file)
+	if !found || datastore == "" || file == "" {
+		return "", "", fmt.Errorf("invalid datastore path %q", diskPath)
+	}
+	return datastore, file, nil
+}
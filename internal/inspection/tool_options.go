@@ -0,0 +1,71 @@
+package inspection
+
+import "context"
+
+// VirtInspectorOptions configures the virt-inspector command line
+type VirtInspectorOptions struct {
+	// NoApplications skips the application inventory and icons of the guests (--no-applications --no-icon)
+	// Inspection is much faster on guests with many packages, Applications of the result is then empty
+	NoApplications bool
+
+	// Keys decrypt encrypted guest devices, they are also used by guestfish to read guest files
+	Keys []DecryptionKey
+
+	// ExtraArgs are appended to the virt-inspector arguments, e.g. "-v", "-x" for debugging
+	ExtraArgs []string
+
+	// OnEvent receives the progress of inspections and guest file reads (optional)
+	OnEvent EventHandler
+
+	// Metrics collects the counts, durations and failures of inspections (optional)
+	Metrics *Metrics
+}
+
+// args returns the virt-inspector arguments of the options
+func (o VirtInspectorOptions) args() []string {
+	var args []string
+	if o.NoApplications {
+		args = append(args, "--no-applications", "--no-icon")
+	}
+	return append(args, o.ExtraArgs...)
+}
+
+// checkFeatures returns a FeatureError if the virt-inspector at path does not support the options
+func (o VirtInspectorOptions) checkFeatures(ctx context.Context, executor Executor, path string) error {
+	if o.NoApplications {
+		if err := requireVersion(ctx, executor, "NoApplications", path, minNoApplicationsVersion); err != nil {
+			return err
+		}
+	}
+	if len(o.Keys) > 0 {
+		if err := requireVersion(ctx, executor, "decryption keys", path, minKeySelectorVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VirtV2vInspectorOptions configures the virt-v2v-inspector command line
+type VirtV2vInspectorOptions struct {
+	// Keys decrypt encrypted guest devices
+	Keys []DecryptionKey
+
+	// ExtraArgs are added to the virt-v2v-inspector arguments, before the VM name, e.g. "-v", "-x" for debugging
+	// The debug output is only kept in the error of failed inspections
+	ExtraArgs []string
+
+	// OnEvent receives the progress of inspections (optional)
+	OnEvent EventHandler
+
+	// Metrics collects the counts, durations and failures of inspections (optional)
+	Metrics *Metrics
+
+	// Executor runs virt-v2v-inspector reading the disks through its own VDDK connection (on the local host
+	// if nil), with a session manager virt-v2v-inspector runs with the executor of the sessions
+	Executor Executor
+}
+
+// executor returns the executor of virt-v2v-inspector
+func (o VirtV2vInspectorOptions) executor() Executor {
+	return executorOrLocal(o.Executor)
+}
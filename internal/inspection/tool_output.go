@@ -0,0 +1,91 @@
+package inspection
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+)
+
+// maxToolOutput is how much of the standard error of a tool is kept for errors and logs
+// The end of the output is kept, where tools report the cause of a failure
+const maxToolOutput = 64 * 1024
+
+// outputBuffer is a writer keeping the last bytes written to it, safe for concurrent use
+type outputBuffer struct {
+	mu        sync.Mutex
+	limit     int
+	data      []byte
+	truncated int64
+}
+
+// newOutputBuffer creates an output buffer keeping at most limit bytes
+func newOutputBuffer(limit int) *outputBuffer {
+	return &outputBuffer{limit: limit}
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.data = append(b.data, p...)
+	if excess := len(b.data) - b.limit; excess > 0 {
+		b.truncated += int64(excess)
+		b.data = append(b.data[:0], b.data[excess:]...)
+	}
+	return len(p), nil
+}
+
+// Len returns the number of bytes kept
+func (b *outputBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.data)
+}
+
+// String returns the kept output with credentials redacted
+func (b *outputBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	output := redact(string(b.data))
+	if b.truncated > 0 {
+		return fmt.Sprintf("[%d bytes truncated]\n%s", b.truncated, output)
+	}
+	return output
+}
+
+// ToolError is the failure of an inspection tool with the end of its standard error
+type ToolError struct {
+	Tool     string
+	ExitCode int    // -1 if the tool did not exit
+	Stderr   string // Size limited, with credentials redacted
+	Err      error
+}
+
+func (e *ToolError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("%s failed (exit code %d): %v", e.Tool, e.ExitCode, e.Err)
+	}
+	return fmt.Sprintf("%s failed (exit code %d): %v\nOutput: %s", e.Tool, e.ExitCode, e.Err, e.Stderr)
+}
+
+// Unwrap returns the error running the tool
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// newToolError builds the error of a tool run that failed
+func newToolError(tool string, err error, stderr *outputBuffer) *ToolError {
+	exitCode := -1
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		exitCode = exitError.ExitCode()
+	}
+	return &ToolError{
+		Tool:     tool,
+		ExitCode: exitCode,
+		Stderr:   stderr.String(),
+		Err:      err,
+	}
+}
@@ -0,0 +1,120 @@
+package inspection
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// toolVersion runs a tool with args printing its version and returns the version, the last word of the
+// first output line (e.g. "1.50.1" from "virt-inspector 1.50.1")
+func toolVersion(ctx context.Context, executor Executor, path string, args ...string) (string, error) {
+	cmd := executor.Command(ctx, path, args...)
+	cmd.Env = libguestfsEnv()
+	stderr := newOutputBuffer(maxToolOutput)
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", newToolError(path, err, stderr)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s printed no version", path)
+	}
+	return fields[len(fields)-1], nil
+}
+
+// Minimum tool versions of optional features
+const (
+	minNoApplicationsVersion = "1.38" // virt-inspector --no-applications and --no-icon
+	minKeySelectorVersion    = "1.40" // --key DEVICE:file:FILE selectors of libguestfs tools
+)
+
+// FeatureError reports a requested feature not supported by the installed version of a tool
+type FeatureError struct {
+	Feature    string
+	Tool       string
+	Version    string // Detected version
+	MinVersion string
+}
+
+func (e *FeatureError) Error() string {
+	return fmt.Sprintf("%s requires %s %s or newer, found %s", e.Feature, e.Tool, e.MinVersion, e.Version)
+}
+
+// detectedVersions caches the versions of the tools by executor identity and path, tools are not expected to
+// change while running
+var detectedVersions sync.Map
+
+// identifiedExecutor is implemented by the executors whose commands run on a host identified by a string,
+// used to cache the tool versions of the host
+type identifiedExecutor interface {
+	identity() string
+}
+
+// cachedToolVersion returns the version of the tool at path, detected on first use with "--version"
+// Failed detections are not cached, nor are versions of executors without an identity
+func cachedToolVersion(ctx context.Context, executor Executor, path string) (string, error) {
+	identified, ok := executor.(identifiedExecutor)
+	if !ok {
+		return toolVersion(ctx, executor, path, "--version")
+	}
+	key := identified.identity() + "\x00" + path
+	if cached, ok := detectedVersions.Load(key); ok {
+		return cached.(string), nil
+	}
+	version, err := toolVersion(ctx, executor, path, "--version")
+	if err != nil {
+		return "", err
+	}
+	detectedVersions.Store(key, version)
+	return version, nil
+}
+
+// requireVersion returns a FeatureError if the tool at path is older than minVersion
+// Tools whose version cannot be detected are assumed to support the feature, running them reports
+// the actual error
+func requireVersion(ctx context.Context, executor Executor, feature string, path string, minVersion string) error {
+	version, err := cachedToolVersion(ctx, executor, path)
+	if err != nil {
+		return nil
+	}
+	if compareVersions(version, minVersion) < 0 {
+		return &FeatureError{Feature: feature, Tool: filepath.Base(path), Version: version, MinVersion: minVersion}
+	}
+	return nil
+}
+
+// compareVersions compares dotted numeric versions, returning -1, 0 or 1
+// Non-numeric suffixes of components are ignored (e.g. "1.50.1rc1" is "1.50.1")
+func compareVersions(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for idx := 0; idx < max(len(partsA), len(partsB)); idx++ {
+		var numA, numB int
+		if idx < len(partsA) {
+			numA = leadingNumber(partsA[idx])
+		}
+		if idx < len(partsB) {
+			numB = leadingNumber(partsB[idx])
+		}
+		if numA != numB {
+			return cmp.Compare(numA, numB)
+		}
+	}
+	return 0
+}
+
+// leadingNumber returns the number at the start of s, 0 if none
+func leadingNumber(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	number, _ := strconv.Atoi(s[:end])
+	return number
+}
@@ -0,0 +1,54 @@
+package inspection
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCachedToolVersion(t *testing.T) {
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "tool")
+	writeTool := func(script string) {
+		t.Helper()
+		if err := os.WriteFile(tool, []byte("#!/bin/sh\n"+script+"\n"), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	writeTool("exit 1")
+	if _, err := cachedToolVersion(ctx, LocalExecutor{}, tool); err == nil {
+		t.Fatal("cachedToolVersion() error = nil, want the failure of the tool")
+	}
+
+	writeTool("echo tool 1.50.1")
+	version, err := cachedToolVersion(ctx, LocalExecutor{}, tool)
+	if err != nil || version != "1.50.1" {
+		t.Fatalf("cachedToolVersion() = %q, %v, want 1.50.1 once the tool works", version, err)
+	}
+
+	writeTool("echo tool 1.52.0")
+	if version, _ := cachedToolVersion(ctx, LocalExecutor{}, tool); version != "1.50.1" {
+		t.Errorf("cachedToolVersion() = %q, want the cached 1.50.1", version)
+	}
+}
+
+func TestExecutorIdentity(t *testing.T) {
+	identities := map[string]identifiedExecutor{
+		"local":                        LocalExecutor{},
+		"container podman quay.io/a":   ContainerExecutor{Image: "quay.io/a"},
+		"container docker quay.io/a":   ContainerExecutor{Image: "quay.io/a", Runtime: "docker"},
+		"ssh root@conversion:22":       SSHExecutor{Host: "conversion", User: "root"},
+		"ssh @conversion:2222":         SSHExecutor{Host: "conversion", Port: 2222},
+		"container podman quay.io/b":   ContainerExecutor{Image: "quay.io/b", RunArgs: []string{"--device=/dev/kvm"}},
+		"ssh root@conversion-2:22":     SSHExecutor{Host: "conversion-2", User: "root", SSHArgs: []string{"-v"}},
+		"container podman quay.io/a:1": ContainerExecutor{Image: "quay.io/a:1", Runtime: "podman"},
+	}
+	for want, executor := range identities {
+		if got := executor.identity(); got != want {
+			t.Errorf("identity() of %#v = %q, want %q", executor, got, want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package inspection
+
+import (
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultVDDKLibDirs are the locations searched for VDDK when no library directory is configured
+var defaultVDDKLibDirs = []string{
+	"/opt/vmware-vix-disklib",
+	"/usr/lib64/vmware-vix-disklib",
+	"/usr/local/vmware-vix-disklib",
+}
+
+// VDDKOptions configures how nbdkit-vddk and virt-v2v-inspector use VDDK
+// VDDK verifies the vCenter certificate against Thumbprint. Without a thumbprint VDDK connects without
+// verification, unless ComputeThumbprint or RequireThumbprint is set
+type VDDKOptions struct {
+	// LibDir is the VDDK library directory, searched in the default locations if empty (see ResolveVDDKLibrary)
+	LibDir string
+
+	// Thumbprint is the SHA-1 thumbprint of the vCenter certificate, 20 colon separated hex bytes
+	// ("AB:CD:...:EF"), verified by VDDK when connecting
+	Thumbprint string
+
+	// ComputeThumbprint computes the thumbprint from the certificate vCenter presents when Thumbprint is empty
+	// The certificate must be trusted by the system roots or CACertFile, so the computed thumbprint is not
+	// trusted on first use. Inspections fail if it cannot be computed
+	ComputeThumbprint bool
+
+	// CACertFile is a PEM bundle of the CAs trusted for the vCenter certificate when computing the thumbprint
+	// (e.g., the vCenter VMCA root), the system roots if empty
+	CACertFile string
+
+	// RequireThumbprint fails inspections without a thumbprint, instead of letting VDDK connect without
+	// verifying the vCenter certificate
+	RequireThumbprint bool
+}
+
+// thumbprint returns the vCenter thumbprint to pass to VDDK, empty to connect without one
+func (o VDDKOptions) thumbprint(vcenterHost string, logger *logrus.Logger) (string, error) {
+	if o.Thumbprint != "" {
+		if !isThumbprint(o.Thumbprint) {
+			return "", fmt.Errorf("invalid vCenter thumbprint %q, expected the SHA-1 thumbprint as 20 colon separated hex bytes", o.Thumbprint)
+		}
+		return o.Thumbprint, nil
+	}
+
+	if !o.ComputeThumbprint {
+		if o.RequireThumbprint {
+			return "", fmt.Errorf("vCenter thumbprint is required, set Thumbprint or ComputeThumbprint")
+		}
+		if logger != nil {
+			logger.Warn("No vCenter thumbprint configured, VDDK connects without SSL verification")
+		}
+		return "", nil
+	}
+
+	thumbprint, err := getVCenterThumbprint(vcenterHost, o.CACertFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute vCenter thumbprint: %w", err)
+	}
+	if logger != nil {
+		logger.WithField("thumbprint", thumbprint).Debug("Computed vCenter thumbprint")
+	}
+	return thumbprint, nil
+}
+
+// tlsConfig returns the TLS configuration verifying the vCenter certificate like VDDK sessions: against
+// Thumbprint, against the system roots or CACertFile with ComputeThumbprint, or not at all without a thumbprint
+func (o VDDKOptions) tlsConfig(vcenterHost string) (*tls.Config, error) {
+	if o.Thumbprint != "" {
+		if !isThumbprint(o.Thumbprint) {
+			return nil, fmt.Errorf("invalid vCenter thumbprint %q, expected the SHA-1 thumbprint as 20 colon separated hex bytes", o.Thumbprint)
+		}
+		return &tls.Config{
+			InsecureSkipVerify: true, // The certificate is verified against the thumbprint instead
+			VerifyConnection: func(state tls.ConnectionState) error {
+				if len(state.PeerCertificates) == 0 {
+					return fmt.Errorf("no certificates found")
+				}
+				digest := sha1.Sum(state.PeerCertificates[0].Raw)
+				if thumbprint := formatThumbprint(digest[:]); !strings.EqualFold(thumbprint, o.Thumbprint) {
+					return fmt.Errorf("vCenter certificate thumbprint %s does not match the configured thumbprint", thumbprint)
+				}
+				return nil
+			},
+		}, nil
+	}
+	if o.ComputeThumbprint {
+		return caTLSConfig(vcenterHost, o.CACertFile)
+	}
+	if o.RequireThumbprint {
+		return nil, fmt.Errorf("vCenter thumbprint is required, set Thumbprint or ComputeThumbprint")
+	}
+	return &tls.Config{InsecureSkipVerify: true}, nil // VDDK connects without verification too
+}
+
+// isThumbprint returns true for a SHA-1 thumbprint of colon separated hex bytes, the format of VDDK
+func isThumbprint(thumbprint string) bool {
+	parts := strings.Split(thumbprint, ":")
+	if len(parts) != sha1.Size {
+		return false
+	}
+	for _, part := range parts {
+		if len(part) != 2 {
+			return false
+		}
+		if _, err := hex.DecodeString(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// formatThumbprint formats a certificate digest as colon separated upper case hex bytes, as shown by vSphere
+func formatThumbprint(digest []byte) string {
+	parts := make([]string, len(digest))
+	for idx, b := range digest {
+		parts[idx] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// VDDKLibrary is a VDDK installation passed to nbdkit and virt-v2v as libdir
+type VDDKLibrary struct {
+	Dir     string `json:"dir"`
+	Version string `json:"version,omitempty"` // e.g. "8.0.3", or only the major version if no full version is found
+}
+
+// ResolveVDDKLibrary validates a VDDK library directory, or searches the default locations if libDir is empty
+// A valid directory contains lib64/libvixDiskLib.so.<version>, the library loaded by nbdkit-vddk
+func ResolveVDDKLibrary(libDir string) (*VDDKLibrary, error) {
+	if libDir != "" {
+		return readVDDKLibrary(libDir)
+	}
+
+	for _, dir := range defaultVDDKLibDirs {
+		if library, err := readVDDKLibrary(dir); err == nil {
+			return library, nil
+		}
+	}
+	return nil, fmt.Errorf("VDDK library not found in %s, configure the VDDK library directory", strings.Join(defaultVDDKLibDirs, ", "))
+}
+
+// readVDDKLibrary validates a VDDK library directory and reads the library version from its file names
+func readVDDKLibrary(libDir string) (*VDDKLibrary, error) {
+	matches, err := filepath.Glob(filepath.Join(libDir, "lib64", "libvixDiskLib.so.*"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VDDK library directory %s: %w", libDir, err)
+	}
+	if len(matches) == 0 {
+		if _, err := os.Stat(libDir); err != nil {
+			return nil, fmt.Errorf("VDDK library directory %s: %w", libDir, err)
+		}
+		return nil, fmt.Errorf("VDDK library directory %s has no lib64/libvixDiskLib.so", libDir)
+	}
+
+	// VDDK ships libvixDiskLib.so.<major>.<minor>.<patch> with a libvixDiskLib.so.<major> symlink
+	library := &VDDKLibrary{Dir: libDir}
+	for _, match := range matches {
+		version := strings.TrimPrefix(filepath.Base(match), "libvixDiskLib.so.")
+		if !isNumericVersion(version) {
+			continue
+		}
+		if library.Version == "" || strings.Count(version, ".") > strings.Count(library.Version, ".") {
+			library.Version = version
+		}
+	}
+	return library, nil
+}
+
+// isNumericVersion returns true for dot separated numbers (e.g., "8.0.3")
+func isNumericVersion(version string) bool {
+	for _, part := range strings.Split(version, ".") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package inspection
+
+import (
+	"crypto/sha1"
+	"strings"
+	"testing"
+)
+
+func TestIsThumbprint(t *testing.T) {
+	sha1Thumbprint := "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01"
+	tests := map[string]bool{
+		sha1Thumbprint:                                    true,
+		strings.ToLower(sha1Thumbprint):                   true,
+		strings.Repeat("AB:", 31) + "AB":                  false, // SHA-256, not supported by VDDK
+		"AB:CD":                                           false,
+		strings.ReplaceAll(sha1Thumbprint, ":", ""):       false,
+		strings.Replace(sha1Thumbprint, "AB", "ZZ", 1):    false,
+		strings.Replace(sha1Thumbprint, "AB:", "ABC:", 1): false,
+		"": false,
+	}
+	for thumbprint, want := range tests {
+		if got := isThumbprint(thumbprint); got != want {
+			t.Errorf("isThumbprint(%q) = %v, want %v", thumbprint, got, want)
+		}
+	}
+}
+
+func TestFormatThumbprint(t *testing.T) {
+	digest := sha1.Sum([]byte("certificate"))
+	thumbprint := formatThumbprint(digest[:])
+	if !isThumbprint(thumbprint) {
+		t.Fatalf("formatThumbprint() = %q, not a VDDK thumbprint", thumbprint)
+	}
+	if thumbprint != strings.ToUpper(thumbprint) {
+		t.Errorf("formatThumbprint() = %q, want upper case", thumbprint)
+	}
+	if got := formatThumbprint([]byte{0x0a, 0xff}); got != "0A:FF" {
+		t.Errorf("formatThumbprint() = %q, want 0A:FF", got)
+	}
+}
+
+func TestVDDKOptionsThumbprint(t *testing.T) {
+	valid := "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01"
+	tests := []struct {
+		name    string
+		options VDDKOptions
+		want    string
+		wantErr string
+	}{
+		{name: "configured", options: VDDKOptions{Thumbprint: valid, RequireThumbprint: true}, want: valid},
+		{name: "invalid", options: VDDKOptions{Thumbprint: "AB:CD"}, wantErr: "invalid vCenter thumbprint"},
+		{name: "not configured", options: VDDKOptions{}, want: ""},
+		{name: "required", options: VDDKOptions{RequireThumbprint: true}, wantErr: "thumbprint is required"},
+		// Port 443 of the unspecified address is not listening, computing fails instead of skipping verification
+		{name: "compute fails closed", options: VDDKOptions{ComputeThumbprint: true}, wantErr: "failed to compute vCenter thumbprint"},
+		{name: "invalid CA file", options: VDDKOptions{ComputeThumbprint: true, CACertFile: "/nonexistent/ca.pem"}, wantErr: "failed to read CA certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.options.thumbprint("0.0.0.0", nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("thumbprint() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("thumbprint() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
-	"os/exec"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/nirarg/v2v-vm-validations/pkg/types"
@@ -18,25 +19,39 @@ const UseVirtV2VOpen = false
 // Inspector handles VM inspection operations
 type VirtInspector struct {
 	virtInspectorPath string
+	options           VirtInspectorOptions
 	timeout           time.Duration
 	logger            *logrus.Logger
+	secrets           *redactor // Redacts the decryption keys
+	sessions          *SessionManager
 }
 
 // NewInspector creates a new Inspector instance
-func NewVirtInspector(virtInspectorPath string, timeout time.Duration, logger *logrus.Logger) *VirtInspector {
+// options: virt-inspector command line options
+// sessions: manager of the NBD sessions shared with other inspections (a session is opened
+// for each inspection if nil)
+func NewVirtInspector(virtInspectorPath string, options VirtInspectorOptions, timeout time.Duration, logger *logrus.Logger, sessions *SessionManager) *VirtInspector {
 	if virtInspectorPath == "" {
 		virtInspectorPath = "virt-inspector" // Use system PATH
 	}
 	if timeout == 0 {
 		timeout = 5 * time.Minute
 	}
+	if sessions == nil {
+		sessions = NewSessionManager(0, nil, logger)
+	}
+	secrets := newRedactor(keySecrets(options.Keys)...)
 	return &VirtInspector{
 		virtInspectorPath: virtInspectorPath,
+		options:           options,
 		timeout:           timeout,
-		logger:            logger,
+		logger:            secrets.logger(logger),
+		secrets:           secrets,
+		sessions:          sessions,
 	}
 }
 
+// Inspect uses virt-inspector to inspect a VM snapshot through an NBD session of its disks
 func (i *VirtInspector) Inspect(
 	ctx context.Context,
 	vmName string,
@@ -47,115 +62,42 @@ func (i *VirtInspector) Inspect(
 	password string,
 	diskInfo *types.SnapshotDiskInfo, // Snapshot disk info from vm_service
 ) (*types.VirtInspectorXML, error) {
+	startedAt := time.Now()
+	result, err := i.inspect(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	i.options.Metrics.observeInspection("virt-inspector", time.Since(startedAt), err)
+	return result, err
+}
 
-	var nbdURL string
-	var sessionCloser func()
-
-	if UseVirtV2VOpen {
-		i.logger.WithFields(logrus.Fields{
-			"vm_name":       vmName,
-			"snapshot_name": snapshotName,
-			"vcenter_url":   vcenterURL,
-			"datacenter":    datacenter,
-		}).Info("Running virt-inspector using virt-v2v-open (VDDK + snapshot)")
-
-		openCtx, cancel := context.WithTimeout(ctx, i.timeout)
-		defer cancel()
-
-		v2vSession, err := OpenWithVirtV2V(
-			openCtx,
-			vmName,
-			datacenter,
-			snapshotName,
-			vcenterURL,
-			username,
-			password,
-		)
-		if err != nil {
-			return nil, err
-		}
-		nbdURL = v2vSession.NBDURL
-		sessionCloser = v2vSession.Close
-
-		// Give NBD time to initialize
-		time.Sleep(4 * time.Second)
-	} else {
-		i.logger.WithFields(logrus.Fields{
-			"vm_name":       vmName,
-			"snapshot_name": snapshotName,
-			"vcenter_url":   vcenterURL,
-			"datacenter":    datacenter,
-		}).Info("Running virt-inspector using nbdkit-vddk (VDDK + snapshot)")
+// inspect runs virt-inspector on the NBD session of the VM snapshot
+func (i *VirtInspector) inspect(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) (*types.VirtInspectorXML, error) {
 
-		// Use diskInfo passed from vm_service (no need to query vSphere here)
-		i.logger.WithFields(logrus.Fields{
-			"vm_moref":       diskInfo.VMMoref,
-			"snapshot_moref": diskInfo.SnapshotMoref,
-			"disk_path":      diskInfo.DiskPath,
-			"base_disk_path": diskInfo.BaseDiskPath,
-		}).Debug("Using snapshot disk info from vm_service")
-
-		openCtx, cancel := context.WithTimeout(ctx, i.timeout)
-		defer cancel()
-
-		nbdkitSession, err := OpenWithNBDKitVDDK(
-			openCtx,
-			diskInfo.VMMoref,
-			diskInfo.SnapshotMoref,
-			diskInfo.BaseDiskPath,
-			vcenterURL,
-			username,
-			password,
-			i.logger,
-		)
-		if err != nil {
-			return nil, err
-		}
-		nbdURL = nbdkitSession.NBDURL
-		sessionCloser = nbdkitSession.Close
-
-		// Wait for NBD server to be ready (more reliable than sleep)
-		if err := nbdkitSession.WaitForReady(30 * time.Second); err != nil {
-			i.logger.WithError(err).Error("NBD server not ready")
-			nbdkitSession.Close()
-			return nil, fmt.Errorf("NBD server not ready: %w", err)
-		}
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	nbdURLs, sessionCloser, err := i.openSession(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+	if err != nil {
+		return nil, failedStep(reasonSession, err)
 	}
 	defer sessionCloser()
 
-	inspectCtx, cancel := context.WithTimeout(ctx, i.timeout)
-	defer cancel()
-
-	i.logger.WithField("nbd_url", nbdURL).Info("Running virt-inspector on NBD")
-
-	cmdString := fmt.Sprintf("unset LD_LIBRARY_PATH && %s --format=raw -a '%s'",
-		i.virtInspectorPath, nbdURL)
-
-	virtInspectorCmd := exec.CommandContext(inspectCtx, "sh", "-c", cmdString)
-
-	output, err := virtInspectorCmd.CombinedOutput()
-	outputStr := string(output)
+	var output []byte
+	err = i.sessions.options.Retry.do(ctx, i.logger, "virt-inspector", func() (err error) {
+		output, err = i.runVirtInspector(ctx, nbdURLs)
+		return err
+	})
 	if err != nil {
-		// Get exit code if available
-		exitCode := -1
-		if exitError, ok := err.(*exec.ExitError); ok {
-			exitCode = exitError.ExitCode()
-		}
-		i.logger.WithFields(logrus.Fields{
-			"output":    outputStr,
-			"exit_code": exitCode,
-			"nbd_url":   nbdURL,
-			"command":   cmdString,
-		}).Error("virt-inspector failed")
-
-		// Include output in error message for better debugging
-		if outputStr != "" {
-			return nil, fmt.Errorf("virt-inspector failed (exit code %d): %w\nOutput: %s", exitCode, err, outputStr)
-		}
-		return nil, fmt.Errorf("virt-inspector failed (exit code %d): %w", exitCode, err)
+		return nil, err
 	}
+	outputStr := string(output)
 
-	inspectionData, err := parseInspectionXML(output)
+	inspectionData, err := ParseVirtInspectorXML(output)
 	if err != nil {
 		if i.logger != nil {
 			i.logger.WithFields(logrus.Fields{
@@ -163,8 +105,9 @@ func (i *VirtInspector) Inspect(
 				"output": outputStr,
 			}).Error("Failed to parse virt-inspector XML output")
 		}
-		return nil, fmt.Errorf("failed to parse inspection output: %w", err)
+		return nil, failedStep(reasonParse, fmt.Errorf("failed to parse inspection output: %w", err))
 	}
+	emit(ctx, InspectionEvent{Phase: PhaseXMLParsed, Tool: "virt-inspector"})
 
 	if UseVirtV2VOpen {
 		i.logger.Info("virt-v2v-open snapshot inspection completed successfully")
@@ -174,8 +117,107 @@ func (i *VirtInspector) Inspect(
 	return inspectionData, nil
 }
 
-// parseInspectionXML parses virt-inspector XML output and returns the native XML structure
-func parseInspectionXML(xmlData []byte) (*types.VirtInspectorXML, error) {
+// runVirtInspector runs virt-inspector on an NBD URL and returns its output
+func (i *VirtInspector) runVirtInspector(ctx context.Context, nbdURLs []string) ([]byte, error) {
+	inspectCtx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+
+	i.logger.WithField("nbd_urls", nbdURLs).Info("Running virt-inspector on NBD")
+
+	executor := i.sessions.options.executor()
+	if err := i.options.checkFeatures(ctx, executor, i.virtInspectorPath); err != nil {
+		return nil, err
+	}
+	keys, removeKeys, err := keyArgs(ctx, executor, i.options.Keys)
+	if err != nil {
+		return nil, err
+	}
+	defer removeKeys()
+
+	args := append([]string{"--format=raw"}, diskArgs(nbdURLs)...)
+	args = append(args, keys...)
+	args = append(args, i.options.args()...)
+	virtInspectorCmd := executor.Command(inspectCtx, i.virtInspectorPath, args...)
+	virtInspectorCmd.Env = libguestfsEnv()
+	emit(ctx, InspectionEvent{Phase: PhaseToolStarted, Tool: "virt-inspector"})
+
+	stderr := newOutputBuffer(maxToolOutput)
+	virtInspectorCmd.Stderr = stderr
+
+	output, err := virtInspectorCmd.Output()
+	if err != nil {
+		toolErr := newToolError("virt-inspector", err, stderr)
+		i.logger.WithFields(logrus.Fields{
+			"stderr":    toolErr.Stderr,
+			"exit_code": toolErr.ExitCode,
+			"nbd_urls":  nbdURLs,
+			"command":   i.virtInspectorPath,
+			"args":      args,
+		}).Error("virt-inspector failed")
+		return nil, i.secrets.redactError(toolErr)
+	}
+	if stderr.Len() > 0 {
+		i.logger.WithField("stderr", stderr.String()).Debug("virt-inspector output")
+	}
+	return output, nil
+}
+
+// libguestfsEnv returns the environment of libguestfs tools
+// LD_LIBRARY_PATH is removed, so the appliance does not load libraries meant for nbdkit, like the OpenSSL of VDDK
+func libguestfsEnv() []string {
+	env := os.Environ()
+	filtered := make([]string, 0, len(env))
+	for _, e := range env {
+		if !strings.HasPrefix(e, "LD_LIBRARY_PATH=") {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// diskArgs returns the libguestfs arguments adding the NBD URLs as disks
+func diskArgs(nbdURLs []string) []string {
+	args := make([]string, 0, 2*len(nbdURLs))
+	for _, nbdURL := range nbdURLs {
+		args = append(args, "-a", nbdURL)
+	}
+	return args
+}
+
+// openSession acquires the shared NBD session of the VM snapshot from the session manager
+// Returns the NBD URLs of the disks and a function that releases the session
+func (i *VirtInspector) openSession(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]string, func(), error) {
+	return i.sessions.Acquire(ctx, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+}
+
+// ToolVersions returns the detected versions of virt-inspector and guestfish, omitting undetected tools
+func (i *VirtInspector) ToolVersions(ctx context.Context) map[string]string {
+	versions := make(map[string]string)
+	for name, path := range map[string]string{"virt-inspector": i.virtInspectorPath, "guestfish": guestfishPath} {
+		if version, err := cachedToolVersion(ctx, i.sessions.options.executor(), path); err == nil {
+			versions[name] = version
+		}
+	}
+	return versions
+}
+
+// CloseSessions closes the NBD sessions of the session manager that are not in use
+func (i *VirtInspector) CloseSessions() {
+	i.sessions.Close()
+}
+
+// ParseVirtInspectorXML parses virt-inspector XML output and returns the native XML structure
+// The output is kept in RawXML
+func ParseVirtInspectorXML(xmlData []byte) (*types.VirtInspectorXML, error) {
 	var xmlRoot types.VirtInspectorXML
 	err := xml.Unmarshal(xmlData, &xmlRoot)
 	if err != nil {
@@ -186,5 +228,6 @@ func parseInspectionXML(xmlData []byte) (*types.VirtInspectorXML, error) {
 		return nil, fmt.Errorf("no operating systems found in inspection output")
 	}
 
+	xmlRoot.RawXML = string(xmlData)
 	return &xmlRoot, nil
 }
@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -17,26 +18,54 @@ import (
 // VirtV2vInspector handles VM inspection operations using virt-v2v-inspector
 type VirtV2vInspector struct {
 	virtV2vInspectorPath string
+	vddk                 VDDKOptions
+	options              VirtV2vInspectorOptions
 	timeout              time.Duration
 	logger               *logrus.Logger
+	secrets              *redactor // Redacts the decryption keys
+	sessions             *SessionManager
 }
 
 // NewVirtV2vInspector creates a new VirtV2vInspector instance
-func NewVirtV2vInspector(virtV2vInspectorPath string, timeout time.Duration, logger *logrus.Logger) *VirtV2vInspector {
+// vddk configures the VDDK library and the vCenter thumbprint verification
+// options configures the virt-v2v-inspector command line
+// sessions: manager of the NBD sessions shared with other inspections, read by virt-v2v-inspector as a
+// libvirt domain (-i libvirtxml); virt-v2v-inspector opens its own VDDK connection to vCenter if nil
+func NewVirtV2vInspector(virtV2vInspectorPath string, vddk VDDKOptions, options VirtV2vInspectorOptions, timeout time.Duration, logger *logrus.Logger, sessions *SessionManager) *VirtV2vInspector {
 	if virtV2vInspectorPath == "" {
 		virtV2vInspectorPath = "virt-v2v-inspector" // Use system PATH
 	}
 	if timeout == 0 {
 		timeout = 5 * time.Minute
 	}
+	secrets := newRedactor(keySecrets(options.Keys)...)
 	return &VirtV2vInspector{
 		virtV2vInspectorPath: virtV2vInspectorPath,
+		vddk:                 vddk,
+		options:              options,
 		timeout:              timeout,
-		logger:               logger,
+		logger:               secrets.logger(logger),
+		secrets:              secrets,
+		sessions:             sessions,
 	}
 }
 
-// Inspect uses virt-v2v-inspector to inspect a VM snapshot directly via VDDK
+// executor returns the executor of virt-v2v-inspector, the executor of the sessions serving the NBD
+// sockets if set
+func (i *VirtV2vInspector) executor() Executor {
+	if i.sessions != nil {
+		return i.sessions.options.executor()
+	}
+	return i.options.executor()
+}
+
+// Version returns the detected version of virt-v2v-inspector
+func (i *VirtV2vInspector) Version(ctx context.Context) (string, error) {
+	return cachedToolVersion(ctx, i.executor(), i.virtV2vInspectorPath)
+}
+
+// Inspect uses virt-v2v-inspector to inspect a VM snapshot, through the shared NBD session of the
+// snapshot if the inspector has a session manager, directly via VDDK otherwise
 func (i *VirtV2vInspector) Inspect(
 	ctx context.Context,
 	vmName string,
@@ -46,92 +75,87 @@ func (i *VirtV2vInspector) Inspect(
 	username string,
 	password string,
 	diskInfo *types.SnapshotDiskInfo, // Snapshot disk info from vm_service
-	sslVerify string, // SSL verification option for vpx:// URL (e.g., "no_verify=1" or "cacert=/path/to/ca-bundle.crt")
+	sslVerify string, // SSL verification option for vpx:// URL (e.g., "no_verify=1" or "cacert=/path/to/ca-bundle.crt"), unused with a session manager
 ) (*types.VirtV2VInspectorXML, error) {
-	i.logger.WithFields(logrus.Fields{
+	// The password is redacted from the logs and errors of the inspection, including tool output
+	secrets := i.secrets.with(password)
+	ctx = withEvents(ctx, i.options.OnEvent, vmName)
+	startedAt := time.Now()
+	result, err := i.inspect(ctx, secrets, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo, sslVerify)
+	i.options.Metrics.observeInspection("virt-v2v-inspector", time.Since(startedAt), err)
+	return result, secrets.redactError(err)
+}
+
+// inspect runs virt-v2v-inspector on the VM snapshot
+func (i *VirtV2vInspector) inspect(
+	ctx context.Context,
+	secrets *redactor,
+	vmName string,
+	snapshotName string,
+	vcenterURL string,
+	datacenter string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+	sslVerify string,
+) (*types.VirtV2VInspectorXML, error) {
+	logger := secrets.logger(i.logger)
+	logger.WithFields(logrus.Fields{
 		"vm_name":       vmName,
 		"snapshot_name": snapshotName,
 		"vcenter_url":   vcenterURL,
 		"datacenter":    datacenter,
 	}).Info("Running virt-v2v-inspector on snapshot")
 
-	// Build libvirt connection URL for vSphere
-	// Format: vpx://username@vcenter/compute-resource-path?ssl-verify
-	// The path must point to a compute resource (host/cluster), not the datacenter or VM
-	// The VM name is specified as a positional argument after "--"
-	// Extract hostname from vCenter URL
-	vcenterHost := extractHostname(vcenterURL)
-
-	// URL-encode username to handle special characters like @
-	// The @ symbol in the username needs to be percent-encoded as %40
-	// because @ is used as a delimiter between username and hostname in URLs
-	// We use url.QueryEscape which encodes @ as %40
-	encodedUsername := url.QueryEscape(username)
-
-	// Use the compute resource path from diskInfo (e.g., "/Datacenter/Cluster/host.example.com")
-	// This is required for vpx:// URLs - they need a compute resource, not just a datacenter
-	computeResourcePath := diskInfo.ComputeResourcePath
-	if computeResourcePath == "" {
-		return nil, fmt.Errorf("compute resource path is required for vpx:// URL")
-	}
-
-	// Build vpx:// URL (without snapshot parameter)
-	// Inspect the base/parent disk file directly
-	// The snapshot parameter is not needed when using the parent file
-	// Add SSL verification parameter (provided by caller)
-	libvirtURL := fmt.Sprintf("vpx://%s@%s%s?%s",
-		encodedUsername, vcenterHost, computeResourcePath, sslVerify)
+	executor := i.executor()
 
-	// Create context with timeout
-	inspectCtx, cancel := context.WithTimeout(ctx, i.timeout)
-	defer cancel()
-
-	// virt-v2v-inspector expects -ip to be a file path, not the password directly
-	// Create a temporary file with the password
-	passwordFile, err := i.createPasswordFile(password)
+	// The XML is written to a file with -O, so the debug messages and progress output of the tool on stdout
+	// do not have to be told apart from it
+	outputDir, err := executor.TempDir(ctx, "v2v-inspector")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create password file: %w", err)
+		return nil, fmt.Errorf("failed to create temporary output directory: %w", err)
+	}
+	defer trackTempPath(executor, outputDir)() // Also removed on Cleanup
+	outputFile := filepath.Join(outputDir, "inspection.xml")
+
+	// The input options and the source, the VM name or the domain XML file, of virt-v2v-inspector
+	var input []string
+	var source string
+	if i.sessions != nil {
+		nbdURLs, release, err := i.sessions.acquire(ctx, logger, vmName, snapshotName, vcenterURL, datacenter, username, password, diskInfo)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		input, source, err = libvirtXMLInput(ctx, executor, outputDir, vmName, nbdURLs)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var removeInput func()
+		input, removeInput, err = i.vddkInput(ctx, logger, vcenterURL, username, password, diskInfo, sslVerify)
+		if err != nil {
+			return nil, err
+		}
+		defer removeInput()
+		source = vmName
 	}
-	defer os.Remove(passwordFile) // Clean up the temporary file
-
-	var output []byte
 
 	// Build virt-v2v-inspector command
-	args := []string{
-		"-v",            // Verbose
-		"-x",            // Debug
-		"-i", "libvirt", // Input type: libvirt
-		"-ic", libvirtURL, // libvirt connection URI (vpx://...)
-		"-ip", passwordFile, // libvirt password file path (not password directly)
-		"-it", "vddk", // Input transport: VDDK
-	}
+	args := append(input, "-O", outputFile) // Output XML file
 
-	// Add VDDK options
-	// Get vCenter thumbprint
-	thumbprint, err := getVCenterThumbprint(vcenterHost)
+	keys, removeKeys, err := keyArgs(ctx, executor, i.options.Keys)
 	if err != nil {
-		i.logger.WithError(err).Warn("Failed to get thumbprint, proceeding without SSL verification")
-	} else if thumbprint != "" {
-		args = append(args, "-io", fmt.Sprintf("vddk-thumbprint=%s", thumbprint))
-	}
-
-	// Add VDDK library directory
-	vddkLibDir := findVDDKLibDir()
-	if vddkLibDir != "" {
-		args = append(args, "-io", fmt.Sprintf("vddk-libdir=%s", vddkLibDir))
+		return nil, err
 	}
+	defer removeKeys()
+	args = append(args, keys...)
 
-	// Add disk file specification
-	// virt-v2v-inspector needs the disk file path in VDDK format
-	// Format: vddk-file=[datastore] path/to/disk.vmdk
-	if diskInfo.BaseDiskPath != "" {
-		args = append(args, "-io", fmt.Sprintf("vddk-file=%s", diskInfo.BaseDiskPath))
-	}
-
-	args = append(args, "--", vmName)
+	args = append(args, i.options.ExtraArgs...)
+	args = append(args, "--", source)
 
 	// Log the command (without password file path)
-	if i.logger != nil {
+	if logger != nil {
 		logArgs := make([]string, len(args))
 		copy(logArgs, args)
 		// Mask password file path in log
@@ -140,175 +164,282 @@ func (i *VirtV2vInspector) Inspect(
 				logArgs[idx+1] = "***"
 			}
 		}
-		i.logger.WithFields(logrus.Fields{
+		logger.WithFields(logrus.Fields{
 			"command": "virt-v2v-inspector",
 			"args":    logArgs,
 		}).Info("Running virt-v2v-inspector command")
 	}
 
-	// Execute virt-v2v-inspector
-	cmd := exec.CommandContext(inspectCtx, i.virtV2vInspectorPath, args...)
+	// Execute virt-v2v-inspector, the timeout does not include opening the session
+	inspectCtx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+	cmd := executor.Command(inspectCtx, i.virtV2vInspectorPath, args...)
 
 	// Filter out VDDK library paths from LD_LIBRARY_PATH to prevent supermin
 	// (called by libguestfs) from picking up VDDK's OpenSSL library
 	// virt-v2v-inspector will spawn nbdkit internally, and nbdkit's wrapper
 	// will set LD_LIBRARY_PATH only for nbdkit itself
-	env := os.Environ()
-	filteredEnv := make([]string, 0, len(env))
-	vddkLibPath := "/opt/vmware-vix-disklib/lib64"
-	for _, e := range env {
-		// Remove VDDK library path from LD_LIBRARY_PATH if present
-		if strings.HasPrefix(e, "LD_LIBRARY_PATH=") {
-			ldPath := strings.TrimPrefix(e, "LD_LIBRARY_PATH=")
-			// Filter out VDDK library path
-			paths := strings.Split(ldPath, ":")
-			filteredPaths := make([]string, 0, len(paths))
-			for _, p := range paths {
-				if p != vddkLibPath && !strings.Contains(p, "vmware-vix-disklib") {
-					filteredPaths = append(filteredPaths, p)
-				}
-			}
-			if len(filteredPaths) > 0 {
-				filteredEnv = append(filteredEnv, fmt.Sprintf("LD_LIBRARY_PATH=%s", strings.Join(filteredPaths, ":")))
-			}
-			// If LD_LIBRARY_PATH becomes empty, don't set it at all
-		} else {
-			filteredEnv = append(filteredEnv, e)
-		}
-	}
-	cmd.Env = filteredEnv
+	cmd.Env = withoutVDDKLibraryPath(os.Environ())
 
-	// Log environment filtering for debugging
-	if i.logger != nil {
-		hasVddkPath := false
-		for _, e := range filteredEnv {
-			if strings.HasPrefix(e, "LD_LIBRARY_PATH=") && strings.Contains(e, "vmware-vix-disklib") {
-				hasVddkPath = true
-				break
-			}
-		}
-		if hasVddkPath {
-			i.logger.Warn("LD_LIBRARY_PATH still contains VDDK paths after filtering")
-		} else {
-			i.logger.Debug("LD_LIBRARY_PATH filtered successfully (VDDK paths removed)")
-		}
-	}
+	// Run the command in a goroutine so we can monitor for context cancellation
+	resultChan := make(chan error, 1)
 
-	// Capture output with timeout handling
-	// Use a goroutine to capture output so we can monitor for context cancellation
-	type result struct {
-		output []byte
-		err    error
-	}
-	resultChan := make(chan result, 1)
+	// Progress messages, and debug messages if ExtraArgs has -v -x, go to stdout and stderr, they are kept
+	// interleaved in their order and only their end is kept for errors
+	stderr := newOutputBuffer(maxToolOutput)
+	cmd.Stdout = stderr
+	cmd.Stderr = stderr
 
+	emit(ctx, InspectionEvent{Phase: PhaseToolStarted, Tool: "virt-v2v-inspector"})
 	go func() {
-		output, err := cmd.CombinedOutput()
-		resultChan <- result{output: output, err: err}
+		resultChan <- cmd.Run()
 	}()
 
 	// Wait for either completion or context cancellation
 	select {
-	case res := <-resultChan:
-		output = res.output
-		err = res.err
+	case err = <-resultChan:
 	case <-inspectCtx.Done():
 		// Context was cancelled (timeout or parent cancellation)
 		// Kill the process if it's still running
 		if cmd.Process != nil {
-			if killErr := cmd.Process.Kill(); killErr != nil {
-				if i.logger != nil {
-					i.logger.WithError(killErr).Warn("Failed to kill virt-v2v-inspector process after timeout")
+			if killErr := killCommand(cmd); killErr != nil {
+				if logger != nil {
+					logger.WithError(killErr).Warn("Failed to kill virt-v2v-inspector process after timeout")
 				}
-			} else if i.logger != nil {
-				i.logger.Warn("Killed virt-v2v-inspector process due to timeout")
+			} else if logger != nil {
+				logger.Warn("Killed virt-v2v-inspector process due to timeout")
 			}
 		}
 		if inspectCtx.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("virt-v2v-inspector command timed out after %v", i.timeout)
+			return nil, failedStep(reasonTimeout, fmt.Errorf("virt-v2v-inspector command timed out after %v", i.timeout))
 		}
 		return nil, fmt.Errorf("virt-v2v-inspector command was cancelled: %w", inspectCtx.Err())
 	}
 
-	outputStr := string(output)
 	if err != nil {
-		// Get exit code if available
-		exitCode := -1
-		if exitError, ok := err.(*exec.ExitError); ok {
-			exitCode = exitError.ExitCode()
-		}
-		i.logger.WithFields(logrus.Fields{
-			"output":    outputStr,
-			"exit_code": exitCode,
+		toolErr := newToolError("virt-v2v-inspector", err, stderr)
+		logger.WithFields(logrus.Fields{
+			"stderr":    toolErr.Stderr,
+			"exit_code": toolErr.ExitCode,
 			"command":   i.virtV2vInspectorPath,
 			"args":      args,
 		}).Error("virt-v2v-inspector failed")
+		return nil, toolErr
+	}
+	xmlData, err := readOutputFile(ctx, executor, outputDir, filepath.Base(outputFile))
+	if err != nil {
+		logger.WithField("output", stderr.String()).Error("virt-v2v-inspector did not write its output file")
+		return nil, failedStep(reasonParse, fmt.Errorf("failed to read virt-v2v-inspector output: %w", err))
+	}
 
-		// Include output in error message for better debugging
-		if outputStr != "" {
-			return nil, fmt.Errorf("virt-v2v-inspector failed (exit code %d): %w\nOutput: %s", exitCode, err, outputStr)
+	inspectionData, err := ParseVirtV2VInspectorXML(xmlData)
+	if err != nil {
+		if logger != nil {
+			logger.WithFields(logrus.Fields{
+				"error": err,
+				"xml":   secrets.redact(string(xmlData)),
+			}).Error("Failed to parse virt-v2v-inspector XML output")
 		}
-		return nil, fmt.Errorf("virt-v2v-inspector failed (exit code %d): %w", exitCode, err)
-	}
-
-	// Extract XML from output (virt-v2v-inspector with -v -x may output debug messages)
-	// Look for XML content - it should start with <?xml or <v2v-inspection>
-	xmlStart := strings.Index(outputStr, "<?xml")
-	if xmlStart == -1 {
-		xmlStart = strings.Index(outputStr, "<v2v-inspection")
-	}
-	if xmlStart == -1 {
-		xmlStart = strings.Index(outputStr, "<operatingsystem")
-	}
-	if xmlStart == -1 {
-		xmlStart = strings.Index(outputStr, "<inspection")
-	}
-
-	var xmlData []byte
-	if xmlStart >= 0 {
-		// Extract XML portion from output
-		xmlData = []byte(outputStr[xmlStart:])
-		// Find the end of XML (look for closing </v2v-inspection> tag first, then fallback to </operatingsystem>)
-		xmlEnd := strings.LastIndex(string(xmlData), "</v2v-inspection>")
-		if xmlEnd > 0 {
-			xmlEnd += len("</v2v-inspection>")
-			xmlData = xmlData[:xmlEnd]
-		} else {
-			// Fallback: look for </operatingsystem> if </v2v-inspection> not found
-			xmlEnd = strings.LastIndex(string(xmlData), "</operatingsystem>")
-			if xmlEnd > 0 {
-				xmlEnd += len("</operatingsystem>")
-				xmlData = xmlData[:xmlEnd]
-			}
+		return nil, failedStep(reasonParse, fmt.Errorf("failed to parse virt-v2v-inspector output: %w", err))
+	}
+	emit(ctx, InspectionEvent{Phase: PhaseXMLParsed, Tool: "virt-v2v-inspector"})
+
+	logger.Info("virt-v2v-inspector snapshot inspection completed successfully")
+	return inspectionData, nil
+}
+
+// vddkInput returns the options of virt-v2v-inspector reading the disks of the VM through its libvirt VDDK
+// input, and a function removing the password file they use
+func (i *VirtV2vInspector) vddkInput(
+	ctx context.Context,
+	logger *logrus.Logger,
+	vcenterURL string,
+	username string,
+	password string,
+	diskInfo *types.SnapshotDiskInfo,
+	sslVerify string,
+) ([]string, func(), error) {
+	// Build libvirt connection URL for vSphere
+	// Format: vpx://username@vcenter/compute-resource-path?ssl-verify
+	// The path must point to a compute resource (host/cluster), not the datacenter or VM
+	// The VM name is specified as a positional argument after "--"
+	// Extract hostname from vCenter URL
+	vcenterHost := extractHostname(vcenterURL)
+
+	// Use the compute resource path from diskInfo (e.g., "/Datacenter/Cluster/host.example.com")
+	// This is required for vpx:// URLs - they need a compute resource, not just a datacenter
+	computeResourcePath := diskInfo.ComputeResourcePath
+	if computeResourcePath == "" {
+		return nil, nil, fmt.Errorf("compute resource path is required for vpx:// URL")
+	}
+
+	// Inspect the base/parent disk file directly, the snapshot parameter is not needed
+	libvirtURL, err := vpxComputeResourceURL(username, vcenterHost, computeResourcePath, sslVerify)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Add VDDK options
+	// Get vCenter thumbprint
+	thumbprint, err := i.vddk.thumbprint(vcenterHost, logger)
+	if err != nil {
+		return nil, nil, failedStep(reasonSession, err)
+	}
+
+	// Add VDDK library directory
+	vddkLibrary, err := i.executor().VDDKLibrary(i.vddk.LibDir)
+	if err != nil {
+		return nil, nil, failedStep(reasonSession, err)
+	}
+
+	// virt-v2v-inspector expects -ip to be a file path, not the password directly
+	// Create a temporary file with the password
+	passwordFile, removePasswordFile, err := createPasswordFile(ctx, i.executor(), password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create password file: %w", err)
+	}
+	emit(ctx, InspectionEvent{Phase: PhaseAuthFileCreated, Tool: "virt-v2v-inspector"})
+
+	args := []string{
+		"-i", "libvirt", // Input type: libvirt
+		"-ic", libvirtURL, // libvirt connection URI (vpx://...)
+		"-ip", passwordFile, // libvirt password file path (not password directly)
+		"-it", "vddk", // Input transport: VDDK
+	}
+	if thumbprint != "" {
+		args = append(args, "-io", fmt.Sprintf("vddk-thumbprint=%s", thumbprint))
+	}
+	args = append(args, "-io", fmt.Sprintf("vddk-libdir=%s", vddkLibrary.Dir))
+
+	// Add disk file specification
+	// virt-v2v-inspector needs the disk file path in VDDK format
+	// Format: vddk-file=[datastore] path/to/disk.vmdk
+	if diskInfo.BaseDiskPath != "" {
+		args = append(args, "-io", fmt.Sprintf("vddk-file=%s", diskInfo.BaseDiskPath))
+	}
+	return args, removePasswordFile, nil // The password file is also removed on Cleanup
+}
+
+// libvirtXMLInput writes the libvirt domain of the VM with the NBD disks of its session in dir, and returns
+// the options of virt-v2v-inspector reading it and the domain XML file
+func libvirtXMLInput(ctx context.Context, executor Executor, dir string, vmName string, nbdURLs []string) ([]string, string, error) {
+	domain, err := libvirtDomainXML(vmName, nbdURLs)
+	if err != nil {
+		return nil, "", err
+	}
+	domainFile := filepath.Join(dir, "domain.xml")
+	if err := executor.WriteFile(ctx, domainFile, domain); err != nil {
+		return nil, "", fmt.Errorf("failed to write libvirt domain XML: %w", err)
+	}
+	return []string{"-i", "libvirtxml"}, domainFile, nil
+}
+
+// libvirtDomain is the libvirt domain of a VM read by virt-v2v-inspector -i libvirtxml
+type libvirtDomain struct {
+	XMLName xml.Name      `xml:"domain"`
+	Type    string        `xml:"type,attr"`
+	Name    string        `xml:"name"`
+	Memory  libvirtMemory `xml:"memory"` // Required by virt-v2v, not part of the inspection
+	OSType  string        `xml:"os>type"`
+	Disks   []libvirtDisk `xml:"devices>disk"`
+}
+
+// libvirtMemory is the memory of a libvirt domain
+type libvirtMemory struct {
+	Unit  string `xml:"unit,attr"`
+	Value int64  `xml:",chardata"`
+}
+
+// libvirtDisk is a network disk of a libvirt domain
+type libvirtDisk struct {
+	Type   string            `xml:"type,attr"`
+	Device string            `xml:"device,attr"`
+	Driver libvirtDiskDriver `xml:"driver"`
+	Source libvirtDiskSource `xml:"source"`
+	Target libvirtDiskTarget `xml:"target"`
+}
+
+// libvirtDiskDriver is the format of a libvirt disk
+type libvirtDiskDriver struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// libvirtDiskSource is the NBD export of a libvirt disk
+type libvirtDiskSource struct {
+	Protocol string          `xml:"protocol,attr"`
+	Name     string          `xml:"name,attr,omitempty"` // Export name
+	Host     libvirtDiskHost `xml:"host"`
+}
+
+// libvirtDiskHost is the unix socket or TCP address of an NBD export
+type libvirtDiskHost struct {
+	Transport string `xml:"transport,attr,omitempty"`
+	Socket    string `xml:"socket,attr,omitempty"`
+	Name      string `xml:"name,attr,omitempty"`
+	Port      string `xml:"port,attr,omitempty"`
+}
+
+// libvirtDiskTarget is the guest device of a libvirt disk
+type libvirtDiskTarget struct {
+	Dev string `xml:"dev,attr"`
+}
+
+// libvirtDomainXML returns the libvirt domain XML of the VM with a raw disk for each NBD URL, in order
+func libvirtDomainXML(vmName string, nbdURLs []string) ([]byte, error) {
+	domain := libvirtDomain{
+		Type:   "kvm",
+		Name:   vmName,
+		Memory: libvirtMemory{Unit: "MiB", Value: 1024},
+		OSType: "hvm",
+	}
+	for idx, nbdURL := range nbdURLs {
+		network, address, export, err := parseNBDURL(nbdURL)
+		if err != nil {
+			return nil, err
 		}
-		if i.logger != nil {
-			xmlPreview := string(xmlData)
-			if len(xmlPreview) > 1000 {
-				xmlPreview = xmlPreview[:1000] + "... (truncated)"
+		host := libvirtDiskHost{Transport: "unix", Socket: address}
+		if network == "tcp" {
+			name, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid NBD address %q: %w", address, err)
 			}
-			i.logger.WithField("xml_extracted", xmlPreview).Debug("Extracted XML from output")
-		}
-	} else {
-		// No XML found, try parsing the whole output
-		xmlData = output
-		if i.logger != nil {
-			i.logger.Warn("No XML markers found in output, attempting to parse entire output")
+			host = libvirtDiskHost{Name: name, Port: port}
 		}
+		domain.Disks = append(domain.Disks, libvirtDisk{
+			Type:   "network",
+			Device: "disk",
+			Driver: libvirtDiskDriver{Name: "qemu", Type: "raw"},
+			Source: libvirtDiskSource{Protocol: "nbd", Name: export, Host: host},
+			Target: libvirtDiskTarget{Dev: diskDeviceName(idx)},
+		})
 	}
+	return xml.MarshalIndent(domain, "", "  ")
+}
 
-	inspectionData, err := parseV2VInspectionXML(xmlData)
-	if err != nil {
-		if i.logger != nil {
-			i.logger.WithFields(logrus.Fields{
-				"error":  err,
-				"output": outputStr,
-			}).Error("Failed to parse virt-v2v-inspector XML output")
-		}
-		return nil, fmt.Errorf("failed to parse virt-v2v-inspector output: %w", err)
+// diskDeviceName returns the name of the guest device of the disk at index idx (sda, ..., sdz, sdaa, ...)
+func diskDeviceName(idx int) string {
+	var name string
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = string(rune('a'+(idx-1)%26)) + name
 	}
+	return "sd" + name
+}
 
-	i.logger.Info("virt-v2v-inspector snapshot inspection completed successfully")
-	return inspectionData, nil
+// vpxComputeResourceURL returns the vpx URL of a compute resource of vCenter, with the user name and path
+// segments escaped, and the SSL verification option (e.g., "no_verify=1") as query
+func vpxComputeResourceURL(username string, vcenterHost string, computeResourcePath string, sslVerify string) (string, error) {
+	query, err := url.ParseQuery(sslVerify)
+	if err != nil {
+		return "", fmt.Errorf("invalid SSL verification option %q: %w", sslVerify, err)
+	}
+	return (&url.URL{
+		Scheme:   "vpx",
+		User:     url.User(username),
+		Host:     vcenterHost,
+		Path:     computeResourcePath,
+		RawQuery: query.Encode(),
+	}).String(), nil
 }
 
 // extractHostname extracts hostname from a URL
@@ -327,64 +458,65 @@ func extractHostname(urlStr string) string {
 	return urlStr
 }
 
-// findVDDKLibDir finds the VDDK library directory
-func findVDDKLibDir() string {
-	vddkLibDir := "/opt/vmware-vix-disklib"
-	if _, err := os.Stat(vddkLibDir); err == nil {
-		return vddkLibDir
-	}
-
-	// Try alternative locations
-	altPaths := []string{
-		"/usr/lib64/vmware-vix-disklib",
-		"/usr/local/vmware-vix-disklib",
-	}
-	for _, altPath := range altPaths {
-		if _, err := os.Stat(altPath); err == nil {
-			return altPath
-		}
+// readOutputFile reads the output file name written by a tool in dir, copied from the host running the tool
+func readOutputFile(ctx context.Context, executor Executor, dir string, name string) ([]byte, error) {
+	copiedDir, removeCopied, err := executor.Fetch(ctx, dir)
+	if err != nil {
+		return nil, err
 	}
-
-	return ""
+	defer removeCopied()
+	return os.ReadFile(filepath.Join(copiedDir, name))
 }
 
-// createPasswordFile creates a temporary file with the password
+// createPasswordFile creates a temporary file with the password, only readable by the current user
 // virt-v2v-inspector expects -ip to be a file path, not the password directly
-func (i *VirtV2vInspector) createPasswordFile(password string) (string, error) {
-	tmpFile, err := os.CreateTemp("", "v2v-password-*")
+// The returned function removes the file, which is also removed on Cleanup
+func createPasswordFile(ctx context.Context, executor Executor, password string) (string, func(), error) {
+	dir, err := executor.TempDir(ctx, "v2v-password")
 	if err != nil {
-		return "", fmt.Errorf("failed to create temporary password file: %w", err)
-	}
-
-	// Write password to file
-	if _, err := tmpFile.WriteString(password); err != nil {
-		tmpFile.Close()
-		os.Remove(tmpFile.Name())
-		return "", fmt.Errorf("failed to write password to file: %w", err)
+		return "", nil, fmt.Errorf("failed to create temporary password directory: %w", err)
 	}
+	remove := trackTempPath(executor, dir)
 
-	// Close the file
-	if err := tmpFile.Close(); err != nil {
-		os.Remove(tmpFile.Name())
-		return "", fmt.Errorf("failed to close password file: %w", err)
+	passwordFile := filepath.Join(dir, "password")
+	if err := executor.WriteFile(ctx, passwordFile, []byte(password)); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to write password to file: %w", err)
 	}
-
-	// Set restrictive permissions (read-only for owner)
-	if err := os.Chmod(tmpFile.Name(), 0600); err != nil {
-		os.Remove(tmpFile.Name())
-		return "", fmt.Errorf("failed to set password file permissions: %w", err)
-	}
-
-	return tmpFile.Name(), nil
+	return passwordFile, remove, nil
 }
 
-// parseV2VInspectionXML parses virt-v2v-inspector XML output and returns the native XML structure
-func parseV2VInspectionXML(xmlData []byte) (*types.VirtV2VInspectorXML, error) {
+// ParseVirtV2VInspectorXML parses virt-v2v-inspector XML output and returns the native XML structure
+// The output is kept in RawXML
+func ParseVirtV2VInspectorXML(xmlData []byte) (*types.VirtV2VInspectorXML, error) {
 	var xmlRoot types.VirtV2VInspectorXML
 	err := xml.Unmarshal(xmlData, &xmlRoot)
 	if err != nil {
 		return nil, fmt.Errorf("XML parsing error: %w", err)
 	}
 
+	xmlRoot.RawXML = string(xmlData)
 	return &xmlRoot, nil
 }
+
+// withoutVDDKLibraryPath returns env with the VDDK library directories removed from LD_LIBRARY_PATH
+// LD_LIBRARY_PATH is dropped if no other directory remains
+func withoutVDDKLibraryPath(env []string) []string {
+	filtered := make([]string, 0, len(env))
+	for _, e := range env {
+		if !strings.HasPrefix(e, "LD_LIBRARY_PATH=") {
+			filtered = append(filtered, e)
+			continue
+		}
+		var paths []string
+		for _, p := range strings.Split(strings.TrimPrefix(e, "LD_LIBRARY_PATH="), ":") {
+			if !strings.Contains(p, "vmware-vix-disklib") {
+				paths = append(paths, p)
+			}
+		}
+		if len(paths) > 0 {
+			filtered = append(filtered, "LD_LIBRARY_PATH="+strings.Join(paths, ":"))
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,136 @@
+package inspection
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+func TestVPXComputeResourceURL(t *testing.T) {
+	tests := []struct {
+		name                         string
+		username, host, path, verify string
+		want                         string
+		wantErr                      bool
+	}{
+		{
+			name:     "no verification",
+			username: "admin", host: "vcenter.example.com", path: "/DC1/Cluster1/esx1.example.com", verify: "no_verify=1",
+			want: "vpx://admin@vcenter.example.com/DC1/Cluster1/esx1.example.com?no_verify=1",
+		},
+		{
+			name:     "domain user with a space",
+			username: "svc v2v@vsphere.local", host: "vcenter", path: "/DC1/host", verify: "",
+			want: "vpx://svc%20v2v%40vsphere.local@vcenter/DC1/host",
+		},
+		{
+			name:     "escaped path and option",
+			username: "admin", host: "vcenter", path: "/Main DC/Cluster #1/esx1", verify: "cacert=/etc/pki/ca bundle.crt",
+			want: "vpx://admin@vcenter/Main%20DC/Cluster%20%231/esx1?cacert=%2Fetc%2Fpki%2Fca+bundle.crt",
+		},
+		{
+			name:     "invalid option",
+			username: "admin", host: "vcenter", path: "/DC1/host", verify: "no_verify=%zz",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vpxComputeResourceURL(tt.username, tt.host, tt.path, tt.verify)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("vpxComputeResourceURL() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("vpxComputeResourceURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLibvirtDomainXML(t *testing.T) {
+	data, err := libvirtDomainXML("vm <1> & co", []string{"nbd+unix:///?socket=/run/disk-0.sock", "nbd://conversion:10810/disk1"})
+	if err != nil {
+		t.Fatalf("libvirtDomainXML() error = %v", err)
+	}
+	var domain libvirtDomain
+	if err := xml.Unmarshal(data, &domain); err != nil {
+		t.Fatalf("invalid domain XML %s: %v", data, err)
+	}
+	if domain.Name != "vm <1> & co" {
+		t.Errorf("Name = %q, want the escaped VM name", domain.Name)
+	}
+	want := []libvirtDisk{
+		{
+			Type: "network", Device: "disk", Driver: libvirtDiskDriver{Name: "qemu", Type: "raw"},
+			Source: libvirtDiskSource{Protocol: "nbd", Host: libvirtDiskHost{Transport: "unix", Socket: "/run/disk-0.sock"}},
+			Target: libvirtDiskTarget{Dev: "sda"},
+		},
+		{
+			Type: "network", Device: "disk", Driver: libvirtDiskDriver{Name: "qemu", Type: "raw"},
+			Source: libvirtDiskSource{Protocol: "nbd", Name: "disk1", Host: libvirtDiskHost{Name: "conversion", Port: "10810"}},
+			Target: libvirtDiskTarget{Dev: "sdb"},
+		},
+	}
+	if !reflect.DeepEqual(domain.Disks, want) {
+		t.Errorf("Disks = %+v, want %+v", domain.Disks, want)
+	}
+
+	if _, err := libvirtDomainXML("vm", []string{"http://vcenter/disk"}); err == nil {
+		t.Error("libvirtDomainXML() error = nil, want an error for a non-NBD URL")
+	}
+}
+
+func TestDiskDeviceName(t *testing.T) {
+	for idx, want := range map[int]string{0: "sda", 1: "sdb", 25: "sdz", 26: "sdaa", 27: "sdab", 701: "sdzz", 702: "sdaaa"} {
+		if got := diskDeviceName(idx); got != want {
+			t.Errorf("diskDeviceName(%d) = %s, want %s", idx, got, want)
+		}
+	}
+}
+
+// fakeVirtV2vInspector is a virt-v2v-inspector reporting UEFI firmware for a libvirt domain with the disk
+// of the first stub session of vm-1
+const fakeVirtV2vInspector = `#!/bin/sh
+while [ $# -gt 0 ]; do
+	case "$1" in
+	-i) input="$2"; shift ;;
+	-O) output="$2"; shift ;;
+	--) source="$2"; shift ;;
+	esac
+	shift
+done
+[ "$input" = libvirtxml ] || exit 2
+grep -q 'socket="/run/vm-1-snapshot-1-1.sock"' "$source" || exit 3
+echo '<v2v-inspection><firmware type="uefi"/></v2v-inspection>' >"$output"
+`
+
+func TestVirtV2vInspectorReadsSessions(t *testing.T) {
+	tool := filepath.Join(t.TempDir(), "virt-v2v-inspector")
+	if err := os.WriteFile(tool, []byte(fakeVirtV2vInspector), 0700); err != nil {
+		t.Fatal(err)
+	}
+	stub := &stubSessions{}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	inspector := NewVirtV2vInspector(tool, VDDKOptions{}, VirtV2vInspectorOptions{}, time.Minute, logger, newStubSessionManager(0, stub))
+
+	result, err := inspector.Inspect(context.Background(), "vm-1", "snapshot-1", "https://vcenter", "DC1", "user", "secret", &types.SnapshotDiskInfo{}, "")
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if result.Firmware.Type != "uefi" {
+		t.Errorf("Firmware = %q, want uefi", result.Firmware.Type)
+	}
+	if opened := stub.opened.Load(); opened != 1 {
+		t.Errorf("sessions opened = %d, want 1", opened)
+	}
+	waitFor(t, "the session released after the inspection to close", func() bool { return stub.closed.Load() == 1 })
+}
@@ -4,13 +4,22 @@ import (
 	"context"
 	"fmt"
 	"net/url"
-	"os"
 	"os/exec"
+	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
 type V2VSession struct {
-	NBDURL string
-	cmd    *exec.Cmd
+	NBDURL     string
+	socketPath string // Unix socket served by virt-v2v-open
+	executor   Executor
+	cmd        *exec.Cmd
+	removePass func() // Removes the password file, kept while virt-v2v-open runs
+	output     *outputBuffer
+	done       chan struct{} // Closed once the virt-v2v-open process has exited
+	waitErr    error         // Exit error of the virt-v2v-open process, set before done is closed
+	unregister func()        // Unregisters Close from the cleanup registry
 }
 
 func OpenWithVirtV2V(
@@ -21,6 +30,8 @@ func OpenWithVirtV2V(
 	vcenterURL string,
 	username string,
 	password string,
+	options SessionOptions, // VDDK settings and executor of the session
+	logger *logrus.Logger,
 ) (*V2VSession, error) {
 
 	parsedURL, err := url.Parse(vcenterURL)
@@ -29,50 +40,126 @@ func OpenWithVirtV2V(
 	}
 
 	vcenterHost := parsedURL.Hostname()
+	executor := options.executor()
 
 	if datacenter == "" {
 		return nil, fmt.Errorf("datacenter cannot be empty")
 	}
 
-	// Build vpx source URL
-	vpxURL := fmt.Sprintf(
-		"vpx://%s@%s/%s/%s?snapshot=%s&no_verify=1&password=%s",
-		username,
-		vcenterHost,
-		datacenter,
-		vmName,
-		snapshotName,
-		password,
-	)
+	// VDDK verifies the vCenter certificate against the thumbprint, like nbdkit-vddk sessions
+	thumbprint, err := options.VDDK.thumbprint(vcenterHost, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// The password is passed in a file so it does not appear in the URL or process tables
+	vpxURL := vpxSourceURL(username, vcenterHost, datacenter, vmName, snapshotName, thumbprint != "")
+	passwordFile, removePasswordFile, err := createPasswordFile(ctx, executor, password)
+	if err != nil {
+		return nil, err
+	}
 
 	args := []string{
 		"-it", "vddk",
-		vpxURL,
-		"-o", "nbd",
+		"-ip", passwordFile,
+	}
+	if thumbprint != "" {
+		args = append(args, "-io", fmt.Sprintf("vddk-thumbprint=%s", thumbprint))
+	}
+	args = append(args, vpxURL, "-o", "nbd")
+
+	// Serve on a private unix socket on the host of the executor, only accessible by the current user, so
+	// concurrent sessions on one host don't collide on the default NBD port
+	socketPath, err := newNBDSocket(ctx, executor, "virt-v2v-open")
+	if err != nil {
+		removePasswordFile()
+		return nil, err
 	}
+	args = append(args, "-oo", fmt.Sprintf("socket=%s", socketPath))
+	nbdURL := nbdUnixURL(socketPath)
 
-	cmd := exec.CommandContext(ctx, "virt-v2v-open", args...)
+	cmd := executor.Command(ctx, "virt-v2v-open", args...)
 
-	// Optional: pipe output to your logger / stdout for debugging
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	// Keep the end of the output for errors when the NBD server fails
+	output := newOutputBuffer(maxToolOutput)
+	cmd.Stdout = output
+	cmd.Stderr = output
 
 	if err := cmd.Start(); err != nil {
+		removeNBDSocket(executor, socketPath)
+		removePasswordFile()
 		return nil, fmt.Errorf("failed to start virt-v2v-open: %w", err)
 	}
 
-	// Default port used by virt-v2v-open
-	nbdURL := "nbd://localhost:10809"
+	session := &V2VSession{
+		NBDURL:     nbdURL,
+		socketPath: socketPath,
+		executor:   executor,
+		cmd:        cmd,
+		removePass: removePasswordFile,
+		output:     output,
+		done:       make(chan struct{}),
+	}
+	go func() {
+		session.waitErr = cmd.Wait()
+		close(session.done)
+	}()
+	session.unregister = registerCleanup(session.Close)
+	return session, nil
+}
+
+// vpxSourceURL returns the vpx URL of the snapshot of the VM, with the user name and path segments escaped
+// libvirt verifies the vCenter certificate against the system roots, unless VDDK connects without verification
+// too (verify is false)
+func vpxSourceURL(username string, vcenterHost string, datacenter string, vmName string, snapshotName string, verify bool) string {
+	query := url.Values{"snapshot": {snapshotName}}
+	if !verify {
+		query.Set("no_verify", "1")
+	}
+	return (&url.URL{
+		Scheme:   "vpx",
+		User:     url.User(username),
+		Host:     vcenterHost,
+		Path:     "/" + datacenter + "/" + vmName,
+		RawQuery: query.Encode(),
+	}).String()
+}
 
-	return &V2VSession{
-		NBDURL: nbdURL,
-		cmd:    cmd,
-	}, nil
+// WaitForReady waits until the NBD server completes a handshake for its export
+func (s *V2VSession) WaitForReady(ctx context.Context, timeout time.Duration) error {
+	_, err := waitForNBD(ctx, s.executor, s.NBDURL, timeout, func() error {
+		select {
+		case <-s.done:
+			reason := "virt-v2v-open process exited"
+			if s.waitErr != nil {
+				reason += ": " + s.waitErr.Error()
+			}
+			if s.output.Len() > 0 {
+				return fmt.Errorf("%s (output: %s)", reason, s.output.String())
+			}
+			return fmt.Errorf("%s", reason)
+		default:
+			return nil
+		}
+	})
+	return err
 }
 
 func (s *V2VSession) Close() {
-	if s != nil && s.cmd != nil && s.cmd.Process != nil {
-		_ = s.cmd.Process.Kill()
-		_, _ = s.cmd.Process.Wait()
+	if s == nil {
+		return
+	}
+	if s.unregister != nil {
+		s.unregister()
+	}
+	if s.cmd != nil && s.cmd.Process != nil {
+		_ = killCommand(s.cmd)
+		<-s.done
+	}
+	if s.socketPath != "" {
+		removeNBDSocket(s.executor, s.socketPath)
+	}
+	if s.removePass != nil {
+		s.removePass()
 	}
 }
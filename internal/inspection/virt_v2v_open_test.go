@@ -0,0 +1,40 @@
+package inspection
+
+import "testing"
+
+func TestVPXSourceURL(t *testing.T) {
+	tests := []struct {
+		name                                     string
+		username, host, datacenter, vm, snapshot string
+		verify                                   bool
+		want                                     string
+	}{
+		{
+			name:     "plain",
+			username: "admin", host: "vcenter.example.com", datacenter: "DC1", vm: "rhel9", snapshot: "snapshot-42",
+			want: "vpx://admin@vcenter.example.com/DC1/rhel9?no_verify=1&snapshot=snapshot-42",
+		},
+		{
+			name:     "verified",
+			username: "admin", host: "vcenter.example.com", datacenter: "DC1", vm: "rhel9", snapshot: "snapshot-42", verify: true,
+			want: "vpx://admin@vcenter.example.com/DC1/rhel9?snapshot=snapshot-42",
+		},
+		{
+			name:     "domain user",
+			username: "administrator@vsphere.local", host: "vcenter", datacenter: "DC1", vm: "rhel9", snapshot: "snapshot-42",
+			want: "vpx://administrator%40vsphere.local@vcenter/DC1/rhel9?no_verify=1&snapshot=snapshot-42",
+		},
+		{
+			name:     "special characters",
+			username: "svc:v2v", host: "vcenter", datacenter: "Main DC", vm: "web#1", snapshot: "pre migration&x",
+			want: "vpx://svc%3Av2v@vcenter/Main%20DC/web%231?no_verify=1&snapshot=pre+migration%26x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vpxSourceURL(tt.username, tt.host, tt.datacenter, tt.vm, tt.snapshot, tt.verify); got != tt.want {
+				t.Errorf("vpxSourceURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,14 +20,130 @@ type Credentials struct {
 	Password   string
 }
 
+// SessionOptions configures the NBD sessions used to read VM snapshot disks (VDDK, nbdkit filters)
+type SessionOptions = inspection.SessionOptions
+
+// VDDKOptions configures the VDDK library and the vCenter thumbprint verification
+type VDDKOptions = inspection.VDDKOptions
+
+// HTTPSOptions configures the HTTPS datastore fallback of VDDK sessions
+type HTTPSOptions = inspection.HTTPSOptions
+
+// RetryPolicy retries session opening and inspection tools on transient failures
+type RetryPolicy = inspection.RetryPolicy
+
+// RetryError reports all attempts of an operation that failed after retries
+type RetryError = inspection.RetryError
+
+// FeatureError reports a requested feature not supported by the installed version of a tool
+type FeatureError = inspection.FeatureError
+
+// ToolError is the failure of an inspection tool with the end of its standard error
+type ToolError = inspection.ToolError
+
+// VirtInspectorOptions configures the virt-inspector command line
+type VirtInspectorOptions = inspection.VirtInspectorOptions
+
+// Metrics collects metrics of inspections and NBD sessions, it is a Prometheus collector
+type Metrics = inspection.Metrics
+
+// NewMetrics creates a new Metrics
+var NewMetrics = inspection.NewMetrics
+
+// Cleanup stops the NBD servers and removes the temporary files of all sessions and inspections in progress
+var Cleanup = inspection.Cleanup
+
+// CleanupOnSignal runs Cleanup when the process receives SIGINT or SIGTERM, until ctx is done
+var CleanupOnSignal = inspection.CleanupOnSignal
+
+// HealthcheckConfig is the environment verified by Healthcheck
+type HealthcheckConfig = inspection.HealthcheckConfig
+
+// HealthReport is the result of Healthcheck
+type HealthReport = inspection.HealthReport
+
+// ComponentHealth is the health of a component needed by inspections
+type ComponentHealth = inspection.ComponentHealth
+
+// Healthcheck verifies that the inspection tools and VDDK are runnable and that vCenter is reachable
+var Healthcheck = inspection.Healthcheck
+
+// VirtV2vInspectorOptions configures the virt-v2v-inspector command line
+type VirtV2vInspectorOptions = inspection.VirtV2vInspectorOptions
+
+// DecryptionKey is a key of an encrypted (LUKS or BitLocker) guest device
+type DecryptionKey = inspection.DecryptionKey
+
+// InspectionEvent reports a phase transition of an inspection
+type InspectionEvent = inspection.InspectionEvent
+
+// InspectionPhase is a step of an inspection reported to the event handler of an inspector
+type InspectionPhase = inspection.InspectionPhase
+
+// EventHandler receives the events of inspections
+type EventHandler = inspection.EventHandler
+
+// Inspection phases reported to event handlers
+const (
+	PhaseAuthFileCreated  = inspection.PhaseAuthFileCreated
+	PhaseNBDServerStarted = inspection.PhaseNBDServerStarted
+	PhaseNBDReady         = inspection.PhaseNBDReady
+	PhaseToolStarted      = inspection.PhaseToolStarted
+	PhaseXMLParsed        = inspection.PhaseXMLParsed
+)
+
+// InspectorOptions configures the command lines of the inspection tools
+type InspectorOptions struct {
+	VirtInspector    VirtInspectorOptions
+	VirtV2vInspector VirtV2vInspectorOptions
+
+	// Metrics collects the metrics of the inspections and their NBD sessions (optional)
+	// It is used by the tools and sessions without their own Metrics
+	Metrics *Metrics
+
+	// Recording records the results of the inspector to disk, or replays recorded results (optional)
+	Recording *Recording
+}
+
+// SSHOptions configures access to the ESXi host for the SSH disk transport
+type SSHOptions = inspection.SSHOptions
+
+// Disk transports of SessionOptions
+const (
+	TransportVDDK = inspection.TransportVDDK
+	TransportSSH  = inspection.TransportSSH
+)
+
+// NBDKitFilters selects the nbdkit filters layered on the VDDK plugin
+type NBDKitFilters = inspection.NBDKitFilters
+
+// VDDKLibrary is a VDDK installation with its version
+type VDDKLibrary = inspection.VDDKLibrary
+
+// Executor runs the NBD servers and inspection tools
+type Executor = inspection.Executor
+
+// LocalExecutor runs the NBD servers and inspection tools on the local host
+type LocalExecutor = inspection.LocalExecutor
+
+// ContainerExecutor runs the NBD servers and inspection tools in containers of an image
+type ContainerExecutor = inspection.ContainerExecutor
+
+// SSHExecutor runs the NBD servers and inspection tools on a remote conversion host over SSH
+type SSHExecutor = inspection.SSHExecutor
+
 // CacheKey represents a unique identifier for a VM+snapshot pair
 type CacheKey struct {
 	VMName       string
 	SnapshotName string
+	Variant      string // Inspection variant, e.g. "no-applications" for partial results (empty for full inspections)
 }
 
 // String returns a string representation of the cache key
 func (k CacheKey) String() string {
+	if k.Variant != "" {
+		return fmt.Sprintf("%s:%s:%s", k.VMName, k.SnapshotName, k.Variant)
+	}
 	return fmt.Sprintf("%s:%s", k.VMName, k.SnapshotName)
 }
 
@@ -60,6 +176,10 @@ type DB interface {
 type Inspector struct {
 	virtInspector       *inspection.VirtInspector
 	virtV2vInspector    *inspection.VirtV2vInspector
+	vddkLibDir          string
+	executor            inspection.Executor
+	virtVariant         string
+	recording           *Recording
 	db                  DB
 	credentials         Credentials
 	virtMemoryCache     *virtInspectorMemoryCache
@@ -69,17 +189,73 @@ type Inspector struct {
 	logger              *logrus.Logger
 }
 
+// sessionIdleTimeout is how long the NBD session of a VM snapshot stays open after an inspection,
+// so the inspections and guest file reads of the checks of a VM share one session
+const sessionIdleTimeout = time.Minute
+
 // NewInspector creates a new Inspector that supports both inspection methods
 // virtInspectorPath: path to virt-inspector executable (uses system PATH if empty)
 // virtV2vInspectorPath: path to virt-v2v-inspector executable (uses system PATH if empty)
-// timeout: timeout for inspection operations (defaults to 5 minutes if zero)
+// timeout: timeout of each virt-inspector, virt-v2v-inspector and guestfish run (defaults to 5 minutes if zero),
+// opening NBD sessions is limited by the StartTimeout and ReadyTimeout of sessionOptions
 // credentials: vCenter access credentials
+// sessionOptions: options of the NBD sessions to VM snapshot disks, shared by virt-inspector and virt-v2v-inspector;
+// its VDDK options are used by virt-v2v-inspector when it runs with its own executor (defaults if nil)
+// inspectorOptions: command line options of the inspection tools (defaults if nil)
 // logger: logger instance for logging (can be nil)
 // db: database implementation provided by caller (can be nil for memory-only caching)
-func NewInspector(virtInspectorPath string, virtV2vInspectorPath string, timeout time.Duration, credentials Credentials, logger *logrus.Logger, db DB) *Inspector {
+func NewInspector(virtInspectorPath string, virtV2vInspectorPath string, timeout time.Duration, credentials Credentials, sessionOptions *SessionOptions, inspectorOptions *InspectorOptions, logger *logrus.Logger, db DB) *Inspector {
+	var vddk inspection.VDDKOptions
+	var executor inspection.Executor
+	if sessionOptions != nil {
+		vddk = sessionOptions.VDDK
+		executor = sessionOptions.Executor
+	}
+	if inspectorOptions == nil {
+		inspectorOptions = &InspectorOptions{}
+	}
+	virtOptions, v2vOptions := inspectorOptions.VirtInspector, inspectorOptions.VirtV2vInspector
+	// virt-v2v-inspector reads the shared NBD sessions, unless it runs with its own executor and VDDK connection
+	useSessions := v2vOptions.Executor == nil
+	if v2vOptions.Executor == nil {
+		v2vOptions.Executor = executor
+	}
+	if executor == nil {
+		executor = inspection.LocalExecutor{}
+	}
+	if metrics := inspectorOptions.Metrics; metrics != nil {
+		if virtOptions.Metrics == nil {
+			virtOptions.Metrics = metrics
+		}
+		if v2vOptions.Metrics == nil {
+			v2vOptions.Metrics = metrics
+		}
+		if sessionOptions == nil || sessionOptions.Metrics == nil {
+			options := SessionOptions{}
+			if sessionOptions != nil {
+				options = *sessionOptions
+			}
+			options.Metrics = metrics
+			sessionOptions = &options
+		}
+	}
+	// Results without applications must not be served to inspectors expecting them
+	var virtVariant string
+	if inspectorOptions.VirtInspector.NoApplications {
+		virtVariant = "no-applications"
+	}
+	sessions := inspection.NewSessionManager(sessionIdleTimeout, sessionOptions, logger)
+	var v2vSessions *inspection.SessionManager
+	if useSessions {
+		v2vSessions = sessions
+	}
 	return &Inspector{
-		virtInspector:      inspection.NewVirtInspector(virtInspectorPath, timeout, logger),
-		virtV2vInspector:   inspection.NewVirtV2vInspector(virtV2vInspectorPath, timeout, logger),
+		virtInspector:      inspection.NewVirtInspector(virtInspectorPath, virtOptions, timeout, logger, sessions),
+		virtV2vInspector:   inspection.NewVirtV2vInspector(virtV2vInspectorPath, vddk, v2vOptions, timeout, logger, v2vSessions),
+		vddkLibDir:         vddk.LibDir,
+		executor:           executor,
+		virtVariant:        virtVariant,
+		recording:          inspectorOptions.Recording,
 		db:                 db,
 		credentials:        credentials,
 		virtMemoryCache:    newVirtInspectorMemoryCache(),
@@ -90,6 +266,27 @@ func NewInspector(virtInspectorPath string, virtV2vInspectorPath string, timeout
 	}
 }
 
+// VDDKLibrary validates the VDDK library used by the inspections and returns its location and version
+func (p *Inspector) VDDKLibrary() (*VDDKLibrary, error) {
+	return p.executor.VDDKLibrary(p.vddkLibDir)
+}
+
+// ToolVersions returns the versions of the inspection tools and VDDK, detected once per process
+// Tools whose version cannot be detected are omitted, and no tools are run when replaying a recording
+func (p *Inspector) ToolVersions(ctx context.Context) map[string]string {
+	if p.recording != nil && p.recording.Mode == RecordingReplay {
+		return map[string]string{}
+	}
+	versions := p.virtInspector.ToolVersions(ctx)
+	if version, err := p.virtV2vInspector.Version(ctx); err == nil {
+		versions["virt-v2v-inspector"] = version
+	}
+	if library, err := p.VDDKLibrary(); err == nil && library.Version != "" {
+		versions["vddk"] = library.Version
+	}
+	return versions
+}
+
 // InspectWithVirt performs inspection using VirtInspector with memory and DB caching
 // Concurrent calls for the same VM-snapshot key will wait for the first call to complete
 func (p *Inspector) InspectWithVirt(
@@ -102,6 +299,7 @@ func (p *Inspector) InspectWithVirt(
 	key := CacheKey{
 		VMName:       vmName,
 		SnapshotName: snapshotName,
+		Variant:      p.virtVariant,
 	}
 
 	// Check memory cache first
@@ -157,7 +355,9 @@ func (p *Inspector) InspectWithVirt(
 			}).Info("Performing new inspection (not found in cache)")
 		}
 
-		result, err := p.virtInspector.Inspect(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo)
+		result, err := recordInspection(p.recording, key, "virt-inspector", rawVirtInspectorXML, inspection.ParseVirtInspectorXML, func() (*types.VirtInspectorXML, error) {
+			return p.virtInspector.Inspect(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -256,7 +456,9 @@ func (p *Inspector) InspectWithVirtV2v(
 			}).Info("Performing new inspection (not found in cache)")
 		}
 
-		result, err := p.virtV2vInspector.Inspect(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo, sslVerify)
+		result, err := recordInspection(p.recording, key, "virt-v2v-inspector", rawVirtV2VInspectorXML, inspection.ParseVirtV2VInspectorXML, func() (*types.VirtV2VInspectorXML, error) {
+			return p.virtV2vInspector.Inspect(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo, sslVerify)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -287,6 +489,192 @@ func (p *Inspector) InspectWithVirtV2v(
 	return result, err
 }
 
+// CloseSessions closes the NBD sessions of VM snapshots that are not used by an inspection
+// Sessions are otherwise closed after being unused for a minute
+func (p *Inspector) CloseSessions() {
+	p.virtInspector.CloseSessions()
+}
+
+// ReadGuestFiles reads files or directories from the guest filesystems of a VM snapshot
+// Results are not cached; paths missing in the guest are omitted from the result
+func (p *Inspector) ReadGuestFiles(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	datacenter string,
+	diskInfo *types.SnapshotDiskInfo,
+	paths []string,
+) (map[string][]byte, error) {
+	if p.logger != nil {
+		p.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+			"paths":         paths,
+		}).Debug("Reading guest files")
+	}
+	key := CacheKey{VMName: vmName, SnapshotName: snapshotName}
+	return recordGuestOperation(p.recording, key, "ReadGuestFiles", paths, func() (map[string][]byte, error) {
+		return p.virtInspector.ReadFiles(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo, paths)
+	})
+}
+
+// ListGuestDirectory lists the entry names of a directory in the guest filesystems of a VM snapshot
+// Results are not cached
+func (p *Inspector) ListGuestDirectory(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	datacenter string,
+	diskInfo *types.SnapshotDiskInfo,
+	dir string,
+) ([]string, error) {
+	if p.logger != nil {
+		p.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+			"dir":           dir,
+		}).Debug("Listing guest directory")
+	}
+	key := CacheKey{VMName: vmName, SnapshotName: snapshotName}
+	return recordGuestOperation(p.recording, key, "ListGuestDirectory", []string{dir}, func() ([]string, error) {
+		return p.virtInspector.ListDirectory(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo, dir)
+	})
+}
+
+// PathsExist reports whether the given paths exist in the guest filesystems of a VM snapshot
+// Results are not cached
+func (p *Inspector) PathsExist(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	datacenter string,
+	diskInfo *types.SnapshotDiskInfo,
+	paths []string,
+) (map[string]bool, error) {
+	if p.logger != nil {
+		p.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+			"paths":         paths,
+		}).Debug("Checking guest paths")
+	}
+	key := CacheKey{VMName: vmName, SnapshotName: snapshotName}
+	return recordGuestOperation(p.recording, key, "PathsExist", paths, func() (map[string]bool, error) {
+		return p.virtInspector.PathsExist(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo, paths)
+	})
+}
+
+// ListInitramfsFiles lists the contents of the initramfs images in the guest /boot directory
+// Results are not cached
+func (p *Inspector) ListInitramfsFiles(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	datacenter string,
+	diskInfo *types.SnapshotDiskInfo,
+) (map[string][]string, error) {
+	if p.logger != nil {
+		p.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+		}).Debug("Listing initramfs contents")
+	}
+	key := CacheKey{VMName: vmName, SnapshotName: snapshotName}
+	return recordGuestOperation(p.recording, key, "ListInitramfsFiles", nil, func() (map[string][]string, error) {
+		return p.virtInspector.ListInitramfsFiles(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo)
+	})
+}
+
+// FilesystemUsage collects usage statistics for the given guest mount points
+// Results are not cached
+func (p *Inspector) FilesystemUsage(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	datacenter string,
+	diskInfo *types.SnapshotDiskInfo,
+	mountPoints []string,
+) (map[string]types.FilesystemUsage, error) {
+	if p.logger != nil {
+		p.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+			"mount_points":  mountPoints,
+		}).Debug("Collecting filesystem usage")
+	}
+	key := CacheKey{VMName: vmName, SnapshotName: snapshotName}
+	return recordGuestOperation(p.recording, key, "FilesystemUsage", mountPoints, func() (map[string]types.FilesystemUsage, error) {
+		return p.virtInspector.FilesystemUsage(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo, mountPoints)
+	})
+}
+
+// PartitionTables reports the partition table type of every disk of the VM snapshot
+// Results are not cached
+func (p *Inspector) PartitionTables(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	datacenter string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]types.PartitionTable, error) {
+	if p.logger != nil {
+		p.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+		}).Debug("Reading partition tables")
+	}
+	key := CacheKey{VMName: vmName, SnapshotName: snapshotName}
+	return recordGuestOperation(p.recording, key, "PartitionTables", nil, func() ([]types.PartitionTable, error) {
+		return p.virtInspector.PartitionTables(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo)
+	})
+}
+
+// LVMLayout reports the LVM physical and logical volumes of the VM snapshot
+// Results are not cached
+func (p *Inspector) LVMLayout(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	datacenter string,
+	diskInfo *types.SnapshotDiskInfo,
+) (*types.LVMLayout, error) {
+	if p.logger != nil {
+		p.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+		}).Debug("Reading LVM layout")
+	}
+	key := CacheKey{VMName: vmName, SnapshotName: snapshotName}
+	return recordGuestOperation(p.recording, key, "LVMLayout", nil, func() (*types.LVMLayout, error) {
+		return p.virtInspector.LVMLayout(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo)
+	})
+}
+
+// ListEnabledServices lists the systemd units enabled in the guest of the VM snapshot
+// Results are not cached
+func (p *Inspector) ListEnabledServices(
+	ctx context.Context,
+	vmName string,
+	snapshotName string,
+	datacenter string,
+	diskInfo *types.SnapshotDiskInfo,
+) ([]types.EnabledService, error) {
+	if p.logger != nil {
+		p.logger.WithFields(logrus.Fields{
+			"vm_name":       vmName,
+			"snapshot_name": snapshotName,
+		}).Debug("Listing enabled services")
+	}
+	key := CacheKey{VMName: vmName, SnapshotName: snapshotName}
+	return recordGuestOperation(p.recording, key, "ListEnabledServices", nil, func() ([]types.EnabledService, error) {
+		return p.virtInspector.ListEnabledServices(ctx, vmName, snapshotName, p.credentials.VCenterURL, datacenter, p.credentials.Username, p.credentials.Password, diskInfo)
+	})
+}
+
+// rawVirtInspectorXML and rawVirtV2VInspectorXML return the tool output of inspection results, for recordings
+func rawVirtInspectorXML(result *types.VirtInspectorXML) string       { return result.RawXML }
+func rawVirtV2VInspectorXML(result *types.VirtV2VInspectorXML) string { return result.RawXML }
+
 // virtInspectorMemoryCache provides in-memory caching for VirtInspector results
 type virtInspectorMemoryCache struct {
 	mu    sync.RWMutex
@@ -0,0 +1,163 @@
+package persistent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RecordingMode selects whether an Inspector records its results to disk or replays recorded results
+type RecordingMode string
+
+const (
+	// RecordingRecord runs the inspection tools and saves their results, replacing previous recordings
+	RecordingRecord RecordingMode = "record"
+	// RecordingReplay serves the saved results without running the tools or contacting vCenter
+	RecordingReplay RecordingMode = "replay"
+)
+
+// ErrNotRecorded is returned in replay mode for results without a recording
+var ErrNotRecorded = errors.New("not recorded")
+
+// Recording saves or replays the results of an Inspector in a directory, for reproducible integration tests
+// and offline demo environments
+// Each VM snapshot has a directory with the XML output of virt-inspector and virt-v2v-inspector, and the
+// results of guest file reads in JSON:
+//
+//	<Dir>/<vm>/<snapshot>/virt-inspector.xml
+//	<Dir>/<vm>/<snapshot>/virt-v2v-inspector.xml
+//	<Dir>/<vm>/<snapshot>/guest/<operation>-<arguments hash>.json
+//
+// Recordings contain guest files, so they are only readable by the current user
+type Recording struct {
+	Dir  string
+	Mode RecordingMode
+}
+
+// snapshotDir returns the directory of the recordings of a VM snapshot
+func (r *Recording) snapshotDir(key CacheKey) string {
+	return filepath.Join(r.Dir, recordingName(key.VMName), recordingName(key.SnapshotName))
+}
+
+// recordingName escapes a VM or snapshot name as a single path component
+func recordingName(name string) string {
+	escaped := url.PathEscape(name)
+	if strings.Trim(escaped, ".") == "" {
+		// "", "." and ".." are not file names, escaped names of other VMs never contain "%2E" or "%00"
+		return strings.ReplaceAll(escaped, ".", "%2E") + "%00"
+	}
+	return escaped
+}
+
+// inspectionFile returns the path of the recorded XML output of tool for a VM snapshot
+func (r *Recording) inspectionFile(key CacheKey, tool string) string {
+	name := tool
+	if key.Variant != "" {
+		name += "-" + key.Variant
+	}
+	return filepath.Join(r.snapshotDir(key), name+".xml")
+}
+
+// guestFile returns the path of the recorded result of a guest file operation with args for a VM snapshot
+func (r *Recording) guestFile(key CacheKey, operation string, args []string) string {
+	hash := sha256.Sum256([]byte(strings.Join(args, "\x00")))
+	return filepath.Join(r.snapshotDir(key), "guest", operation+"-"+hex.EncodeToString(hash[:8])+".json")
+}
+
+// recordInspection runs inspect and records the XML output of tool, or replays the recorded output
+// rawXML returns the output of a result; parse parses a recorded output
+func recordInspection[T any](r *Recording, key CacheKey, tool string, rawXML func(T) string, parse func([]byte) (T, error), inspect func() (T, error)) (T, error) {
+	var zero T
+	if r == nil {
+		return inspect()
+	}
+	file := r.inspectionFile(key, tool)
+	switch r.Mode {
+	case RecordingReplay:
+		data, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			return zero, fmt.Errorf("%s output of %s: %w", tool, key, ErrNotRecorded)
+		} else if err != nil {
+			return zero, fmt.Errorf("failed to read recorded %s output: %w", tool, err)
+		}
+		return parse(data)
+	case RecordingRecord:
+		result, err := inspect()
+		if err != nil {
+			return zero, err
+		}
+		if err := writeRecording(file, []byte(rawXML(result))); err != nil {
+			return zero, err
+		}
+		return result, nil
+	default:
+		return zero, fmt.Errorf("unknown recording mode %q", r.Mode)
+	}
+}
+
+// recordGuestOperation runs a guest file operation with args and records its result, or replays the
+// recorded result
+func recordGuestOperation[T any](r *Recording, key CacheKey, operation string, args []string, run func() (T, error)) (T, error) {
+	var result T
+	if r == nil {
+		return run()
+	}
+	file := r.guestFile(key, operation, args)
+	switch r.Mode {
+	case RecordingReplay:
+		data, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			return result, fmt.Errorf("%s %q of %s: %w", operation, args, key, ErrNotRecorded)
+		} else if err != nil {
+			return result, fmt.Errorf("failed to read recorded %s result: %w", operation, err)
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return result, fmt.Errorf("failed to parse recorded %s result: %w", operation, err)
+		}
+		return result, nil
+	case RecordingRecord:
+		result, err := run()
+		if err != nil {
+			return result, err
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return result, fmt.Errorf("failed to encode %s result: %w", operation, err)
+		}
+		if err := writeRecording(file, data); err != nil {
+			return result, err
+		}
+		return result, nil
+	default:
+		return result, fmt.Errorf("unknown recording mode %q", r.Mode)
+	}
+}
+
+// writeRecording atomically writes a recording file, creating its directories
+func writeRecording(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".recording-*")
+	if err != nil {
+		return fmt.Errorf("failed to create recording: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to save recording: %w", err)
+	}
+	return nil
+}
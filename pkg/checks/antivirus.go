@@ -0,0 +1,127 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// DefaultAntivirusProducts lists name fragments of common antivirus/EDR products
+// Matching is case-insensitive against installed application names
+var DefaultAntivirusProducts = []string{
+	"CrowdStrike",
+	"falcon-sensor",
+	"Defender for Endpoint",
+	"Defender Advanced Threat Protection",
+	"mdatp",
+	"McAfee",
+	"Trellix",
+	"Symantec",
+	"Trend Micro",
+	"ds_agent",
+	"Sophos",
+	"Carbon Black",
+	"cb-psc-sensor",
+	"SentinelOne",
+	"Cylance",
+	"Cortex XDR",
+	"ESET Endpoint",
+	"ESET Server Security",
+	"Kaspersky",
+}
+
+// AntivirusCheck detects antivirus/EDR agents in the application inventory
+// Their driver-level filters may need re-registration or removal after conversion
+type AntivirusCheck struct {
+	products []string
+}
+
+// NewAntivirusCheck creates a new AntivirusCheck
+// products: product name fragments to look for (uses DefaultAntivirusProducts if nil)
+func NewAntivirusCheck(products []string) *AntivirusCheck {
+	if products == nil {
+		products = DefaultAntivirusProducts
+	}
+	return &AntivirusCheck{
+		products: products,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *AntivirusCheck) ID() string {
+	return "antivirus-agents"
+}
+
+// Category returns the category of the check
+func (c *AntivirusCheck) Category() string {
+	return CategoryApplications
+}
+
+// Tags returns the tags of the check
+func (c *AntivirusCheck) Tags() []string {
+	return []string{"windows", "linux", "security"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *AntivirusCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *AntivirusCheck) Name() string {
+	return "Antivirus/EDR Agents"
+}
+
+// Describe returns the metadata of the check
+func (c *AntivirusCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects antivirus/EDR agents in the application inventory. Their driver-level filters may need re-registration or removal after conversion.", OSFamilyLinux, OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and reports installed antivirus/EDR products
+func (c *AntivirusCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate matches installed applications against the product list
+func (c *AntivirusCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var details []string
+	for _, guest := range data.Operatingsystems {
+		for _, app := range guest.Applications.Application {
+			if product := c.match(app.Name); product != "" {
+				details = append(details, fmt.Sprintf("%s %s (matches %s)", app.Name, app.Version, product))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("antivirus-agents.found")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("antivirus-agents.none")
+}
+
+// match returns the product matching the application name, or "" if none matches
+func (c *AntivirusCheck) match(appName string) string {
+	name := strings.ToLower(appName)
+	for _, product := range c.products {
+		if strings.Contains(name, strings.ToLower(product)) {
+			return product
+		}
+	}
+	return ""
+}
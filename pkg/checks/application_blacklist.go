@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// ApplicationBlacklistCheck fails or warns when installed applications match caller-supplied patterns
+// This lets teams encode org-specific migration blockers without writing new checks
+type ApplicationBlacklistCheck struct {
+	patterns []*regexp.Regexp
+	severity Severity
+}
+
+// NewApplicationBlacklistCheck creates a new ApplicationBlacklistCheck
+// patterns: regular expressions matched against installed application names
+// severity: severity reported for matches, SeverityError fails validation
+// and SeverityWarning only warns (defaults to SeverityError if empty)
+func NewApplicationBlacklistCheck(patterns []string, severity Severity) (*ApplicationBlacklistCheck, error) {
+	if severity == "" {
+		severity = SeverityError
+	}
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid application pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &ApplicationBlacklistCheck{
+		patterns: compiled,
+		severity: severity,
+	}, nil
+}
+
+// ID returns the stable identifier of the check
+func (c *ApplicationBlacklistCheck) ID() string {
+	return "application-blacklist"
+}
+
+// Category returns the category of the check
+func (c *ApplicationBlacklistCheck) Category() string {
+	return CategoryApplications
+}
+
+// Tags returns the tags of the check
+func (c *ApplicationBlacklistCheck) Tags() []string {
+	return []string{"policy"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *ApplicationBlacklistCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *ApplicationBlacklistCheck) Name() string {
+	return "Application Blacklist"
+}
+
+// Describe returns the metadata of the check
+func (c *ApplicationBlacklistCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Fails or warns when installed applications match caller-supplied patterns. This lets teams encode org-specific migration blockers without writing new checks.")
+}
+
+// Run inspects the VM snapshot and reports blacklisted applications
+func (c *ApplicationBlacklistCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate matches installed applications against the blacklist patterns
+func (c *ApplicationBlacklistCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var details []string
+	for _, guest := range data.Operatingsystems {
+		for _, app := range guest.Applications.Application {
+			for _, re := range c.patterns {
+				if re.MatchString(app.Name) {
+					details = append(details, fmt.Sprintf("%s %s matches %s", app.Name, app.Version, re.String()))
+					break
+				}
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     c.severity != SeverityError,
+			Severity:  c.severity,
+			Details:   details,
+		}.withMessage("application-blacklist.found", "count", len(details))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("application-blacklist.none")
+}
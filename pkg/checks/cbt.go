@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// CBTCheck reports whether Changed Block Tracking is enabled for the VM and each disk
+// Warm migration copies only changed blocks and is impossible without CBT
+type CBTCheck struct{}
+
+// NewCBTCheck creates a new CBTCheck
+func NewCBTCheck() *CBTCheck {
+	return &CBTCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *CBTCheck) ID() string {
+	return "vsphere-cbt"
+}
+
+// Category returns the category of the check
+func (c *CBTCheck) Category() string {
+	return CategoryPlatform
+}
+
+// Tags returns the tags of the check
+func (c *CBTCheck) Tags() []string {
+	return []string{"vsphere-config", "storage", "warm-migration"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *CBTCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *CBTCheck) Name() string {
+	return "Changed Block Tracking"
+}
+
+// Describe returns the metadata of the check
+func (c *CBTCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Reports whether Changed Block Tracking is enabled for the VM and each disk. Warm migration copies only changed blocks and is impossible without CBT.")
+}
+
+// Run validates CBT settings from the vSphere configuration
+func (c *CBTCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config), nil
+}
+
+// evaluate reports the VM and per-disk CBT state
+func (c *CBTCheck) evaluate(config *types.VMConfig) CheckResult {
+	details := []string{fmt.Sprintf("VM: CBT %s", enabledString(config.CBTEnabled))}
+	disabled := 0
+	for _, disk := range config.Disks {
+		details = append(details, fmt.Sprintf("%s (%s): CBT %s", disk.Label, disk.FileName, enabledString(disk.CBTEnabled)))
+		if !disk.CBTEnabled {
+			disabled++
+		}
+	}
+
+	if !config.CBTEnabled || disabled > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("vsphere-cbt.disabled")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("vsphere-cbt.enabled")
+}
+
+// enabledString formats a boolean setting for result details
+func enabledString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,287 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/persistent"
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// Check defines a single pre-migration validation
+type Check interface {
+	// ID returns a stable identifier of the check (e.g., "fstab-by-path")
+	ID() string
+
+	// Name returns a human readable name of the check
+	Name() string
+
+	// Category returns the area of the VM the check validates (e.g., CategoryStorage)
+	Category() string
+
+	// Tags returns free-form labels used to select checks (e.g., "linux", "windows", "vsphere-config")
+	Tags() []string
+
+	// Sources returns the inspection sources the check needs
+	Sources() []Source
+
+	// Describe returns the metadata of the check, used to document checks and render them in UIs
+	Describe() Metadata
+
+	// Run executes the check against the VM described by params
+	// An error means the check could not be performed (e.g., inspection timeout, vCenter
+	// authentication failure) and is distinct from a result reporting a failed validation
+	Run(ctx context.Context, params InspectionParams) (CheckResult, error)
+}
+
+// Check categories
+const (
+	CategoryStorage      = "storage"
+	CategoryNetwork      = "network"
+	CategoryBoot         = "boot"
+	CategoryGuestOS      = "guest-os"
+	CategoryApplications = "applications"
+	CategoryPlatform     = "platform" // vSphere VM configuration and target platform limits
+	CategoryPolicy       = "policy"   // User-supplied policies
+)
+
+// Source identifies a source of inspection data used by checks
+type Source string
+
+const (
+	// SourceVirtInspector is the virt-inspector data of the snapshot (types.VirtInspectorXML)
+	SourceVirtInspector Source = "virt-inspector"
+	// SourceVirtV2vInspector is the virt-v2v-inspector data of the snapshot (types.VirtV2VInspectorXML)
+	SourceVirtV2vInspector Source = "virt-v2v-inspector"
+	// SourceGuestFilesystem is guest file content and metadata read with guestfish
+	SourceGuestFilesystem Source = "guest-filesystem"
+	// SourceVSphereConfig is the vSphere VM configuration provided in InspectionParams.VMConfig
+	SourceVSphereConfig Source = "vsphere-config"
+)
+
+// Guest OS families, matching the OS names reported by virt-inspector
+const (
+	OSFamilyLinux   = "linux"
+	OSFamilyWindows = "windows"
+)
+
+// Severity indicates how serious a check result is
+type Severity string
+
+const (
+	// SeverityInfo is used for passing results that need no action
+	SeverityInfo Severity = "info"
+	// SeverityWarning is used for findings that should be reviewed but do not block migration
+	SeverityWarning Severity = "warning"
+	// SeverityError is used for findings that block migration
+	SeverityError Severity = "error"
+)
+
+// CheckResult holds the outcome of a single check
+type CheckResult struct {
+	CheckID     string              `json:"check_id,omitempty"` // Set by CheckRunner from the check's ID
+	CheckName   string              `json:"check_name"`
+	Valid       bool                `json:"valid"`
+	Severity    Severity            `json:"severity"`
+	Message     string              `json:"message"`
+	MessageID   string              `json:"message_id,omitempty"`   // Catalog ID of Message, for rendering it in other languages
+	MessageArgs map[string]string   `json:"message_args,omitempty"` // Named arguments of Message
+	Details     []string            `json:"details,omitempty"`
+	Error       string              `json:"error,omitempty"`       // Set by CheckRunner when the check could not be performed
+	Suppression *AppliedSuppression `json:"suppression,omitempty"` // Set by CheckRunner when a suppression matched the finding
+
+	StartedAt time.Time     `json:"started_at"` // Set by CheckRunner
+	Duration  time.Duration `json:"duration"`   // Set by CheckRunner, excluding inspections performed before the checks run
+}
+
+// Metadata describes a check independently of any VM
+type Metadata struct {
+	ID              string   `json:"id"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Category        string   `json:"category"`
+	Tags            []string `json:"tags,omitempty"`
+	DefaultSeverity Severity `json:"default_severity"` // Severity of the findings the check fails on
+	Sources         []Source `json:"sources"`
+	OSFamilies      []string `json:"os_families,omitempty"` // Empty if the check applies to any guest OS
+}
+
+// describe builds the metadata of a check from its identification methods
+func describe(c Check, severity Severity, description string, osFamilies ...string) Metadata {
+	return Metadata{
+		ID:              c.ID(),
+		Title:           c.Name(),
+		Description:     description,
+		Category:        c.Category(),
+		Tags:            c.Tags(),
+		DefaultSeverity: severity,
+		Sources:         c.Sources(),
+		OSFamilies:      osFamilies,
+	}
+}
+
+// InspectionParams contains everything a check needs to inspect a VM snapshot
+type InspectionParams struct {
+	VMName               string
+	SnapshotName         string
+	Datacenter           string
+	DiskInfo             *types.SnapshotDiskInfo
+	VMConfig             *types.VMConfig // vSphere VM configuration, required by checks using vSphere config
+	SSLVerify            string          // SSL verification option for vpx:// URL (e.g., "no_verify=1")
+	Credentials          persistent.Credentials
+	SessionOptions       *persistent.SessionOptions   // nbdkit filters of the NBD sessions to the snapshot disks, defaults if nil
+	InspectorOptions     *persistent.InspectorOptions // Command line options of the inspection tools, defaults if nil
+	VirtInspectorPath    string                       // Uses system PATH if empty
+	VirtV2vInspectorPath string                       // Uses system PATH if empty
+	Timeout              time.Duration                // Defaults to 5 minutes if zero
+	Logger               *logrus.Logger
+	DB                   persistent.DB // Can be nil for memory-only caching
+
+	// Inspector is shared by all checks, and can be shared between VMs, so its caches and inflight
+	// inspection tracking are reused; if nil, a CheckRunner creates a persistent inspector from the fields
+	// above for its run. Required to run a check on its own
+	// Tests can set a fake.Inspector returning canned inspection results
+	Inspector Inspector
+
+	shared *sharedInspection // Set by CheckRunner to share one inspection between checks
+}
+
+// newInspector returns the inspector provided by the caller, or creates a persistent inspector from the
+// inspection parameters, for the checks of a CheckRunner run
+func (p InspectionParams) newInspector() Inspector {
+	if p.Inspector != nil {
+		return p.Inspector
+	}
+	return persistent.NewInspector(p.VirtInspectorPath, p.VirtV2vInspectorPath, p.Timeout, p.Credentials, p.SessionOptions, p.InspectorOptions, p.Logger, p.DB)
+}
+
+// snapshotInspector returns the inspector of the VM snapshot: shared by a CheckRunner, or provided by the caller
+// A check run on its own needs Inspector, so all its inspections share one cache and NBD session
+func (p InspectionParams) snapshotInspector() (Inspector, error) {
+	if p.DiskInfo == nil {
+		return nil, fmt.Errorf("snapshot disk info is required")
+	}
+	if p.shared != nil {
+		return p.shared.inspector, nil
+	}
+	if p.Inspector == nil {
+		return nil, fmt.Errorf("an Inspector is required to run a check outside a CheckRunner")
+	}
+	return p.Inspector, nil
+}
+
+// inspectWithVirt runs virt-inspector for the VM snapshot described by params
+func (p InspectionParams) inspectWithVirt(ctx context.Context) (*types.VirtInspectorXML, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	if p.shared != nil {
+		return p.shared.inspectWithVirt(ctx, p)
+	}
+	return inspector.InspectWithVirt(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo)
+}
+
+// inspectWithVirtV2v runs virt-v2v-inspector for the VM snapshot described by params
+func (p InspectionParams) inspectWithVirtV2v(ctx context.Context) (*types.VirtV2VInspectorXML, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	if p.shared != nil {
+		return p.shared.inspectWithVirtV2v(ctx, p)
+	}
+	return inspector.InspectWithVirtV2v(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo, p.SSLVerify)
+}
+
+// readGuestFiles reads files or directories from the guest filesystems of the VM snapshot
+func (p InspectionParams) readGuestFiles(ctx context.Context, paths ...string) (map[string][]byte, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	return inspector.ReadGuestFiles(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo, paths)
+}
+
+// listGuestDirectory lists the entry names of a directory in the guest filesystems of the VM snapshot
+func (p InspectionParams) listGuestDirectory(ctx context.Context, dir string) ([]string, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	return inspector.ListGuestDirectory(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo, dir)
+}
+
+// pathsExist reports whether the given paths exist in the guest filesystems of the VM snapshot
+func (p InspectionParams) pathsExist(ctx context.Context, paths ...string) (map[string]bool, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	return inspector.PathsExist(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo, paths)
+}
+
+// listInitramfsFiles lists the contents of the initramfs images of the VM snapshot
+func (p InspectionParams) listInitramfsFiles(ctx context.Context) (map[string][]string, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	return inspector.ListInitramfsFiles(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo)
+}
+
+// filesystemUsage collects usage statistics for guest mount points of the VM snapshot
+func (p InspectionParams) filesystemUsage(ctx context.Context, mountPoints ...string) (map[string]types.FilesystemUsage, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	return inspector.FilesystemUsage(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo, mountPoints)
+}
+
+// partitionTables reports the partition table type of every disk of the VM snapshot
+func (p InspectionParams) partitionTables(ctx context.Context) ([]types.PartitionTable, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	return inspector.PartitionTables(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo)
+}
+
+// enabledServices lists the systemd units enabled in the guest of the VM snapshot
+func (p InspectionParams) enabledServices(ctx context.Context) ([]types.EnabledService, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	return inspector.ListEnabledServices(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo)
+}
+
+// lvmLayout reports the LVM physical and logical volumes of the VM snapshot
+func (p InspectionParams) lvmLayout(ctx context.Context) (*types.LVMLayout, error) {
+	inspector, err := p.snapshotInspector()
+	if err != nil {
+		return nil, err
+	}
+	return inspector.LVMLayout(ctx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo)
+}
+
+// vmConfig returns the vSphere VM configuration or an error if it was not provided
+func (p InspectionParams) vmConfig() (*types.VMConfig, error) {
+	if p.VMConfig == nil {
+		return nil, fmt.Errorf("vSphere VM configuration is required")
+	}
+	return p.VMConfig, nil
+}
+
+// checkFailed builds a result for a check that could not be performed
+func checkFailed(checkName string, err error) CheckResult {
+	return CheckResult{
+		CheckName: checkName,
+		Valid:     false,
+		Severity:  SeverityError,
+		Error:     err.Error(),
+	}.withMessage("check.failed")
+}
@@ -0,0 +1,193 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// cloudInitConfigPaths lists cloud-init configuration read from the guest
+var cloudInitConfigPaths = []string{
+	"/etc/cloud/cloud.cfg",
+	"/etc/cloud/cloud.cfg.d",
+}
+
+// vmwareDatasources lists cloud-init datasources specific to VMware
+var vmwareDatasources = []string{"VMware", "VMwareGuestInfo", "OVF"}
+
+// CloudInitCheck detects cloud-init in the guest and reports its configured datasources
+// VMware datasources may reset networking or hostname on first boot in the target
+type CloudInitCheck struct{}
+
+// NewCloudInitCheck creates a new CloudInitCheck
+func NewCloudInitCheck() *CloudInitCheck {
+	return &CloudInitCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *CloudInitCheck) ID() string {
+	return "cloud-init-datasources"
+}
+
+// Category returns the category of the check
+func (c *CloudInitCheck) Category() string {
+	return CategoryNetwork
+}
+
+// Tags returns the tags of the check
+func (c *CloudInitCheck) Tags() []string {
+	return []string{"linux", "cloud-init"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *CloudInitCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *CloudInitCheck) Name() string {
+	return "Cloud-init Datasources"
+}
+
+// Describe returns the metadata of the check
+func (c *CloudInitCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects cloud-init in the guest and reports its configured datasources. VMware datasources may reset networking or hostname on first boot in the target.", OSFamilyLinux)
+}
+
+// Run inspects installed packages and cloud-init configuration of the guest
+func (c *CloudInitCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	files, err := params.readGuestFiles(ctx, cloudInitConfigPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data, files), nil
+}
+
+// evaluate reports cloud-init presence and VMware datasources
+func (c *CloudInitCheck) evaluate(data *types.VirtInspectorXML, files map[string][]byte) CheckResult {
+	installed := false
+	for _, guest := range data.Operatingsystems {
+		if findApplication(guest, []string{"cloud-init"}) != "" {
+			installed = true
+		}
+	}
+	if !installed && len(files) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("cloud-init-datasources.not-installed")
+	}
+
+	// Files in cloud.cfg.d override cloud.cfg and each other in lexical order
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		if p == "/etc/cloud/cloud.cfg" || strings.HasSuffix(p, ".cfg") {
+			paths = append(paths, p)
+		}
+	}
+	sort.Slice(paths, func(a, b int) bool {
+		if paths[a] == "/etc/cloud/cloud.cfg" {
+			return paths[b] != "/etc/cloud/cloud.cfg"
+		}
+		if paths[b] == "/etc/cloud/cloud.cfg" {
+			return false
+		}
+		return paths[a] < paths[b]
+	})
+
+	var datasources []string
+	source := ""
+	for _, p := range paths {
+		if list, found := parseDatasourceList(files[p]); found {
+			datasources = list
+			source = p
+		}
+	}
+
+	if len(datasources) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("cloud-init-datasources.default-datasources")
+	}
+
+	details := []string{fmt.Sprintf("datasource_list from %s: %s", source, strings.Join(datasources, ", "))}
+	var vmware []string
+	for _, ds := range datasources {
+		for _, vmwareDS := range vmwareDatasources {
+			if strings.EqualFold(ds, vmwareDS) {
+				vmware = append(vmware, ds)
+			}
+		}
+	}
+
+	if len(vmware) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("cloud-init-datasources.vmware-datasources", "datasources", strings.Join(vmware, ", "))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("cloud-init-datasources.no-vmware-datasources")
+}
+
+// parseDatasourceList extracts datasource_list from cloud-init YAML configuration
+// Both the inline form ("datasource_list: [ VMware, None ]") and the block list form are supported
+func parseDatasourceList(content []byte) ([]string, bool) {
+	var datasources []string
+	found := false
+	inBlock := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if inBlock {
+			if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+				datasources = append(datasources, strings.Trim(strings.TrimSpace(item), `"'`))
+				continue
+			}
+			inBlock = false
+		}
+
+		value, ok := strings.CutPrefix(line, "datasource_list:")
+		if !ok {
+			continue
+		}
+		found = true
+		datasources = nil
+		value = strings.TrimSpace(value)
+		if value == "" {
+			inBlock = true
+			continue
+		}
+		for _, item := range strings.Split(strings.Trim(value, "[]"), ",") {
+			if item = strings.Trim(strings.TrimSpace(item), `"'`); item != "" {
+				datasources = append(datasources, item)
+			}
+		}
+	}
+	return datasources, found
+}
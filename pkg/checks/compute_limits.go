@@ -0,0 +1,134 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// ComputeLimits describes CPU and memory limits of the target cluster
+// Zero values mean no limit
+type ComputeLimits struct {
+	MaxCPUsPerVM        int    `json:"max_cpus_per_vm,omitempty"`         // Maximum vCPUs allowed per VM
+	MaxMemoryBytesPerVM uint64 `json:"max_memory_bytes_per_vm,omitempty"` // Maximum memory allowed per VM
+	NodeCPUs            int    `json:"node_cpus,omitempty"`               // Allocatable CPUs of the largest node
+	NodeMemoryBytes     uint64 `json:"node_memory_bytes,omitempty"`       // Allocatable memory of the largest node
+}
+
+// ComputeLimitsCheck compares the VM's vCPU count and memory against target cluster limits
+// It also reports CPU/memory hot-add and NUMA settings that are not carried over
+type ComputeLimitsCheck struct {
+	limits ComputeLimits
+}
+
+// NewComputeLimitsCheck creates a new ComputeLimitsCheck
+func NewComputeLimitsCheck(limits ComputeLimits) *ComputeLimitsCheck {
+	return &ComputeLimitsCheck{
+		limits: limits,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *ComputeLimitsCheck) ID() string {
+	return "compute-limits"
+}
+
+// Category returns the category of the check
+func (c *ComputeLimitsCheck) Category() string {
+	return CategoryPlatform
+}
+
+// Tags returns the tags of the check
+func (c *ComputeLimitsCheck) Tags() []string {
+	return []string{"vsphere-config", "compute"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *ComputeLimitsCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *ComputeLimitsCheck) Name() string {
+	return "CPU and Memory Limits"
+}
+
+// Describe returns the metadata of the check
+func (c *ComputeLimitsCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Compares the VM's vCPU count and memory against target cluster limits. It also reports CPU/memory hot-add and NUMA settings that are not carried over.")
+}
+
+// Run validates CPU and memory from the vSphere configuration
+func (c *ComputeLimitsCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config), nil
+}
+
+// evaluate compares CPU and memory against the limits and reports settings lost in migration
+func (c *ComputeLimitsCheck) evaluate(config *types.VMConfig) CheckResult {
+	var memory uint64
+	if config.MemoryBytes > 0 {
+		memory = uint64(config.MemoryBytes)
+	}
+
+	var problems []string
+	if c.limits.MaxCPUsPerVM > 0 && config.NumCPUs > c.limits.MaxCPUsPerVM {
+		problems = append(problems, fmt.Sprintf("VM has %d vCPUs, the target allows at most %d per VM", config.NumCPUs, c.limits.MaxCPUsPerVM))
+	}
+	if c.limits.MaxMemoryBytesPerVM > 0 && memory > c.limits.MaxMemoryBytesPerVM {
+		problems = append(problems, fmt.Sprintf("VM has %s of memory, the target allows at most %s per VM", formatBytes(memory), formatBytes(c.limits.MaxMemoryBytesPerVM)))
+	}
+	if c.limits.NodeCPUs > 0 && config.NumCPUs > c.limits.NodeCPUs {
+		problems = append(problems, fmt.Sprintf("VM has %d vCPUs, no node has more than %d allocatable", config.NumCPUs, c.limits.NodeCPUs))
+	}
+	if c.limits.NodeMemoryBytes > 0 && memory > c.limits.NodeMemoryBytes {
+		problems = append(problems, fmt.Sprintf("VM has %s of memory, no node has more than %s allocatable", formatBytes(memory), formatBytes(c.limits.NodeMemoryBytes)))
+	}
+
+	details := []string{fmt.Sprintf("%d vCPUs, %s of memory", config.NumCPUs, formatBytes(memory))}
+	var lost []string
+	if config.CPUHotAddEnabled {
+		lost = append(lost, "CPU hot-add is enabled and will not be carried over")
+	}
+	if config.MemoryHotAddEnabled {
+		lost = append(lost, "memory hot-add is enabled and will not be carried over")
+	}
+	keys := make([]string, 0, len(config.NUMASettings))
+	for key := range config.NUMASettings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lost = append(lost, fmt.Sprintf("NUMA setting %s = %s will not be carried over", key, config.NUMASettings[key]))
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   append(append(problems, details...), lost...),
+		}.withMessage("compute-limits.exceeded")
+	}
+
+	if len(lost) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(details, lost...),
+		}.withMessage("compute-limits.settings-lost")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("compute-limits.within-limits")
+}
@@ -0,0 +1,264 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config selects and configures checks by ID so validation policy can be standardized without code changes
+//
+// Configuration files are YAML documents (JSON is also accepted), e.g.:
+//
+//	disabled: [hostname]
+//	timeout: 10m
+//	fail_on: [error]
+//	checks:
+//	  eol-os:
+//	    severity: error
+//	  kernel-inventory:
+//	    timeout: 20m
+//	  disk-limits:
+//	    params:
+//	      max_disk_bytes: 4398046511104
+type Config struct {
+	Enabled  []string               `json:"enabled,omitempty"`  // IDs of checks to run, all registered checks if empty
+	Disabled []string               `json:"disabled,omitempty"` // IDs of checks not to run
+	Checks   map[string]CheckConfig `json:"checks,omitempty"`   // Per-check settings keyed by check ID
+	Timeout  string                 `json:"timeout,omitempty"`  // Default timeout of each check (e.g., "10m"), none if empty
+	FailOn   []Severity             `json:"fail_on,omitempty"`  // Severities failing the validation, DefaultFailOn if empty
+}
+
+// CheckConfig holds the settings of a single check
+type CheckConfig struct {
+	Severity Severity        `json:"severity,omitempty"` // Overrides the severity of findings (non-info results)
+	Params   json.RawMessage `json:"params,omitempty"`   // Check specific parameters
+	Timeout  string          `json:"timeout,omitempty"`  // Overrides the default check timeout (e.g., "20m")
+}
+
+// Parameters of configurable built-in checks
+type (
+	supportedOSParams struct {
+		Matrix []OSVersionRange `json:"matrix,omitempty"`
+	}
+	eolParams struct {
+		Table []EOLEntry `json:"table,omitempty"`
+	}
+	virtioKernelParams struct {
+		MinVersion string `json:"min_version,omitempty"`
+	}
+	filesystemTypeParams struct {
+		Allowed  []string `json:"allowed,omitempty"`
+		Severity Severity `json:"severity,omitempty"`
+	}
+	freeSpaceParams struct {
+		MinRootFreeBytes uint64 `json:"min_root_free_bytes,omitempty"`
+		MinBootFreeBytes uint64 `json:"min_boot_free_bytes,omitempty"`
+	}
+	antivirusParams struct {
+		Products []string `json:"products,omitempty"`
+	}
+	applicationBlacklistParams struct {
+		Patterns []string `json:"patterns,omitempty"`
+		Severity Severity `json:"severity,omitempty"`
+	}
+	hostnameParams struct {
+		CheckVMName bool `json:"check_vm_name,omitempty"`
+	}
+	guestAgentsParams struct {
+		Agents []GuestAgent `json:"agents,omitempty"`
+	}
+	kernelInventoryParams struct {
+		MinBootFreeBytes uint64 `json:"min_boot_free_bytes,omitempty"`
+	}
+	snapshotChainParams struct {
+		MaxDepth int    `json:"max_depth,omitempty"`
+		MaxBytes uint64 `json:"max_bytes,omitempty"`
+	}
+	vmNameParams struct {
+		ExistingNames []string `json:"existing_names,omitempty"`
+	}
+)
+
+// LoadConfig reads a YAML or JSON check configuration
+// Unknown and duplicate keys are rejected
+func LoadConfig(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read check configuration: %w", err)
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse check configuration: %w", err)
+	}
+	return &config, nil
+}
+
+// LoadConfigFile reads a check configuration from a .yaml, .yml or .json file
+func LoadConfigFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open check configuration: %w", err)
+	}
+	defer f.Close()
+	return LoadConfig(f)
+}
+
+// NewChecks creates the checks selected by the configuration in registration order
+// Returns an error for unknown check IDs, invalid severities, timeouts and parameters
+func (c *Config) NewChecks() ([]Check, error) {
+	defaultTimeout, err := parseTimeout(c.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range slices.Concat(c.Enabled, c.Disabled) {
+		if _, found := Lookup(id); !found {
+			return nil, fmt.Errorf("unknown check %q", id)
+		}
+	}
+	for id := range c.Checks {
+		if _, found := Lookup(id); !found {
+			return nil, fmt.Errorf("unknown check %q", id)
+		}
+	}
+
+	var checks []Check
+	for _, reg := range Registrations() {
+		if len(c.Enabled) > 0 && !slices.Contains(c.Enabled, reg.ID) {
+			continue
+		}
+		if slices.Contains(c.Disabled, reg.ID) {
+			continue
+		}
+
+		settings := c.Checks[reg.ID]
+		check, err := newConfiguredCheck(reg, settings.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		switch settings.Severity {
+		case "":
+		case SeverityInfo, SeverityWarning, SeverityError:
+			check = &severityOverride{Check: check, severity: settings.Severity}
+		default:
+			return nil, fmt.Errorf("check %q: invalid severity %q", reg.ID, settings.Severity)
+		}
+
+		timeout := defaultTimeout
+		if settings.Timeout != "" {
+			if timeout, err = parseTimeout(settings.Timeout); err != nil {
+				return nil, fmt.Errorf("check %q: %w", reg.ID, err)
+			}
+		}
+		if timeout > 0 {
+			check = &timeoutOverride{Check: check, timeout: timeout}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// NewRunner creates a CheckRunner for the checks selected by the configuration
+func (c *Config) NewRunner() (*CheckRunner, error) {
+	for _, severity := range c.FailOn {
+		switch severity {
+		case SeverityInfo, SeverityWarning, SeverityError:
+		default:
+			return nil, fmt.Errorf("invalid fail_on severity %q", severity)
+		}
+	}
+	checks, err := c.NewChecks()
+	if err != nil {
+		return nil, err
+	}
+	return NewCheckRunner(checks).WithFailOn(c.FailOn...), nil
+}
+
+// newConfiguredCheck creates a check from its registration and configured parameters
+func newConfiguredCheck(reg Registration, params json.RawMessage) (Check, error) {
+	if len(params) == 0 {
+		check, err := reg.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create check %q: %w", reg.ID, err)
+		}
+		return check, nil
+	}
+	if reg.Configure == nil {
+		return nil, fmt.Errorf("check %q has no parameters", reg.ID)
+	}
+	check, err := reg.Configure(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure check %q: %w", reg.ID, err)
+	}
+	return check, nil
+}
+
+// parseTimeout parses a configured timeout, zero if empty
+func parseTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid timeout %q", value)
+	}
+	return timeout, nil
+}
+
+// decodeParams decodes check parameters, rejecting unknown fields
+func decodeParams(raw json.RawMessage, params any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(params); err != nil {
+		return fmt.Errorf("invalid parameters: %w", err)
+	}
+	return nil
+}
+
+// severityOverride reports the findings of a check with a configured severity
+type severityOverride struct {
+	Check
+	severity Severity
+}
+
+// Describe returns the metadata of the wrapped check with the configured severity
+func (c *severityOverride) Describe() Metadata {
+	metadata := c.Check.Describe()
+	metadata.DefaultSeverity = c.severity
+	return metadata
+}
+
+// Run runs the wrapped check and applies the configured severity to its findings
+func (c *severityOverride) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	result, err := c.Check.Run(ctx, params)
+	if err != nil {
+		return result, err
+	}
+	if result.Severity != SeverityInfo {
+		result.Severity = c.severity
+		result.Valid = c.severity != SeverityError
+	}
+	return result, nil
+}
+
+// timeoutOverride runs a check with a configured timeout
+type timeoutOverride struct {
+	Check
+	timeout time.Duration
+}
+
+// Timeout returns the configured timeout of the check
+func (c *timeoutOverride) Timeout() time.Duration {
+	return c.timeout
+}
@@ -0,0 +1,113 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name: "yaml",
+			input: `# Fleet validation policy
+disabled: [hostname]
+timeout: 10m
+fail_on:
+  - warning
+  - error
+checks:
+  eol-os:
+    severity: error
+  kernel-inventory:
+    timeout: 20m
+  disk-limits:
+    params:
+      max_disk_bytes: 4398046511104
+`,
+		},
+		{
+			name:  "json",
+			input: `{"disabled": ["hostname"], "timeout": "10m", "fail_on": ["warning", "error"], "checks": {"eol-os": {"severity": "error"}, "kernel-inventory": {"timeout": "20m"}, "disk-limits": {"params": {"max_disk_bytes": 4398046511104}}}}`,
+		},
+		{
+			name:    "unknown key",
+			input:   "disable: [hostname]\n",
+			wantErr: "unknown field",
+		},
+		{
+			name:    "duplicate key",
+			input:   "timeout: 10m\ntimeout: 20m\n",
+			wantErr: "already set",
+		},
+		{
+			name:    "invalid yaml",
+			input:   "checks: [\n",
+			wantErr: "failed to parse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadConfig(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if strings.Join(config.Disabled, ",") != "hostname" || config.Timeout != "10m" || len(config.FailOn) != 2 {
+				t.Errorf("LoadConfig() = %+v", config)
+			}
+			if config.Checks["eol-os"].Severity != SeverityError || config.Checks["kernel-inventory"].Timeout != "20m" {
+				t.Errorf("Checks = %+v", config.Checks)
+			}
+
+			checks, err := config.NewChecks()
+			if err != nil {
+				t.Fatalf("NewChecks() error = %v", err)
+			}
+			for _, check := range checks {
+				switch check.ID() {
+				case "hostname":
+					t.Error("disabled check hostname was created")
+				case "kernel-inventory":
+					if timeout := NewCheckRunner(nil).checkTimeout(check); timeout != 20*time.Minute {
+						t.Errorf("kernel-inventory timeout = %v, want 20m", timeout)
+					}
+				case "eol-os":
+					if severity := check.Describe().DefaultSeverity; severity != SeverityError {
+						t.Errorf("eol-os severity = %s, want error", severity)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConfigNewChecksRejectsInvalidSettings(t *testing.T) {
+	tests := map[string]string{
+		"unknown check":      "disabled: [no-such-check]\n",
+		"invalid severity":   "checks:\n  eol-os:\n    severity: fatal\n",
+		"invalid timeout":    "timeout: soon\n",
+		"unknown parameter":  "checks:\n  disk-limits:\n    params:\n      max_disk_count: 4\n",
+		"check without args": "checks:\n  hostname-unknown:\n    params: {}\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, err := LoadConfig(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if _, err := config.NewChecks(); err == nil {
+				t.Error("NewChecks() accepted the configuration")
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CrypttabCheck validates encrypted mappings in /etc/crypttab
+// Mappings referencing by-path or VMware-specific devices, or keys stored on
+// separate devices that are not migrated, leave the guest unbootable after conversion
+type CrypttabCheck struct{}
+
+// NewCrypttabCheck creates a new CrypttabCheck
+func NewCrypttabCheck() *CrypttabCheck {
+	return &CrypttabCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *CrypttabCheck) ID() string {
+	return "crypttab-device-references"
+}
+
+// Category returns the category of the check
+func (c *CrypttabCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *CrypttabCheck) Tags() []string {
+	return []string{"linux", "encryption"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *CrypttabCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *CrypttabCheck) Name() string {
+	return "Crypttab Device References"
+}
+
+// Describe returns the metadata of the check
+func (c *CrypttabCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Validates encrypted mappings in /etc/crypttab. Mappings referencing by-path or VMware-specific devices, or keys stored on separate devices that are not migrated, leave the guest unbootable after conversion.", OSFamilyLinux)
+}
+
+// Run reads the guest /etc/crypttab and reports non-portable device and key references
+func (c *CrypttabCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	files, err := params.readGuestFiles(ctx, "/etc/crypttab")
+	if err != nil {
+		return CheckResult{}, err
+	}
+	crypttab, found := files["/etc/crypttab"]
+	if !found {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("crypttab-device-references.no-crypttab"), nil
+	}
+	return c.evaluate(crypttab), nil
+}
+
+// evaluate checks every crypttab mapping for device and key file references
+func (c *CrypttabCheck) evaluate(crypttab []byte) CheckResult {
+	var details []string
+	mappings := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(crypttab))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		mappings++
+		name, device := fields[0], fields[1]
+
+		if issue := deviceReferenceIssue(device); issue != "" {
+			details = append(details, fmt.Sprintf("/etc/crypttab:%d mapping %s %s", lineNum, name, issue))
+		}
+		if len(fields) >= 3 {
+			if keyDevice := keyFileDevice(fields[2]); keyDevice != "" {
+				details = append(details, fmt.Sprintf("/etc/crypttab:%d mapping %s reads its key from device %s, which is not migrated", lineNum, name, keyDevice))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("crypttab-device-references.non-portable")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("crypttab-device-references.portable", "count", mappings)
+}
+
+// keyFileDevice returns the device holding the key file of a crypttab mapping, or "" if the key is on the guest disks
+// Keys are read from a separate device when the key field is a block device or uses the
+// systemd "keyfile:device" syntax
+func keyFileDevice(keyField string) string {
+	switch keyField {
+	case "none", "-", "":
+		return ""
+	}
+	if strings.HasPrefix(keyField, "/dev/") && !strings.HasPrefix(keyField, "/dev/urandom") && !strings.HasPrefix(keyField, "/dev/random") {
+		return keyField
+	}
+	if idx := strings.Index(keyField, ":"); idx >= 0 {
+		return keyField[idx+1:]
+	}
+	return ""
+}
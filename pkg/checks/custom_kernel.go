@@ -0,0 +1,130 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// thirdPartyKernelPackages lists kernel packages that are not shipped by the distribution (e.g., ELRepo)
+var thirdPartyKernelPackages = []string{
+	"kernel-ml",
+	"kernel-lt",
+}
+
+// CustomKernelCheck flags custom-built or vendor kernels
+// Kernel images in /boot that are not owned by a distribution kernel package may lack
+// virtio support and are not handled by virt-v2v's driver injection
+type CustomKernelCheck struct{}
+
+// NewCustomKernelCheck creates a new CustomKernelCheck
+func NewCustomKernelCheck() *CustomKernelCheck {
+	return &CustomKernelCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *CustomKernelCheck) ID() string {
+	return "custom-kernel"
+}
+
+// Category returns the category of the check
+func (c *CustomKernelCheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *CustomKernelCheck) Tags() []string {
+	return []string{"linux", "kernel"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *CustomKernelCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *CustomKernelCheck) Name() string {
+	return "Custom Kernel"
+}
+
+// Describe returns the metadata of the check
+func (c *CustomKernelCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Flags custom-built or vendor kernels. Kernel images in /boot that are not owned by a distribution kernel package may lack virtio support and are not handled by virt-v2v's driver injection.", OSFamilyLinux)
+}
+
+// Run inspects installed kernel packages and kernel images in /boot
+func (c *CustomKernelCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	bootEntries, err := params.listGuestDirectory(ctx, "/boot")
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data, bootEntries), nil
+}
+
+// evaluate compares kernel images against distribution kernel packages
+func (c *CustomKernelCheck) evaluate(data *types.VirtInspectorXML, bootEntries []string) CheckResult {
+	var details []string
+	linuxFound := false
+
+	for _, guest := range data.Operatingsystems {
+		if guest.Name != "linux" {
+			continue
+		}
+		linuxFound = true
+
+		if pkg := findApplication(guest, thirdPartyKernelPackages); pkg != "" {
+			details = append(details, fmt.Sprintf("third-party kernel package installed: %s", pkg))
+		}
+
+		packaged := installedKernels(guest)
+		for _, entry := range bootEntries {
+			version, ok := strings.CutPrefix(entry, "vmlinuz-")
+			if !ok || strings.Contains(version, "rescue") {
+				continue
+			}
+			if !isPackagedKernel(version, packaged) {
+				details = append(details, fmt.Sprintf("kernel image /boot/%s is not owned by a distribution kernel package", entry))
+			}
+		}
+	}
+
+	if !linuxFound {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("custom-kernel.no-kernels")
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("custom-kernel.custom-kernels")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("custom-kernel.distribution-kernels")
+}
+
+// isPackagedKernel returns true if the kernel image version belongs to one of the packaged kernels
+// Image versions may carry an architecture suffix (e.g., "3.10.0-1160.el7.x86_64")
+func isPackagedKernel(imageVersion string, packaged []string) bool {
+	for _, version := range packaged {
+		if imageVersion == version || strings.HasPrefix(imageVersion, version+".") {
+			return true
+		}
+	}
+	return false
+}
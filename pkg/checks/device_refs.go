@@ -0,0 +1,15 @@
+package checks
+
+import (
+	"strings"
+)
+
+// deviceReferenceIssue returns why a device reference will break after conversion, or "" if it is portable
+// Paths under /dev/disk/by-path encode the VMware controller topology, which does not survive the move to
+// virtio devices
+func deviceReferenceIssue(ref string) string {
+	if strings.Contains(ref, "/dev/disk/by-path/") {
+		return "references a /dev/disk/by-path device tied to the VMware controller topology"
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeviceReferenceIssue(t *testing.T) {
+	tests := []struct {
+		ref       string
+		wantIssue bool
+	}{
+		{"/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0-part1", true},
+		{"resume=/dev/disk/by-path/pci-0000:0b:00.0-sas-0x5000c29-lun-0-part2", true},
+		{"UUID=3f2a9c1e-5b7d-4e8f-9a0b-1c2d3e4f5a6b", false},
+		{"LABEL=root", false},
+		{"/dev/mapper/rhel-root", false},
+		{"/dev/disk/by-uuid/3f2a9c1e-5b7d-4e8f-9a0b-1c2d3e4f5a6b", false},
+		{"/dev/sda1", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if issue := deviceReferenceIssue(tt.ref); (issue != "") != tt.wantIssue {
+			t.Errorf("deviceReferenceIssue(%q) = %q, want issue %v", tt.ref, issue, tt.wantIssue)
+		}
+	}
+}
+
+func TestGrubCheckEvaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string][]byte
+		wantValid   bool
+		wantMessage string
+		wantDetails []string
+	}{
+		{
+			name:        "no configuration",
+			wantValid:   true,
+			wantMessage: "grub-device-references.no-config",
+		},
+		{
+			name: "portable references",
+			files: map[string][]byte{
+				"/etc/default/grub":    []byte("GRUB_CMDLINE_LINUX=\"root=/dev/mapper/rhel-root rd.lvm.lv=rhel/root\"\n"),
+				"/boot/grub2/grub.cfg": []byte("search --no-floppy --fs-uuid --set=root 3f2a9c1e\n"),
+			},
+			wantValid:   true,
+			wantMessage: "grub-device-references.portable",
+		},
+		{
+			name: "by-path references",
+			files: map[string][]byte{
+				"/boot/grub2/device.map": []byte("# this device map was generated by anaconda\n(hd0)      /dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:0:0\n"),
+				"/etc/default/grub":      []byte("\n# resume=/dev/disk/by-path/commented\nGRUB_CMDLINE_LINUX=\"resume=/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0-part2\"\n"),
+			},
+			wantMessage: "grub-device-references.non-portable",
+			wantDetails: []string{
+				"/boot/grub2/device.map:2 references a /dev/disk/by-path device tied to the VMware controller topology: (hd0)      /dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:0:0",
+				"/etc/default/grub:3 references a /dev/disk/by-path device tied to the VMware controller topology: GRUB_CMDLINE_LINUX=\"resume=/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0-part2\"",
+			},
+		},
+		{
+			name: "VMware disk names",
+			files: map[string][]byte{
+				"/boot/grub/device.map": []byte("(hd0) /dev/disk/by-id/scsi-36000c29a1b2c3d4e5f60718293a4b5c6\n"),
+			},
+			wantMessage: "grub-device-references.non-portable",
+			wantDetails: []string{
+				"/boot/grub/device.map:1 references a /dev/disk/by-id device derived from a VMware disk WWN: (hd0) /dev/disk/by-id/scsi-36000c29a1b2c3d4e5f60718293a4b5c6",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewGrubCheck().evaluate(tt.files)
+			if result.Valid != tt.wantValid || result.MessageID != tt.wantMessage {
+				t.Fatalf("evaluate() = %v %s (%s), want %v %s", result.Valid, result.MessageID, result.Message, tt.wantValid, tt.wantMessage)
+			}
+			if tt.wantDetails != nil && strings.Join(result.Details, "\n") != strings.Join(tt.wantDetails, "\n") {
+				t.Errorf("Details = %q, want %q", result.Details, tt.wantDetails)
+			}
+		})
+	}
+}
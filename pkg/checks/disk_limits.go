@@ -0,0 +1,108 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// DiskLimits describes disk limits of the target platform
+// Zero values mean no limit
+type DiskLimits struct {
+	MaxDisks      int    `json:"max_disks,omitempty"`       // Maximum number of disks per VM
+	MaxDiskBytes  uint64 `json:"max_disk_bytes,omitempty"`  // Maximum size of a single disk (e.g., max PVC size)
+	MaxTotalBytes uint64 `json:"max_total_bytes,omitempty"` // Maximum combined size of all disks
+}
+
+// DiskLimitsCheck validates the number and sizes of VM disks against target platform limits
+// Disk capacities come from the vSphere VM configuration
+type DiskLimitsCheck struct {
+	limits DiskLimits
+}
+
+// NewDiskLimitsCheck creates a new DiskLimitsCheck
+func NewDiskLimitsCheck(limits DiskLimits) *DiskLimitsCheck {
+	return &DiskLimitsCheck{
+		limits: limits,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *DiskLimitsCheck) ID() string {
+	return "disk-limits"
+}
+
+// Category returns the category of the check
+func (c *DiskLimitsCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *DiskLimitsCheck) Tags() []string {
+	return []string{"vsphere-config"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *DiskLimitsCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *DiskLimitsCheck) Name() string {
+	return "Disk Count and Size Limits"
+}
+
+// Describe returns the metadata of the check
+func (c *DiskLimitsCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Validates the number and sizes of VM disks against target platform limits. Disk capacities come from the vSphere VM configuration.")
+}
+
+// Run validates the VM disks from the vSphere configuration
+func (c *DiskLimitsCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config.Disks), nil
+}
+
+// evaluate compares disk count and sizes against the limits
+func (c *DiskLimitsCheck) evaluate(disks []types.VirtualDisk) CheckResult {
+	var problems []string
+	var total uint64
+
+	if c.limits.MaxDisks > 0 && len(disks) > c.limits.MaxDisks {
+		problems = append(problems, fmt.Sprintf("VM has %d disks, the target allows at most %d", len(disks), c.limits.MaxDisks))
+	}
+
+	for _, disk := range disks {
+		if disk.CapacityBytes < 0 {
+			continue
+		}
+		size := uint64(disk.CapacityBytes)
+		total += size
+		if c.limits.MaxDiskBytes > 0 && size > c.limits.MaxDiskBytes {
+			problems = append(problems, fmt.Sprintf("%s (%s) is %s, the target allows at most %s per disk", disk.Label, disk.FileName, formatBytes(size), formatBytes(c.limits.MaxDiskBytes)))
+		}
+	}
+
+	if c.limits.MaxTotalBytes > 0 && total > c.limits.MaxTotalBytes {
+		problems = append(problems, fmt.Sprintf("disks total %s, the target allows at most %s", formatBytes(total), formatBytes(c.limits.MaxTotalBytes)))
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   problems,
+		}.withMessage("disk-limits.exceeded")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("disk-limits.within-limits", "count", len(disks), "total", formatBytes(total))
+}
@@ -0,0 +1,116 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// domainControllerPaths lists files and directories present only on Active Directory domain controllers
+// NTFS paths are case sensitive inside libguestfs, so common spellings are listed
+var domainControllerPaths = []string{
+	"/Windows/NTDS/ntds.dit",
+	"/Windows/NTDS/NTDS.DIT",
+	"/WINDOWS/NTDS/ntds.dit",
+	"/Windows/SYSVOL/domain",
+	"/WINDOWS/SYSVOL/domain",
+}
+
+// DomainControllerCheck detects Active Directory Domain Services on Windows guests
+// Migrating a domain controller from a snapshot risks USN rollback in the directory
+type DomainControllerCheck struct{}
+
+// NewDomainControllerCheck creates a new DomainControllerCheck
+func NewDomainControllerCheck() *DomainControllerCheck {
+	return &DomainControllerCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *DomainControllerCheck) ID() string {
+	return "domain-controller"
+}
+
+// Category returns the category of the check
+func (c *DomainControllerCheck) Category() string {
+	return CategoryApplications
+}
+
+// Tags returns the tags of the check
+func (c *DomainControllerCheck) Tags() []string {
+	return []string{"windows"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *DomainControllerCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *DomainControllerCheck) Name() string {
+	return "Active Directory Domain Controller"
+}
+
+// Describe returns the metadata of the check
+func (c *DomainControllerCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects Active Directory Domain Services on Windows guests. Migrating a domain controller from a snapshot risks USN rollback in the directory.", OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and looks for the AD DS database and SYSVOL share on Windows guests
+func (c *DomainControllerCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	if !hasWindowsGuest(data) {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("domain-controller.not-windows"), nil
+	}
+
+	exists, err := params.pathsExist(ctx, domainControllerPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(exists), nil
+}
+
+// evaluate reports the domain controller artifacts found in the guest
+func (c *DomainControllerCheck) evaluate(exists map[string]bool) CheckResult {
+	var details []string
+	for path, found := range exists {
+		if found {
+			details = append(details, fmt.Sprintf("found %s", path))
+		}
+	}
+	sort.Strings(details)
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("domain-controller.domain-controller")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("domain-controller.not-domain-controller")
+}
+
+// hasWindowsGuest returns true if any detected operating system is Windows
+func hasWindowsGuest(data *types.VirtInspectorXML) bool {
+	for _, guest := range data.Operatingsystems {
+		if strings.EqualFold(guest.Name, "windows") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// ldmDevicePrefix is the device-mapper prefix libguestfs uses for Windows LDM (dynamic disk) volumes
+const ldmDevicePrefix = "/dev/mapper/ldm_"
+
+// DynamicDisksCheck detects Windows dynamic disks (LDM volumes)
+// virt-v2v cannot reliably convert guests using dynamic disks
+type DynamicDisksCheck struct{}
+
+// NewDynamicDisksCheck creates a new DynamicDisksCheck
+func NewDynamicDisksCheck() *DynamicDisksCheck {
+	return &DynamicDisksCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *DynamicDisksCheck) ID() string {
+	return "windows-dynamic-disks"
+}
+
+// Category returns the category of the check
+func (c *DynamicDisksCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *DynamicDisksCheck) Tags() []string {
+	return []string{"windows"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *DynamicDisksCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *DynamicDisksCheck) Name() string {
+	return "Windows Dynamic Disks"
+}
+
+// Describe returns the metadata of the check
+func (c *DynamicDisksCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Detects Windows dynamic disks (LDM volumes). virt-v2v cannot reliably convert guests using dynamic disks.", OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and reports LDM volumes
+func (c *DynamicDisksCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks Windows guests for filesystems on LDM volumes
+func (c *DynamicDisksCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var details []string
+
+	for _, guest := range data.Operatingsystems {
+		if guest.Name != "windows" {
+			continue
+		}
+		driveLetters := make(map[string]string)
+		for _, drive := range guest.Drives.Drive {
+			driveLetters[strings.TrimSpace(drive.Device)] = drive.Name
+		}
+		for _, fs := range guest.Filesystems.Filesystem {
+			if !strings.HasPrefix(fs.Device, ldmDevicePrefix) {
+				continue
+			}
+			if letter, ok := driveLetters[fs.Device]; ok {
+				details = append(details, fmt.Sprintf("dynamic volume %s (drive %s:)", fs.Device, letter))
+			} else {
+				details = append(details, fmt.Sprintf("dynamic volume %s", fs.Device))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("windows-dynamic-disks.found")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("windows-dynamic-disks.none")
+}
@@ -0,0 +1,161 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// EOLEntry describes the end-of-life date of a guest OS version
+type EOLEntry struct {
+	Distro  string    `json:"distro"`   // Distro as reported by virt-inspector (e.g., "rhel", "windows")
+	Version string    `json:"version"`  // Major version ("7") or major.minor version ("6.1")
+	EOLDate time.Time `json:"eol_date"` // Date after which the version is no longer supported by its vendor
+}
+
+// DefaultEOLTable lists end-of-life dates for common guest operating systems
+var DefaultEOLTable = []EOLEntry{
+	{Distro: "rhel", Version: "5", EOLDate: eolDate(2017, time.March, 31)},
+	{Distro: "rhel", Version: "6", EOLDate: eolDate(2020, time.November, 30)},
+	{Distro: "rhel", Version: "7", EOLDate: eolDate(2024, time.June, 30)},
+	{Distro: "centos", Version: "6", EOLDate: eolDate(2020, time.November, 30)},
+	{Distro: "centos", Version: "7", EOLDate: eolDate(2024, time.June, 30)},
+	{Distro: "centos", Version: "8", EOLDate: eolDate(2021, time.December, 31)},
+	{Distro: "oraclelinux", Version: "6", EOLDate: eolDate(2021, time.March, 1)},
+	{Distro: "sles", Version: "11", EOLDate: eolDate(2019, time.March, 31)},
+	{Distro: "sles", Version: "12", EOLDate: eolDate(2024, time.October, 31)},
+	{Distro: "ubuntu", Version: "14", EOLDate: eolDate(2019, time.April, 30)},
+	{Distro: "ubuntu", Version: "16", EOLDate: eolDate(2021, time.April, 30)},
+	{Distro: "ubuntu", Version: "18", EOLDate: eolDate(2023, time.May, 31)},
+	{Distro: "debian", Version: "9", EOLDate: eolDate(2022, time.June, 30)},
+	{Distro: "debian", Version: "10", EOLDate: eolDate(2024, time.June, 30)},
+	{Distro: "windows", Version: "5.2", EOLDate: eolDate(2015, time.July, 14)},    // Windows Server 2003
+	{Distro: "windows", Version: "6.0", EOLDate: eolDate(2020, time.January, 14)}, // Windows Server 2008
+	{Distro: "windows", Version: "6.1", EOLDate: eolDate(2020, time.January, 14)}, // Windows Server 2008 R2
+	{Distro: "windows", Version: "6.2", EOLDate: eolDate(2023, time.October, 10)}, // Windows Server 2012
+	{Distro: "windows", Version: "6.3", EOLDate: eolDate(2023, time.October, 10)}, // Windows Server 2012 R2
+}
+
+// EOLCheck warns about guest operating systems that reached end of life
+// EOL guests are reported as warnings and do not fail validation
+type EOLCheck struct {
+	table []EOLEntry
+}
+
+// NewEOLCheck creates a new EOLCheck
+// table: end-of-life dates (uses DefaultEOLTable if nil)
+func NewEOLCheck(table []EOLEntry) *EOLCheck {
+	if table == nil {
+		table = DefaultEOLTable
+	}
+	return &EOLCheck{
+		table: table,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *EOLCheck) ID() string {
+	return "eol-os"
+}
+
+// Category returns the category of the check
+func (c *EOLCheck) Category() string {
+	return CategoryGuestOS
+}
+
+// Tags returns the tags of the check
+func (c *EOLCheck) Tags() []string {
+	return []string{"windows", "linux"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *EOLCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *EOLCheck) Name() string {
+	return "End-of-Life Guest OS"
+}
+
+// Describe returns the metadata of the check
+func (c *EOLCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Warns about guest operating systems that reached end of life. EOL guests are reported as warnings and do not fail validation.", OSFamilyLinux, OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and reports end-of-life guest operating systems
+func (c *EOLCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data, time.Now()), nil
+}
+
+// evaluate checks every detected operating system against the EOL table
+func (c *EOLCheck) evaluate(data *types.VirtInspectorXML, now time.Time) CheckResult {
+	var eol []string
+	var details []string
+
+	for _, guest := range data.Operatingsystems {
+		entry, found := c.lookup(guest)
+		if !found || now.Before(entry.EOLDate) {
+			continue
+		}
+		desc := describeOS(guest)
+		eol = append(eol, desc)
+		details = append(details, fmt.Sprintf("%s reached end of life on %s", desc, entry.EOLDate.Format(time.DateOnly)))
+	}
+
+	if len(eol) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("eol-os.eol", "systems", strings.Join(eol, ", "))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("eol-os.supported")
+}
+
+// lookup finds the EOL entry for the guest OS, preferring major.minor matches over major-only ones
+func (c *EOLCheck) lookup(guest types.VirtInspectorOS) (EOLEntry, bool) {
+	major, minor, err := parseOSVersion(guest)
+	if err != nil {
+		return EOLEntry{}, false
+	}
+	majorVersion := strconv.Itoa(major)
+	fullVersion := fmt.Sprintf("%d.%d", major, minor)
+
+	var majorMatch *EOLEntry
+	for idx := range c.table {
+		entry := &c.table[idx]
+		if !strings.EqualFold(entry.Distro, guest.Distro) {
+			continue
+		}
+		if entry.Version == fullVersion {
+			return *entry, true
+		}
+		if entry.Version == majorVersion && majorMatch == nil {
+			majorMatch = entry
+		}
+	}
+	if majorMatch != nil {
+		return *majorMatch, true
+	}
+	return EOLEntry{}, false
+}
+
+// eolDate returns midnight UTC of the given date
+func eolDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
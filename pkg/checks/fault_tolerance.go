@@ -0,0 +1,74 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// FaultToleranceCheck fails validation when vSphere Fault Tolerance is configured for the VM
+// FT prevents snapshots and therefore blocks the entire inspection and conversion flow
+type FaultToleranceCheck struct{}
+
+// NewFaultToleranceCheck creates a new FaultToleranceCheck
+func NewFaultToleranceCheck() *FaultToleranceCheck {
+	return &FaultToleranceCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *FaultToleranceCheck) ID() string {
+	return "vsphere-fault-tolerance"
+}
+
+// Category returns the category of the check
+func (c *FaultToleranceCheck) Category() string {
+	return CategoryPlatform
+}
+
+// Tags returns the tags of the check
+func (c *FaultToleranceCheck) Tags() []string {
+	return []string{"vsphere-config"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *FaultToleranceCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *FaultToleranceCheck) Name() string {
+	return "Fault Tolerance"
+}
+
+// Describe returns the metadata of the check
+func (c *FaultToleranceCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Fails validation when vSphere Fault Tolerance is configured for the VM. FT prevents snapshots and therefore blocks the entire inspection and conversion flow.")
+}
+
+// Run validates the Fault Tolerance state from the vSphere configuration
+func (c *FaultToleranceCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config), nil
+}
+
+// evaluate reports a configured Fault Tolerance state
+func (c *FaultToleranceCheck) evaluate(config *types.VMConfig) CheckResult {
+	if config.FTState != "" && config.FTState != "notConfigured" {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   []string{fmt.Sprintf("Fault Tolerance state: %s", config.FTState)},
+		}.withMessage("vsphere-fault-tolerance.configured")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("vsphere-fault-tolerance.not-configured")
+}
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// DefaultAllowedFilesystemTypes lists filesystem types the conversion pipeline handles
+var DefaultAllowedFilesystemTypes = []string{
+	"ext2",
+	"ext3",
+	"ext4",
+	"xfs",
+	"btrfs",
+	"vfat",
+	"ntfs",
+	"swap",
+	"crypto_LUKS",
+	"LVM2_member",
+	"iso9660",
+}
+
+// FilesystemTypeCheck compares discovered filesystem types against an allowlist
+type FilesystemTypeCheck struct {
+	allowed  map[string]struct{}
+	severity Severity
+}
+
+// NewFilesystemTypeCheck creates a new FilesystemTypeCheck
+// allowed: allowed filesystem types (uses DefaultAllowedFilesystemTypes if nil)
+// severity: severity reported for disallowed types, SeverityError fails validation
+// and SeverityWarning only warns (defaults to SeverityError if empty)
+func NewFilesystemTypeCheck(allowed []string, severity Severity) *FilesystemTypeCheck {
+	if allowed == nil {
+		allowed = DefaultAllowedFilesystemTypes
+	}
+	if severity == "" {
+		severity = SeverityError
+	}
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, fsType := range allowed {
+		allowedSet[strings.ToLower(fsType)] = struct{}{}
+	}
+	return &FilesystemTypeCheck{
+		allowed:  allowedSet,
+		severity: severity,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *FilesystemTypeCheck) ID() string {
+	return "filesystem-types"
+}
+
+// Category returns the category of the check
+func (c *FilesystemTypeCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *FilesystemTypeCheck) Tags() []string {
+	return []string{"linux", "windows", "filesystem"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *FilesystemTypeCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *FilesystemTypeCheck) Name() string {
+	return "Unsupported Filesystem Types"
+}
+
+// Describe returns the metadata of the check
+func (c *FilesystemTypeCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Compares discovered filesystem types against an allowlist.", OSFamilyLinux, OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and reports filesystems not in the allowlist
+func (c *FilesystemTypeCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks every discovered filesystem type
+func (c *FilesystemTypeCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var details []string
+	unsupported := make(map[string]struct{})
+	var unsupportedTypes []string
+
+	for _, guest := range data.Operatingsystems {
+		for _, fs := range guest.Filesystems.Filesystem {
+			// Unformatted devices have no type and are not a concern
+			if fs.Type == "" || fs.Type == "unknown" {
+				continue
+			}
+			if _, ok := c.allowed[strings.ToLower(fs.Type)]; ok {
+				continue
+			}
+			details = append(details, fmt.Sprintf("%s has unsupported filesystem type %s", fs.Device, fs.Type))
+			if _, seen := unsupported[fs.Type]; !seen {
+				unsupported[fs.Type] = struct{}{}
+				unsupportedTypes = append(unsupportedTypes, fs.Type)
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     c.severity != SeverityError,
+			Severity:  c.severity,
+			Details:   details,
+		}.withMessage("filesystem-types.unsupported", "types", strings.Join(unsupportedTypes, ", "))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("filesystem-types.supported")
+}
@@ -0,0 +1,131 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+const (
+	// DefaultMinRootFreeBytes is the free space virt-v2v needs on / to install drivers
+	DefaultMinRootFreeBytes uint64 = 100 * 1024 * 1024
+	// DefaultMinBootFreeBytes is the free space virt-v2v needs on /boot to rebuild the initramfs
+	DefaultMinBootFreeBytes uint64 = 50 * 1024 * 1024
+)
+
+// FreeSpaceCheck validates minimum free space on the guest root and /boot filesystems
+type FreeSpaceCheck struct {
+	minRootFree uint64
+	minBootFree uint64
+}
+
+// NewFreeSpaceCheck creates a new FreeSpaceCheck
+// minRootFree: minimum free bytes on / (uses DefaultMinRootFreeBytes if zero)
+// minBootFree: minimum free bytes on /boot (uses DefaultMinBootFreeBytes if zero)
+func NewFreeSpaceCheck(minRootFree uint64, minBootFree uint64) *FreeSpaceCheck {
+	if minRootFree == 0 {
+		minRootFree = DefaultMinRootFreeBytes
+	}
+	if minBootFree == 0 {
+		minBootFree = DefaultMinBootFreeBytes
+	}
+	return &FreeSpaceCheck{
+		minRootFree: minRootFree,
+		minBootFree: minBootFree,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *FreeSpaceCheck) ID() string {
+	return "free-space"
+}
+
+// Category returns the category of the check
+func (c *FreeSpaceCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *FreeSpaceCheck) Tags() []string {
+	return []string{"linux", "filesystem"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *FreeSpaceCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *FreeSpaceCheck) Name() string {
+	return "Root Filesystem Free Space"
+}
+
+// Describe returns the metadata of the check
+func (c *FreeSpaceCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Validates minimum free space on the guest root and /boot filesystems.", OSFamilyLinux)
+}
+
+// Run collects filesystem usage for / and /boot and validates free space
+func (c *FreeSpaceCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	usage, err := params.filesystemUsage(ctx, "/", "/boot")
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(usage), nil
+}
+
+// evaluate compares free space against the configured minimums
+func (c *FreeSpaceCheck) evaluate(usage map[string]types.FilesystemUsage) CheckResult {
+	root, found := usage["/"]
+	if !found {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+		}.withMessage("free-space.root-unknown")
+	}
+
+	var problems []string
+	details := []string{fmt.Sprintf("/: %s free of %s", formatBytes(root.FreeBytes), formatBytes(root.TotalBytes))}
+	if root.FreeBytes < c.minRootFree {
+		problems = append(problems, fmt.Sprintf("/ has %s free, at least %s required", formatBytes(root.FreeBytes), formatBytes(c.minRootFree)))
+	}
+
+	if boot, found := usage["/boot"]; found {
+		details = append(details, fmt.Sprintf("/boot: %s free of %s", formatBytes(boot.FreeBytes), formatBytes(boot.TotalBytes)))
+		if boot.FreeBytes < c.minBootFree {
+			problems = append(problems, fmt.Sprintf("/boot has %s free, at least %s required", formatBytes(boot.FreeBytes), formatBytes(c.minBootFree)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   append(problems, details...),
+		}.withMessage("free-space.insufficient")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("free-space.sufficient")
+}
+
+// formatBytes formats a byte count using binary units
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
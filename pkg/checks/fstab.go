@@ -0,0 +1,159 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// vmwareDiskWWNPattern matches by-id names derived from VMware virtual disk WWNs (NAA 6000c29...)
+var vmwareDiskWWNPattern = regexp.MustCompile(`(?i)(scsi-3|wwn-0x)6000c29[0-9a-f]*`)
+
+// vmwareDiskIDIssue returns why a /dev/disk/by-id reference will break after conversion, or "" if it is
+// portable. By-id names embedding VMware disk WWNs or serials are not recreated for virtio devices
+func vmwareDiskIDIssue(ref string) string {
+	switch {
+	case vmwareDiskWWNPattern.MatchString(ref):
+		return "references a /dev/disk/by-id device derived from a VMware disk WWN"
+	case strings.Contains(ref, "/dev/disk/by-id/") && strings.Contains(strings.ToLower(ref), "vmware"):
+		return "references a /dev/disk/by-id device embedding a VMware disk serial"
+	case strings.Contains(ref, "VMware_Virtual"):
+		return "references a VMware virtual disk model name"
+	}
+	return ""
+}
+
+// nonPortableDeviceIssue returns why a device reference will break after conversion, by-path names and
+// by-id names derived from VMware disks, or "" if it is portable
+func nonPortableDeviceIssue(ref string) string {
+	if issue := deviceReferenceIssue(ref); issue != "" {
+		return issue
+	}
+	return vmwareDiskIDIssue(ref)
+}
+
+// FstabCheck validates /etc/fstab device references
+// Entries using /dev/disk/by-path names, or /dev/disk/by-id names embedding VMware
+// WWNs or "VMware_Virtual" serials, will not exist on the target
+type FstabCheck struct{}
+
+// NewFstabCheck creates a new FstabCheck
+func NewFstabCheck() *FstabCheck {
+	return &FstabCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *FstabCheck) ID() string {
+	return "fstab-by-path"
+}
+
+// Category returns the category of the check
+func (c *FstabCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *FstabCheck) Tags() []string {
+	return []string{"linux", "device-names"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *FstabCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *FstabCheck) Name() string {
+	return "Fstab Device References"
+}
+
+// Describe returns the metadata of the check
+func (c *FstabCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Validates /etc/fstab device references. Entries using /dev/disk/by-path names, or /dev/disk/by-id names embedding VMware WWNs or \"VMware_Virtual\" serials, will not exist on the target.", OSFamilyLinux)
+}
+
+// Run reads the guest /etc/fstab and reports non-portable device references
+func (c *FstabCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	files, err := params.readGuestFiles(ctx, "/etc/fstab")
+	if err != nil {
+		return CheckResult{}, err
+	}
+	fstab, found := files["/etc/fstab"]
+	if !found {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("fstab-by-path.no-fstab"), nil
+	}
+	return c.evaluate(fstab), nil
+}
+
+// evaluate checks every fstab entry for non-portable device references
+func (c *FstabCheck) evaluate(fstab []byte) CheckResult {
+	entries := parseFstab(fstab)
+	var details []string
+	for _, entry := range entries {
+		if issue := nonPortableDeviceIssue(entry.Device); issue != "" {
+			details = append(details, fmt.Sprintf("/etc/fstab:%d %s %s: %s", entry.LineNum, entry.MountPoint, issue, entry.Device))
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("fstab-by-path.non-portable")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("fstab-by-path.portable", "count", len(entries))
+}
+
+// fstabEntry represents a single /etc/fstab entry
+type fstabEntry struct {
+	Device     string
+	MountPoint string
+	Type       string
+	Options    string
+	Line       string
+	LineNum    int
+}
+
+// parseFstab parses /etc/fstab content, skipping comments and malformed lines
+func parseFstab(content []byte) []fstabEntry {
+	var entries []fstabEntry
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		entry := fstabEntry{
+			Device:     fields[0],
+			MountPoint: fields[1],
+			Type:       fields[2],
+			Line:       line,
+			LineNum:    lineNum,
+		}
+		if len(fields) >= 4 {
+			entry.Options = fields[3]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
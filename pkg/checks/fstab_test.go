@@ -0,0 +1,104 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVMwareDiskIDIssue(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"/dev/disk/by-id/scsi-36000c29a1b2c3d4e5f60718293a4b5c6-part1", "derived from a VMware disk WWN"},
+		{"/dev/disk/by-id/wwn-0x6000c29f0e1d2c3b4a5968778695a4b3", "derived from a VMware disk WWN"},
+		{"/dev/disk/by-id/scsi-SVMware_Virtual_disk_6000c29a1b2c3d4e-part2", "embedding a VMware disk serial"},
+		{"/dev/disk/by-id/ata-VMware_Virtual_SATA_Hard_Drive_00000000000000000001", "embedding a VMware disk serial"},
+		{"/dev/disk/by-id/scsi-SVMware_Virtual_disk_00000000000000000000000000000000-part1", "embedding a VMware disk serial"},
+		{"(hd0) VMware_Virtual_disk", "VMware virtual disk model name"},
+		{"/dev/disk/by-id/nvme-Samsung_SSD_980_PRO_S5GXNF0R123456-part1", ""},
+		{"/dev/disk/by-id/dm-uuid-LVM-abc", ""},
+		{"/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:0:0", ""},
+		{"UUID=6000c29a-1b2c-3d4e-5f60-718293a4b5c6", ""},
+	}
+	for _, tt := range tests {
+		issue := vmwareDiskIDIssue(tt.ref)
+		if (tt.want == "") != (issue == "") || !strings.Contains(issue, tt.want) {
+			t.Errorf("vmwareDiskIDIssue(%q) = %q, want %q", tt.ref, issue, tt.want)
+		}
+	}
+}
+
+func TestParseFstab(t *testing.T) {
+	content := []byte(`
+# /etc/fstab
+# Created by anaconda
+/dev/mapper/rhel-root   /                       xfs     defaults        0 0
+UUID=3f2a9c1e /boot xfs defaults 0 0
+	/dev/mapper/rhel-swap	none	swap	defaults	0 0
+/swapfile swap swap
+malformed-line
+tmpfs /tmp
+`)
+	want := []fstabEntry{
+		{Device: "/dev/mapper/rhel-root", MountPoint: "/", Type: "xfs", Options: "defaults", LineNum: 4},
+		{Device: "UUID=3f2a9c1e", MountPoint: "/boot", Type: "xfs", Options: "defaults", LineNum: 5},
+		{Device: "/dev/mapper/rhel-swap", MountPoint: "none", Type: "swap", Options: "defaults", LineNum: 6},
+		{Device: "/swapfile", MountPoint: "swap", Type: "swap", LineNum: 7},
+	}
+
+	entries := parseFstab(content)
+	if len(entries) != len(want) {
+		t.Fatalf("parseFstab() = %+v, want %d entries", entries, len(want))
+	}
+	for idx, entry := range entries {
+		entry.Line = ""
+		if entry != want[idx] {
+			t.Errorf("entry %d = %+v, want %+v", idx, entry, want[idx])
+		}
+	}
+	if entries[2].Line != "/dev/mapper/rhel-swap\tnone\tswap\tdefaults\t0 0" {
+		t.Errorf("Line = %q, want the trimmed line", entries[2].Line)
+	}
+	if parseFstab(nil) != nil {
+		t.Error("parseFstab(nil) returned entries")
+	}
+}
+
+func TestFstabCheckEvaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		fstab       string
+		wantValid   bool
+		wantDetails []string
+	}{
+		{
+			name:      "portable",
+			fstab:     "/dev/mapper/rhel-root / xfs defaults 0 0\nUUID=3f2a9c1e /boot xfs defaults 0 0\n",
+			wantValid: true,
+		},
+		{
+			name: "by-path and VMware by-id",
+			fstab: "/dev/mapper/rhel-root / xfs defaults 0 0\n" +
+				"/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0-part1 /data xfs defaults 0 0\n" +
+				"/dev/disk/by-id/wwn-0x6000c29f0e1d2c3b4a5968778695a4b3-part1 /logs ext4 defaults 0 0\n" +
+				"/dev/disk/by-id/scsi-SVMware_Virtual_disk_00000000000000000000000000000000-part1 /srv ext4 defaults 0 0\n",
+			wantDetails: []string{
+				"/etc/fstab:2 /data references a /dev/disk/by-path device tied to the VMware controller topology: /dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0-part1",
+				"/etc/fstab:3 /logs references a /dev/disk/by-id device derived from a VMware disk WWN: /dev/disk/by-id/wwn-0x6000c29f0e1d2c3b4a5968778695a4b3-part1",
+				"/etc/fstab:4 /srv references a /dev/disk/by-id device embedding a VMware disk serial: /dev/disk/by-id/scsi-SVMware_Virtual_disk_00000000000000000000000000000000-part1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewFstabCheck().evaluate([]byte(tt.fstab))
+			if result.Valid != tt.wantValid {
+				t.Fatalf("Valid = %v (%s), want %v", result.Valid, result.Message, tt.wantValid)
+			}
+			if strings.Join(result.Details, "\n") != strings.Join(tt.wantDetails, "\n") {
+				t.Errorf("Details = %q, want %q", result.Details, tt.wantDetails)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// grubConfigPaths lists bootloader configuration files read from the guest
+var grubConfigPaths = []string{
+	"/boot/grub2/grub.cfg",
+	"/boot/grub2/device.map",
+	"/boot/grub/grub.cfg",
+	"/boot/grub/device.map",
+	"/boot/grub/menu.lst",
+	"/etc/default/grub",
+}
+
+// GrubCheck detects bootloader configuration that references device names
+// which will not exist after conversion (by-path or VMware-specific names)
+type GrubCheck struct{}
+
+// NewGrubCheck creates a new GrubCheck
+func NewGrubCheck() *GrubCheck {
+	return &GrubCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *GrubCheck) ID() string {
+	return "grub-device-references"
+}
+
+// Category returns the category of the check
+func (c *GrubCheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *GrubCheck) Tags() []string {
+	return []string{"linux", "device-names"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *GrubCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *GrubCheck) Name() string {
+	return "GRUB Device References"
+}
+
+// Describe returns the metadata of the check
+func (c *GrubCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Detects bootloader configuration that references device names which will not exist after conversion (by-path or VMware-specific names).", OSFamilyLinux)
+}
+
+// Run reads the guest bootloader configuration and reports non-portable device references
+func (c *GrubCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	files, err := params.readGuestFiles(ctx, grubConfigPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(files), nil
+}
+
+// evaluate scans the bootloader configuration files line by line
+func (c *GrubCheck) evaluate(files map[string][]byte) CheckResult {
+	if len(files) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("grub-device-references.no-config")
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var details []string
+	for _, path := range paths {
+		scanner := bufio.NewScanner(bytes.NewReader(files[path]))
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if issue := nonPortableDeviceIssue(line); issue != "" {
+				details = append(details, fmt.Sprintf("%s:%d %s: %s", path, lineNum, issue, line))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("grub-device-references.non-portable")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("grub-device-references.portable", "count", len(files))
+}
@@ -0,0 +1,142 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// GuestAgent describes a platform-specific guest agent found in the application inventory
+type GuestAgent struct {
+	Platform string   `json:"platform"`       // Platform the agent belongs to (e.g., "AWS")
+	Names    []string `json:"names"`          // Application name fragments, matched case-insensitively
+	Keep     bool     `json:"keep,omitempty"` // Whether the agent should be kept on the target platform
+}
+
+// DefaultGuestAgents lists guest agents of other platforms and the QEMU guest agent used by the target
+var DefaultGuestAgents = []GuestAgent{
+	{Platform: "AWS", Names: []string{"amazon-ssm-agent", "AWS Systems Manager", "Amazon SSM Agent", "aws-cfn-bootstrap", "EC2Config", "EC2Launch"}},
+	{Platform: "Azure", Names: []string{"WALinuxAgent", "waagent", "Windows Azure VM Agent", "Azure VM Agent"}},
+	{Platform: "Google Cloud", Names: []string{"google-guest-agent", "google-compute-engine"}},
+	{Platform: "Hyper-V", Names: []string{"hyperv-daemons", "hyperv-tools", "hv-kvp-daemon", "Hyper-V Integration Services"}},
+	{Platform: "VirtualBox", Names: []string{"virtualbox-guest", "VirtualBox Guest Additions"}},
+	{Platform: "QEMU/KVM", Names: []string{"qemu-guest-agent", "QEMU guest agent"}, Keep: true},
+}
+
+// GuestAgentsCheck detects guest agents of other platforms in the application inventory
+// Conflicting agents may fight over networking or hostname; only the target's agent should be kept
+type GuestAgentsCheck struct {
+	agents []GuestAgent
+}
+
+// NewGuestAgentsCheck creates a new GuestAgentsCheck
+// agents: guest agents to look for (uses DefaultGuestAgents if nil)
+func NewGuestAgentsCheck(agents []GuestAgent) *GuestAgentsCheck {
+	if agents == nil {
+		agents = DefaultGuestAgents
+	}
+	return &GuestAgentsCheck{
+		agents: agents,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *GuestAgentsCheck) ID() string {
+	return "guest-agents"
+}
+
+// Category returns the category of the check
+func (c *GuestAgentsCheck) Category() string {
+	return CategoryApplications
+}
+
+// Tags returns the tags of the check
+func (c *GuestAgentsCheck) Tags() []string {
+	return []string{"windows", "linux", "guest-agent"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *GuestAgentsCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *GuestAgentsCheck) Name() string {
+	return "Cloud/Hypervisor Guest Agents"
+}
+
+// Describe returns the metadata of the check
+func (c *GuestAgentsCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects guest agents of other platforms in the application inventory. Conflicting agents may fight over networking or hostname; only the target's agent should be kept.", OSFamilyLinux, OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and reports installed guest agents
+func (c *GuestAgentsCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate matches installed applications against the guest agent list
+func (c *GuestAgentsCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var conflicting []string
+	var kept []string
+	for _, guest := range data.Operatingsystems {
+		for _, app := range guest.Applications.Application {
+			agent := c.match(app.Name)
+			if agent == nil {
+				continue
+			}
+			if agent.Keep {
+				kept = append(kept, fmt.Sprintf("%s %s (%s agent, keep)", app.Name, app.Version, agent.Platform))
+			} else {
+				conflicting = append(conflicting, fmt.Sprintf("%s %s (%s agent, remove after conversion)", app.Name, app.Version, agent.Platform))
+			}
+		}
+	}
+
+	if len(conflicting) > 0 {
+		details := append(conflicting, kept...)
+		if len(kept) == 0 {
+			details = append(details, "no QEMU guest agent installed; install qemu-guest-agent for the target")
+		}
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("guest-agents.conflicting", "count", len(conflicting))
+	}
+
+	if len(kept) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+			Details:   kept,
+		}.withMessage("guest-agents.suitable")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("guest-agents.none")
+}
+
+// match returns the guest agent matching the application name, or nil if none matches
+func (c *GuestAgentsCheck) match(appName string) *GuestAgent {
+	name := strings.ToLower(appName)
+	for idx := range c.agents {
+		for _, fragment := range c.agents[idx].Names {
+			if strings.Contains(name, strings.ToLower(fragment)) {
+				return &c.agents[idx]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// guestIDFamily maps a vSphere guestId prefix to the OS virt-inspector is expected to detect
+type guestIDFamily struct {
+	prefix  string   // guestId prefix (e.g., "rhel")
+	name    string   // virt-inspector OS name ("linux", "windows", ...)
+	distros []string // virt-inspector distros matching the guestId, nil matches any distro
+}
+
+// guestIDFamilies lists known guestId prefixes, more specific prefixes first
+// Guests of RHEL derivatives are commonly configured with the RHEL or CentOS guestId
+var guestIDFamilies = []guestIDFamily{
+	{prefix: "rhel", name: "linux", distros: []string{"rhel", "centos", "rocky", "almalinux", "alma", "oraclelinux"}},
+	{prefix: "centos", name: "linux", distros: []string{"centos", "rhel", "rocky", "almalinux", "alma"}},
+	{prefix: "rockylinux", name: "linux", distros: []string{"rocky"}},
+	{prefix: "almalinux", name: "linux", distros: []string{"almalinux", "alma"}},
+	{prefix: "oracleLinux", name: "linux", distros: []string{"oraclelinux"}},
+	{prefix: "ubuntu", name: "linux", distros: []string{"ubuntu"}},
+	{prefix: "debian", name: "linux", distros: []string{"debian"}},
+	{prefix: "sles", name: "linux", distros: []string{"sles", "opensuse"}},
+	{prefix: "opensuse", name: "linux", distros: []string{"opensuse", "sles"}},
+	{prefix: "fedora", name: "linux", distros: []string{"fedora"}},
+	{prefix: "amazonlinux", name: "linux", distros: []string{"amazonlinux"}},
+	{prefix: "win", name: "windows"},
+	{prefix: "freebsd", name: "freebsd"},
+	{prefix: "solaris", name: "solaris"},
+}
+
+// guestIDVersionPattern extracts the major version following a Linux guestId prefix once the
+// architecture suffix is removed (e.g., "rhel8" -> 8)
+var guestIDVersionPattern = regexp.MustCompile(`^[A-Za-z]+(\d+)$`)
+
+// GuestIDCheck compares the vSphere-configured guestId with the OS detected by virt-inspector
+// Mismatches often indicate template drift and lead to wrong conversion parameters
+type GuestIDCheck struct{}
+
+// NewGuestIDCheck creates a new GuestIDCheck
+func NewGuestIDCheck() *GuestIDCheck {
+	return &GuestIDCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *GuestIDCheck) ID() string {
+	return "guest-id-mismatch"
+}
+
+// Category returns the category of the check
+func (c *GuestIDCheck) Category() string {
+	return CategoryGuestOS
+}
+
+// Tags returns the tags of the check
+func (c *GuestIDCheck) Tags() []string {
+	return []string{"vsphere-config", "windows", "linux"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *GuestIDCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *GuestIDCheck) Name() string {
+	return "Guest OS Identifier"
+}
+
+// Describe returns the metadata of the check
+func (c *GuestIDCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Compares the vSphere-configured guestId with the OS detected by virt-inspector. Mismatches often indicate template drift and lead to wrong conversion parameters.", OSFamilyLinux, OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and compares the detected OS with the vSphere configuration
+func (c *GuestIDCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config.GuestID, data), nil
+}
+
+// evaluate reports mismatches between the configured guestId and detected operating systems
+func (c *GuestIDCheck) evaluate(guestID string, data *types.VirtInspectorXML) CheckResult {
+	if len(data.Operatingsystems) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("guest-id-mismatch.no-os")
+	}
+
+	family, known := lookupGuestIDFamily(guestID)
+	if !known {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("guest-id-mismatch.generic", "guest_id", guestID)
+	}
+
+	var mismatches []string
+	for _, guest := range data.Operatingsystems {
+		detected := describeOS(guest)
+		switch {
+		case guest.Name != family.name:
+			mismatches = append(mismatches, fmt.Sprintf("guestId %s is %s but %s was detected (%s)", guestID, family.name, guest.Name, detected))
+		case family.distros != nil && !slices.Contains(family.distros, guest.Distro):
+			mismatches = append(mismatches, fmt.Sprintf("guestId %s does not match detected distribution %s (%s)", guestID, guest.Distro, detected))
+		case family.name == "linux":
+			if version, ok := guestIDMajorVersion(guestID); ok {
+				if major, _, err := parseOSVersion(guest); err == nil && major != version {
+					mismatches = append(mismatches, fmt.Sprintf("guestId %s is version %d but version %d was detected (%s)", guestID, version, major, detected))
+				}
+			}
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   mismatches,
+		}.withMessage("guest-id-mismatch.mismatch")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("guest-id-mismatch.match", "guest_id", guestID)
+}
+
+// lookupGuestIDFamily returns the OS family of a guestId
+// Generic Linux identifiers (e.g., "otherLinux64Guest", "other5xLinux64Guest") match any Linux distribution
+func lookupGuestIDFamily(guestID string) (guestIDFamily, bool) {
+	for _, family := range guestIDFamilies {
+		if strings.HasPrefix(guestID, family.prefix) {
+			return family, true
+		}
+	}
+	if strings.HasPrefix(guestID, "other") && strings.Contains(guestID, "Linux") {
+		return guestIDFamily{prefix: "other", name: "linux"}, true
+	}
+	return guestIDFamily{}, false
+}
+
+// guestIDMajorVersion returns the major OS version encoded in a Linux guestId, if any
+func guestIDMajorVersion(guestID string) (int, bool) {
+	if strings.HasPrefix(guestID, "other") {
+		return 0, false
+	}
+	name := strings.TrimSuffix(guestID, "Guest")
+	if trimmed, ok := strings.CutSuffix(name, "_64"); ok {
+		name = trimmed
+	} else {
+		name = strings.TrimSuffix(name, "64")
+	}
+	match := guestIDVersionPattern.FindStringSubmatch(name)
+	if match == nil {
+		return 0, false
+	}
+	version, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
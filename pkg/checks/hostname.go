@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// HostnameCheck validates the guest hostname, and optionally the vSphere VM name,
+// against RFC 1123 / Kubernetes naming rules since converted VMs become named Kubernetes resources
+type HostnameCheck struct {
+	checkVMName bool
+}
+
+// NewHostnameCheck creates a new HostnameCheck
+// checkVMName: also validate the vSphere VM name as a Kubernetes DNS label
+func NewHostnameCheck(checkVMName bool) *HostnameCheck {
+	return &HostnameCheck{
+		checkVMName: checkVMName,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *HostnameCheck) ID() string {
+	return "hostname"
+}
+
+// Category returns the category of the check
+func (c *HostnameCheck) Category() string {
+	return CategoryNetwork
+}
+
+// Tags returns the tags of the check
+func (c *HostnameCheck) Tags() []string {
+	return []string{"naming", "kubernetes"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *HostnameCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *HostnameCheck) Name() string {
+	return "Hostname Validity"
+}
+
+// Describe returns the metadata of the check
+func (c *HostnameCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Validates the guest hostname, and optionally the vSphere VM name, against RFC 1123 / Kubernetes naming rules since converted VMs become named Kubernetes resources.")
+}
+
+// Run inspects the VM snapshot and validates the guest hostname
+func (c *HostnameCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data, params.VMName), nil
+}
+
+// evaluate validates hostnames and the VM name
+func (c *HostnameCheck) evaluate(data *types.VirtInspectorXML, vmName string) CheckResult {
+	var details []string
+
+	for _, guest := range data.Operatingsystems {
+		if guest.Hostname == "" {
+			continue
+		}
+		for _, problem := range dns1123SubdomainProblems(guest.Hostname) {
+			details = append(details, fmt.Sprintf("hostname %q: %s", guest.Hostname, problem))
+		}
+	}
+
+	if c.checkVMName {
+		for _, problem := range dns1123LabelProblems(vmName) {
+			details = append(details, fmt.Sprintf("VM name %q: %s", vmName, problem))
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("hostname.invalid")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("hostname.valid")
+}
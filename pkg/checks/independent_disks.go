@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// IndependentDisksCheck detects disks configured in independent mode
+// Independent disks are excluded from snapshots and therefore silently missing from the inspected and converted image
+type IndependentDisksCheck struct{}
+
+// NewIndependentDisksCheck creates a new IndependentDisksCheck
+func NewIndependentDisksCheck() *IndependentDisksCheck {
+	return &IndependentDisksCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *IndependentDisksCheck) ID() string {
+	return "independent-disks"
+}
+
+// Category returns the category of the check
+func (c *IndependentDisksCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *IndependentDisksCheck) Tags() []string {
+	return []string{"vsphere-config"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *IndependentDisksCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *IndependentDisksCheck) Name() string {
+	return "Independent Disk Mode"
+}
+
+// Describe returns the metadata of the check
+func (c *IndependentDisksCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Detects disks configured in independent mode. Independent disks are excluded from snapshots and therefore silently missing from the inspected and converted image.")
+}
+
+// Run validates the VM disks from the vSphere configuration
+func (c *IndependentDisksCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config.Disks), nil
+}
+
+// evaluate reports disks excluded from snapshots by their disk mode
+func (c *IndependentDisksCheck) evaluate(disks []types.VirtualDisk) CheckResult {
+	var details []string
+	for _, disk := range disks {
+		if strings.HasPrefix(disk.DiskMode, "independent") {
+			details = append(details, fmt.Sprintf("%s (%s) is in %s mode", disk.Label, disk.FileName, disk.DiskMode))
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("independent-disks.found", "count", len(details))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("independent-disks.none")
+}
@@ -0,0 +1,29 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// Inspector inspects VM snapshots and reads their guest filesystems for the checks
+// *persistent.Inspector runs the inspection tools against vCenter; fake.Inspector returns canned results,
+// so services can test their validation workflows without libguestfs or vCenter
+type Inspector interface {
+	InspectWithVirt(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) (*types.VirtInspectorXML, error)
+	InspectWithVirtV2v(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, sslVerify string) (*types.VirtV2VInspectorXML, error)
+	ReadGuestFiles(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, paths []string) (map[string][]byte, error)
+	ListGuestDirectory(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, dir string) ([]string, error)
+	PathsExist(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, paths []string) (map[string]bool, error)
+	ListInitramfsFiles(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) (map[string][]string, error)
+	FilesystemUsage(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, mountPoints []string) (map[string]types.FilesystemUsage, error)
+	PartitionTables(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) ([]types.PartitionTable, error)
+	ListEnabledServices(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) ([]types.EnabledService, error)
+	LVMLayout(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) (*types.LVMLayout, error)
+
+	// ToolVersions returns the versions of the inspection tools, recorded in validation reports
+	ToolVersions(ctx context.Context) map[string]string
+
+	// CloseSessions releases the NBD sessions of the inspector once a CheckRunner run is done
+	CloseSessions()
+}
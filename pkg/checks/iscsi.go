@@ -0,0 +1,138 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// iscsiConfigPaths lists guest paths that reveal an iSCSI initiator configuration
+var iscsiConfigPaths = []string{
+	"/etc/iscsi/nodes",
+	"/etc/iscsi/send_targets",
+	"/var/lib/iscsi/nodes",
+	"/var/lib/iscsi/send_targets",
+	"/etc/systemd/system/multi-user.target.wants",
+	"/etc/systemd/system/sockets.target.wants",
+	"/etc/fstab",
+}
+
+// iscsiServices lists systemd units that start the iSCSI initiator at boot
+var iscsiServices = []string{
+	"iscsid.service",
+	"iscsid.socket",
+	"iscsi.service",
+	"open-iscsi.service",
+}
+
+// ISCSICheck detects an active in-guest iSCSI initiator configuration
+// LUNs attached from inside the guest are not migrated by virt-v2v
+type ISCSICheck struct{}
+
+// NewISCSICheck creates a new ISCSICheck
+func NewISCSICheck() *ISCSICheck {
+	return &ISCSICheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *ISCSICheck) ID() string {
+	return "iscsi-initiator"
+}
+
+// Category returns the category of the check
+func (c *ISCSICheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *ISCSICheck) Tags() []string {
+	return []string{"linux", "network"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *ISCSICheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *ISCSICheck) Name() string {
+	return "In-Guest iSCSI Initiator"
+}
+
+// Describe returns the metadata of the check
+func (c *ISCSICheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects an active in-guest iSCSI initiator configuration. LUNs attached from inside the guest are not migrated by virt-v2v.", OSFamilyLinux)
+}
+
+// Run reads the guest iSCSI configuration and reports configured targets and enabled services
+func (c *ISCSICheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	files, err := params.readGuestFiles(ctx, iscsiConfigPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(files), nil
+}
+
+// evaluate looks for discovered targets, enabled iSCSI services and network mounts depending on them
+func (c *ISCSICheck) evaluate(files map[string][]byte) CheckResult {
+	targets := make(map[string]struct{})
+	var services []string
+
+	for file := range files {
+		for _, dir := range []string{"/etc/iscsi/nodes/", "/var/lib/iscsi/nodes/"} {
+			if rel, ok := strings.CutPrefix(file, dir); ok {
+				// Node records are stored as nodes/<target iqn>/<portal>/...
+				targets[strings.SplitN(rel, "/", 2)[0]] = struct{}{}
+			}
+		}
+		base := path.Base(file)
+		for _, service := range iscsiServices {
+			if base == service && strings.Contains(file, ".target.wants/") {
+				services = append(services, service)
+			}
+		}
+	}
+
+	if len(targets) == 0 && len(services) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("iscsi-initiator.none")
+	}
+
+	var details []string
+	targetNames := make([]string, 0, len(targets))
+	for target := range targets {
+		targetNames = append(targetNames, target)
+	}
+	sort.Strings(targetNames)
+	for _, target := range targetNames {
+		details = append(details, fmt.Sprintf("configured iSCSI target: %s", target))
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		details = append(details, fmt.Sprintf("enabled service: %s", service))
+	}
+	details = append(details, netdevMounts(files["/etc/fstab"])...)
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityWarning,
+		Details:   details,
+	}.withMessage("iscsi-initiator.initiator")
+}
+
+// netdevMounts returns fstab entries marked _netdev, which typically depend on network storage
+func netdevMounts(fstab []byte) []string {
+	var mounts []string
+	for _, entry := range parseFstab(fstab) {
+		if strings.Contains(entry.Options, "_netdev") {
+			mounts = append(mounts, fmt.Sprintf("network-dependent fstab entry: %s", entry.Line))
+		}
+	}
+	return mounts
+}
@@ -0,0 +1,156 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// kdumpConfigPaths lists kdump and kernel command line configuration read from the guest
+var kdumpConfigPaths = []string{
+	"/etc/kdump.conf",
+	"/etc/default/kdump-tools",
+	"/etc/sysconfig/kdump",
+	"/etc/default/grub",
+}
+
+// kdumpDeviceTargets lists /etc/kdump.conf directives whose argument is a dump target device
+var kdumpDeviceTargets = []string{"raw", "ext2", "ext3", "ext4", "xfs", "btrfs", "minix"}
+
+// KdumpCheck validates the kdump configuration and crashkernel= reservation
+// Dump targets on by-path or VMware-specific devices, or raw SAN LUNs, will be invalid after migration
+type KdumpCheck struct{}
+
+// NewKdumpCheck creates a new KdumpCheck
+func NewKdumpCheck() *KdumpCheck {
+	return &KdumpCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *KdumpCheck) ID() string {
+	return "kdump"
+}
+
+// Category returns the category of the check
+func (c *KdumpCheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *KdumpCheck) Tags() []string {
+	return []string{"linux", "kernel", "device-names"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *KdumpCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *KdumpCheck) Name() string {
+	return "Kdump Configuration"
+}
+
+// Describe returns the metadata of the check
+func (c *KdumpCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Validates the kdump configuration and crashkernel= reservation. Dump targets on by-path or VMware-specific devices, or raw SAN LUNs, will be invalid after migration.", OSFamilyLinux)
+}
+
+// Run reads the guest kdump configuration and kernel command line defaults
+func (c *KdumpCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	files, err := params.readGuestFiles(ctx, kdumpConfigPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(files), nil
+}
+
+// evaluate checks the crashkernel reservation and dump targets
+func (c *KdumpCheck) evaluate(files map[string][]byte) CheckResult {
+	var problems []string
+	var details []string
+
+	for _, value := range kernelParameterValues(files["/etc/default/grub"], "crashkernel") {
+		details = append(details, fmt.Sprintf("crashkernel=%s", value))
+		if strings.Contains(value, "@") {
+			problems = append(problems, fmt.Sprintf("crashkernel=%s reserves memory at a fixed offset, which may be invalid with the target memory layout", value))
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(files["/etc/kdump.conf"]))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		directive, target := fields[0], fields[1]
+		for _, deviceTarget := range kdumpDeviceTargets {
+			if directive != deviceTarget {
+				continue
+			}
+			details = append(details, fmt.Sprintf("dump target: %s %s", directive, target))
+			if issue := deviceReferenceIssue(target); issue != "" {
+				problems = append(problems, fmt.Sprintf("/etc/kdump.conf:%d dump target %s", lineNum, issue))
+			} else if directive == "raw" {
+				problems = append(problems, fmt.Sprintf("/etc/kdump.conf:%d dumps to raw device %s, which may be a LUN that is not migrated", lineNum, target))
+			}
+		}
+	}
+
+	configured := len(details) > 0
+	for _, p := range []string{"/etc/kdump.conf", "/etc/default/kdump-tools", "/etc/sysconfig/kdump"} {
+		if _, found := files[p]; found {
+			configured = true
+		}
+	}
+	if !configured {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("kdump.not-configured")
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(problems, details...),
+		}.withMessage("kdump.non-portable")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("kdump.portable")
+}
+
+// kernelParameterValues extracts the values of a kernel parameter from /etc/default/grub command lines
+func kernelParameterValues(grubDefaults []byte, name string) []string {
+	var values []string
+	prefix := name + "="
+	scanner := bufio.NewScanner(bytes.NewReader(grubDefaults))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || !strings.HasPrefix(line, "GRUB_CMDLINE_LINUX") {
+			continue
+		}
+		_, cmdline, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		for _, field := range strings.Fields(strings.Trim(cmdline, `"'`)) {
+			if value, ok := strings.CutPrefix(strings.Trim(field, `"'`), prefix); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
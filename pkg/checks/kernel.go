@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// kernelVersionPattern matches the leading dotted numeric part of a kernel version
+var kernelVersionPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*)`)
+
+// installedKernels returns the versions of kernel packages found in the application inventory
+// RPM based guests ship kernel, kernel-core or kernel-default packages, Debian based guests
+// ship linux-image-<version> packages
+func installedKernels(guest types.VirtInspectorOS) []string {
+	var kernels []string
+	for _, app := range guest.Applications.Application {
+		switch {
+		case app.Name == "kernel" || app.Name == "kernel-core" || app.Name == "kernel-default" || app.Name == "kernel-uek":
+			version := app.Version
+			if app.Release != "" {
+				version += "-" + app.Release
+			}
+			kernels = append(kernels, version)
+		case strings.HasPrefix(app.Name, "linux-image-") && kernelVersionPattern.MatchString(strings.TrimPrefix(app.Name, "linux-image-")):
+			kernels = append(kernels, strings.TrimPrefix(app.Name, "linux-image-"))
+		}
+	}
+	return kernels
+}
+
+// parseKernelVersion parses the numeric components of a kernel version (e.g., "3.10.0-1160.el7" -> [3 10 0])
+func parseKernelVersion(version string) ([]int, bool) {
+	match := kernelVersionPattern.FindString(strings.TrimSpace(version))
+	if match == "" {
+		return nil, false
+	}
+	parts := strings.Split(match, ".")
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, true
+}
+
+// compareKernelVersions compares two parsed kernel versions, returning -1, 0 or 1
+func compareKernelVersions(a, b []int) int {
+	for idx := 0; idx < len(a) || idx < len(b); idx++ {
+		var x, y int
+		if idx < len(a) {
+			x = a[idx]
+		}
+		if idx < len(b) {
+			y = b[idx]
+		}
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,241 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// DefaultInitramfsSizeBytes is the free space on /boot assumed to be needed for an extra initramfs
+const DefaultInitramfsSizeBytes uint64 = 64 * 1024 * 1024
+
+// grubEnvPaths lists GRUB environment blocks holding the saved default boot entry
+var grubEnvPaths = []string{
+	"/boot/grub2/grubenv",
+	"/boot/grub/grubenv",
+}
+
+// Kernel flavors reported by the kernel inventory
+const (
+	kernelFlavorStandard = "standard"
+	kernelFlavorRealtime = "realtime"
+	kernelFlavorDebug    = "debug"
+)
+
+// kernelPackage describes an installed kernel package and its flavor
+type kernelPackage struct {
+	name    string
+	version string
+	flavor  string
+}
+
+// KernelInventoryCheck enumerates installed kernels and verifies virt-v2v can update the default one
+// virt-v2v installs virtio drivers into standard kernels only, and needs room on /boot for a new initramfs
+type KernelInventoryCheck struct {
+	minBootFree uint64
+}
+
+// NewKernelInventoryCheck creates a new KernelInventoryCheck
+// minBootFree: free bytes needed on /boot for an extra initramfs (uses DefaultInitramfsSizeBytes if zero)
+func NewKernelInventoryCheck(minBootFree uint64) *KernelInventoryCheck {
+	if minBootFree == 0 {
+		minBootFree = DefaultInitramfsSizeBytes
+	}
+	return &KernelInventoryCheck{
+		minBootFree: minBootFree,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *KernelInventoryCheck) ID() string {
+	return "kernel-inventory"
+}
+
+// Category returns the category of the check
+func (c *KernelInventoryCheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *KernelInventoryCheck) Tags() []string {
+	return []string{"linux", "kernel"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *KernelInventoryCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *KernelInventoryCheck) Name() string {
+	return "Installed Kernels"
+}
+
+// Describe returns the metadata of the check
+func (c *KernelInventoryCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Enumerates installed kernels and verifies virt-v2v can update the default one. virt-v2v installs virtio drivers into standard kernels only, and needs room on /boot for a new initramfs.", OSFamilyLinux)
+}
+
+// Run inspects installed kernel packages, the saved default boot entry and /boot free space
+func (c *KernelInventoryCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	files, err := params.readGuestFiles(ctx, grubEnvPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	usage, err := params.filesystemUsage(ctx, "/boot")
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data, files, usage), nil
+}
+
+// evaluate reports the kernel inventory and problems updating the default kernel
+func (c *KernelInventoryCheck) evaluate(data *types.VirtInspectorXML, files map[string][]byte, usage map[string]types.FilesystemUsage) CheckResult {
+	var kernels []kernelPackage
+	linuxFound := false
+	for _, guest := range data.Operatingsystems {
+		if guest.Name != "linux" {
+			continue
+		}
+		linuxFound = true
+		kernels = append(kernels, kernelPackages(guest)...)
+	}
+
+	if !linuxFound {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("kernel-inventory.no-kernels")
+	}
+
+	if len(kernels) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+		}.withMessage("kernel-inventory.no-packages")
+	}
+
+	var details []string
+	standard := 0
+	for _, kernel := range kernels {
+		details = append(details, fmt.Sprintf("%s %s (%s)", kernel.name, kernel.version, kernel.flavor))
+		if kernel.flavor == kernelFlavorStandard {
+			standard++
+		}
+	}
+
+	var problems []string
+	if standard == 0 {
+		problems = append(problems, "only realtime/debug kernels are installed; virt-v2v may not be able to update them")
+	}
+
+	savedEntry := ""
+	for _, p := range grubEnvPaths {
+		if entry := grubEnvValue(files[p], "saved_entry"); entry != "" {
+			savedEntry = entry
+			break
+		}
+	}
+	if savedEntry != "" {
+		details = append(details, fmt.Sprintf("default boot entry: %s", savedEntry))
+		if flavor := defaultKernelFlavor(savedEntry, kernels); flavor != kernelFlavorStandard {
+			problems = append(problems, fmt.Sprintf("default boot entry %q is not a standard kernel virt-v2v can update (%s)", savedEntry, flavor))
+		}
+	}
+
+	if boot, found := usage["/boot"]; found {
+		details = append(details, fmt.Sprintf("/boot: %s available", formatBytes(boot.AvailableBytes)))
+		if boot.AvailableBytes < c.minBootFree {
+			problems = append(problems, fmt.Sprintf("/boot has %s available, an extra initramfs needs about %s", formatBytes(boot.AvailableBytes), formatBytes(c.minBootFree)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(problems, details...),
+		}.withMessage("kernel-inventory.needs-review", "count", len(problems))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("kernel-inventory.updatable", "count", standard)
+}
+
+// kernelPackages returns installed kernel packages of all flavors from the application inventory
+func kernelPackages(guest types.VirtInspectorOS) []kernelPackage {
+	var kernels []kernelPackage
+	for _, app := range guest.Applications.Application {
+		version := app.Version
+		if app.Release != "" {
+			version += "-" + app.Release
+		}
+		switch app.Name {
+		case "kernel", "kernel-core", "kernel-default", "kernel-uek":
+			kernels = append(kernels, kernelPackage{name: app.Name, version: version, flavor: kernelFlavorStandard})
+		case "kernel-rt", "kernel-rt-core", "kernel-rt-debug":
+			kernels = append(kernels, kernelPackage{name: app.Name, version: version, flavor: kernelFlavorRealtime})
+		case "kernel-debug", "kernel-debug-core", "kernel-uek-debug", "kernel-default-debug":
+			kernels = append(kernels, kernelPackage{name: app.Name, version: version, flavor: kernelFlavorDebug})
+		default:
+			release, ok := strings.CutPrefix(app.Name, "linux-image-")
+			if !ok || !kernelVersionPattern.MatchString(release) {
+				continue
+			}
+			kernels = append(kernels, kernelPackage{name: app.Name, version: release, flavor: kernelReleaseFlavor(release)})
+		}
+	}
+	return kernels
+}
+
+// kernelReleaseFlavor returns the flavor encoded in a kernel release string
+func kernelReleaseFlavor(release string) string {
+	switch {
+	case strings.Contains(release, "+debug") || strings.HasSuffix(release, ".debug") || strings.HasSuffix(release, "-dbg"):
+		return kernelFlavorDebug
+	case strings.Contains(release, "-rt") || strings.Contains(release, ".rt"):
+		return kernelFlavorRealtime
+	default:
+		return kernelFlavorStandard
+	}
+}
+
+// defaultKernelFlavor returns the flavor of the kernel booted by the saved GRUB entry
+// Returns "unknown" when the entry does not reference an installed kernel
+func defaultKernelFlavor(savedEntry string, kernels []kernelPackage) string {
+	if flavor := kernelReleaseFlavor(savedEntry); flavor != kernelFlavorStandard {
+		return flavor
+	}
+	for _, kernel := range kernels {
+		if kernel.flavor == kernelFlavorStandard && strings.Contains(savedEntry, kernel.version) {
+			return kernelFlavorStandard
+		}
+	}
+	return "unknown"
+}
+
+// grubEnvValue returns the value of a variable in a GRUB environment block
+func grubEnvValue(content []byte, name string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), name+"="); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// luksFilesystemType is the filesystem type virt-inspector reports for LUKS containers
+const luksFilesystemType = "crypto_LUKS"
+
+// LUKSCheck flags guests whose root or boot volumes are LUKS-encrypted
+// virt-v2v cannot convert such guests unless the keys are provided
+type LUKSCheck struct{}
+
+// NewLUKSCheck creates a new LUKSCheck
+func NewLUKSCheck() *LUKSCheck {
+	return &LUKSCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *LUKSCheck) ID() string {
+	return "luks-encryption"
+}
+
+// Category returns the category of the check
+func (c *LUKSCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *LUKSCheck) Tags() []string {
+	return []string{"linux", "encryption"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *LUKSCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *LUKSCheck) Name() string {
+	return "LUKS Encryption"
+}
+
+// Describe returns the metadata of the check
+func (c *LUKSCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Flags guests whose root or boot volumes are LUKS-encrypted. virt-v2v cannot convert such guests unless the keys are provided.", OSFamilyLinux)
+}
+
+// Run inspects the VM snapshot and reports LUKS-encrypted devices
+func (c *LUKSCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks the inspection data for encrypted root or boot volumes
+func (c *LUKSCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var encrypted []string
+	var details []string
+	blocking := false
+
+	for _, guest := range data.Operatingsystems {
+		luksDevices := make(map[string]struct{})
+		for _, fs := range guest.Filesystems.Filesystem {
+			if fs.Type == luksFilesystemType {
+				luksDevices[fs.Device] = struct{}{}
+				encrypted = append(encrypted, fs.Device)
+			}
+		}
+
+		for _, mp := range guest.Mountpoints.Mountpoint {
+			if !isBootCritical(mp.MountPoint) {
+				continue
+			}
+			if isLUKSDevice(mp.Device, luksDevices) {
+				blocking = true
+				details = append(details, fmt.Sprintf("%s is mounted from encrypted device %s", mp.MountPoint, mp.Device))
+			}
+		}
+
+		if guest.Root != "" && isLUKSDevice(guest.Root, luksDevices) {
+			blocking = true
+			details = append(details, fmt.Sprintf("root filesystem %s is on an encrypted device", guest.Root))
+		}
+	}
+
+	for _, dev := range encrypted {
+		details = append(details, fmt.Sprintf("encrypted device: %s", dev))
+	}
+
+	if blocking {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("luks-encryption.root-encrypted")
+	}
+
+	if len(encrypted) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+			Details:   details,
+		}.withMessage("luks-encryption.data-encrypted", "count", len(encrypted))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("luks-encryption.none")
+}
+
+// isBootCritical returns true for mount points required to boot the guest
+func isBootCritical(mountPoint string) bool {
+	return mountPoint == "/" || mountPoint == "/boot"
+}
+
+// isLUKSDevice returns true if the device is a LUKS container or a mapping opened from one
+func isLUKSDevice(device string, luksDevices map[string]struct{}) bool {
+	if _, ok := luksDevices[device]; ok {
+		return true
+	}
+
+	// Opened LUKS containers are mapped as /dev/mapper/luks-<uuid>
+	return strings.HasPrefix(device, "/dev/mapper/luks-")
+}
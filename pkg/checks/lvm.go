@@ -0,0 +1,245 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// Thin pool metadata usage thresholds, an exhausted metadata volume makes the pool read-only
+const (
+	thinMetadataWarningPercent = 80
+	thinMetadataErrorPercent   = 95
+)
+
+// LVMCheck validates LVM layouts reported by virt-inspector and lvm
+// It reports volume groups with missing physical volumes, naming the VM disks left out of
+// SnapshotDiskInfo that likely hold them, the logical volumes that would be broken after migration,
+// and thin pools whose metadata is close to exhaustion
+type LVMCheck struct{}
+
+// NewLVMCheck creates a new LVMCheck
+func NewLVMCheck() *LVMCheck {
+	return &LVMCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *LVMCheck) ID() string {
+	return "lvm"
+}
+
+// Category returns the category of the check
+func (c *LVMCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *LVMCheck) Tags() []string {
+	return []string{"linux"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *LVMCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *LVMCheck) Name() string {
+	return "LVM Configuration"
+}
+
+// Describe returns the metadata of the check
+func (c *LVMCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Validates LVM layouts. Volume groups with physical volumes missing from the snapshot disks, for example on disks excluded from SnapshotDiskInfo, break their logical volumes after migration. Thin pools with metadata usage above 80% risk becoming read-only.", OSFamilyLinux)
+}
+
+// Run inspects the VM snapshot and reports logical volumes that would be broken after migration
+func (c *LVMCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	layout, err := params.lvmLayout(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data, layout, params.DiskInfo, params.VMConfig), nil
+}
+
+// evaluate checks that every volume group is complete and every thin pool has metadata space
+// vmConfig is optional, it names the VM disks outside diskInfo
+func (c *LVMCheck) evaluate(data *types.VirtInspectorXML, layout *types.LVMLayout, diskInfo *types.SnapshotDiskInfo, vmConfig *types.VMConfig) CheckResult {
+	var details []string
+	brokenVGs := make(map[string]struct{})
+
+	// Volume groups with physical volumes recorded in their metadata but not found on the snapshot disks
+	missingPVs := make(map[string]int)
+	for _, pv := range layout.PhysicalVolumes {
+		if pv.Missing {
+			missingPVs[pv.VolumeGroup]++
+		}
+	}
+	outsideDisks := disksOutsideSnapshot(diskInfo, vmConfig)
+	for _, vg := range sortedKeys(missingPVs) {
+		brokenVGs[vg] = struct{}{}
+		detail := fmt.Sprintf("volume group %s has %d missing physical volume(s)", vg, missingPVs[vg])
+		if len(outsideDisks) > 0 {
+			detail += fmt.Sprintf(", likely on disks not included in the snapshot disk info: %s", strings.Join(outsideDisks, ", "))
+		}
+		details = append(details, detail)
+		for _, lv := range layout.LogicalVolumes {
+			if lv.VolumeGroup == vg && lv.Partial() {
+				details = append(details, fmt.Sprintf("logical volume %s/%s would be broken after migration", vg, lv.Name))
+			}
+		}
+	}
+
+	// Logical volumes mounted by the guest but not found by virt-inspector
+	lvCount := 0
+	for _, guest := range data.Operatingsystems {
+		available := make(map[string]struct{})
+		for _, fs := range guest.Filesystems.Filesystem {
+			available[fs.Device] = struct{}{}
+			if _, _, ok := parseLVMDevice(fs.Device); ok {
+				lvCount++
+			}
+		}
+
+		for _, mp := range guest.Mountpoints.Mountpoint {
+			vg, lv, ok := parseLVMDevice(mp.Device)
+			if !ok {
+				continue
+			}
+			if _, found := available[mp.Device]; found {
+				continue
+			}
+			brokenVGs[vg] = struct{}{}
+			details = append(details, fmt.Sprintf("logical volume %s/%s mounted on %s is not available (volume group %s is incomplete)", vg, lv, mp.MountPoint, vg))
+		}
+	}
+
+	// Thin pools close to metadata exhaustion
+	var fullPools []string
+	thinSeverity := SeverityInfo
+	for _, lv := range layout.LogicalVolumes {
+		if !lv.ThinPool() || lv.MetadataPercent == nil || *lv.MetadataPercent < thinMetadataWarningPercent {
+			continue
+		}
+		pool := lv.VolumeGroup + "/" + lv.Name
+		fullPools = append(fullPools, pool)
+		details = append(details, fmt.Sprintf("thin pool %s metadata is %.1f%% used", pool, *lv.MetadataPercent))
+		if *lv.MetadataPercent >= thinMetadataErrorPercent {
+			thinSeverity = SeverityError
+		} else if thinSeverity == SeverityInfo {
+			thinSeverity = SeverityWarning
+		}
+	}
+
+	if len(brokenVGs) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("lvm.missing-pvs", "volume_groups", strings.Join(sortedKeys(brokenVGs), ", "))
+	}
+
+	if len(fullPools) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     thinSeverity != SeverityError,
+			Severity:  thinSeverity,
+			Details:   details,
+		}.withMessage("lvm.thin-metadata", "pools", strings.Join(fullPools, ", "), "percent", thinMetadataWarningPercent)
+	}
+
+	if lvCount == 0 && len(layout.LogicalVolumes) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("lvm.none")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("lvm.available", "count", max(lvCount, len(layout.LogicalVolumes)))
+}
+
+// disksOutsideSnapshot returns the VM disks of vmConfig that are not disks of diskInfo, e.g. independent
+// disks, which are not part of snapshots
+func disksOutsideSnapshot(diskInfo *types.SnapshotDiskInfo, vmConfig *types.VMConfig) []string {
+	if diskInfo == nil || vmConfig == nil {
+		return nil
+	}
+	var outside []string
+	for _, disk := range vmConfig.Disks {
+		included := false
+		for _, snapshotDisk := range diskInfo.Disks() {
+			if sameDiskFile(disk.FileName, snapshotDisk.DiskPath) || sameDiskFile(disk.FileName, snapshotDisk.BaseDiskPath) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			outside = append(outside, fmt.Sprintf("%s (%s)", disk.Label, disk.FileName))
+		}
+	}
+	return outside
+}
+
+// sameDiskFile reports whether two datastore paths are files of the same disk, a base disk and its deltas
+func sameDiskFile(a string, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return types.BaseDiskPath(a) == types.BaseDiskPath(b)
+}
+
+// sortedKeys returns the keys of m in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseLVMDevice extracts the volume group and logical volume names from an LVM device path
+// Supports both /dev/mapper/<vg>-<lv> (with "--" escaping) and /dev/<vg>/<lv> forms
+func parseLVMDevice(device string) (string, string, bool) {
+	if name, ok := strings.CutPrefix(device, "/dev/mapper/"); ok {
+		if strings.HasPrefix(name, "luks-") || strings.HasPrefix(name, "mpath") {
+			return "", "", false
+		}
+		// Device-mapper escapes "-" in VG/LV names as "--"
+		for i := 0; i < len(name); i++ {
+			if name[i] != '-' {
+				continue
+			}
+			if i+1 < len(name) && name[i+1] == '-' {
+				i++
+				continue
+			}
+			vg := strings.ReplaceAll(name[:i], "--", "-")
+			lv := strings.ReplaceAll(name[i+1:], "--", "-")
+			if vg == "" || lv == "" {
+				return "", "", false
+			}
+			return vg, lv, true
+		}
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(device, "/dev/"), "/")
+	if !strings.HasPrefix(device, "/dev/") || len(parts) != 2 || parts[0] == "disk" || parts[0] == "mapper" || parts[0] == "md" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
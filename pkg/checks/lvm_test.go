@@ -0,0 +1,142 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+func percent(value float64) *float64 {
+	return &value
+}
+
+func TestLVMCheckEvaluate(t *testing.T) {
+	diskInfo := &types.SnapshotDiskInfo{DiskPath: "[ds] vm/vm-000001.vmdk", BaseDiskPath: "[ds] vm/vm.vmdk"}
+	vmConfig := &types.VMConfig{Disks: []types.VirtualDisk{
+		{Label: "Hard disk 1", FileName: "[ds] vm/vm-000001.vmdk"},
+		{Label: "Hard disk 2", FileName: "[ds] vm/vm_1.vmdk", DiskMode: "independent_persistent"},
+	}}
+	rootLV := types.LVMLogicalVolume{VolumeGroup: "rhel", Name: "root", Attributes: "-wi-ao----"}
+	rootPV := types.LVMPhysicalVolume{Device: "/dev/sda2", Disk: "/dev/sda", VolumeGroup: "rhel"}
+	mounted := &types.VirtInspectorXML{Operatingsystems: []types.VirtInspectorOS{{
+		Mountpoints: types.VirtInspectorMountpoints{Mountpoint: []types.VirtInspectorMountpoint{{Device: "/dev/rhel/root", MountPoint: "/"}}},
+		Filesystems: types.VirtInspectorFilesystems{Filesystem: []types.VirtInspectorFilesystem{{Device: "/dev/rhel/root"}}},
+	}}}
+
+	tests := []struct {
+		name         string
+		data         *types.VirtInspectorXML
+		layout       *types.LVMLayout
+		vmConfig     *types.VMConfig
+		wantSeverity Severity
+		wantMessage  string
+		wantDetails  []string
+	}{
+		{
+			name:         "no LVM",
+			data:         &types.VirtInspectorXML{},
+			layout:       &types.LVMLayout{},
+			wantSeverity: SeverityInfo,
+			wantMessage:  "lvm.none",
+		},
+		{
+			name:         "complete volume groups",
+			data:         mounted,
+			layout:       &types.LVMLayout{PhysicalVolumes: []types.LVMPhysicalVolume{rootPV}, LogicalVolumes: []types.LVMLogicalVolume{rootLV}},
+			wantSeverity: SeverityInfo,
+			wantMessage:  "lvm.available",
+		},
+		{
+			name: "missing physical volume on a disk outside the snapshot",
+			data: mounted,
+			layout: &types.LVMLayout{
+				PhysicalVolumes: []types.LVMPhysicalVolume{rootPV, {VolumeGroup: "data", Missing: true}},
+				LogicalVolumes: []types.LVMLogicalVolume{rootLV,
+					{VolumeGroup: "data", Name: "db", Attributes: "-wi-----p-"},
+				},
+			},
+			vmConfig:     vmConfig,
+			wantSeverity: SeverityError,
+			wantMessage:  "lvm.missing-pvs",
+			wantDetails: []string{
+				"volume group data has 1 missing physical volume(s), likely on disks not included in the snapshot disk info: Hard disk 2 ([ds] vm/vm_1.vmdk)",
+				"logical volume data/db would be broken after migration",
+			},
+		},
+		{
+			name: "mounted logical volume not found",
+			data: &types.VirtInspectorXML{Operatingsystems: []types.VirtInspectorOS{{
+				Mountpoints: types.VirtInspectorMountpoints{Mountpoint: []types.VirtInspectorMountpoint{{Device: "/dev/mapper/data-db", MountPoint: "/var/lib/db"}}},
+			}}},
+			layout:       &types.LVMLayout{},
+			wantSeverity: SeverityError,
+			wantMessage:  "lvm.missing-pvs",
+			wantDetails:  []string{"logical volume data/db mounted on /var/lib/db is not available (volume group data is incomplete)"},
+		},
+		{
+			name: "thin pool metadata above the warning threshold",
+			data: mounted,
+			layout: &types.LVMLayout{LogicalVolumes: []types.LVMLogicalVolume{rootLV,
+				{VolumeGroup: "rhel", Name: "pool", Attributes: "twi-aotz--", MetadataPercent: percent(85)},
+			}},
+			wantSeverity: SeverityWarning,
+			wantMessage:  "lvm.thin-metadata",
+			wantDetails:  []string{"thin pool rhel/pool metadata is 85.0% used"},
+		},
+		{
+			name: "thin pool metadata nearly exhausted",
+			data: mounted,
+			layout: &types.LVMLayout{LogicalVolumes: []types.LVMLogicalVolume{
+				{VolumeGroup: "rhel", Name: "pool", Attributes: "twi-aotz--", MetadataPercent: percent(97.5)},
+			}},
+			wantSeverity: SeverityError,
+			wantMessage:  "lvm.thin-metadata",
+		},
+		{
+			name: "thin pool metadata below the threshold",
+			data: mounted,
+			layout: &types.LVMLayout{LogicalVolumes: []types.LVMLogicalVolume{
+				{VolumeGroup: "rhel", Name: "pool", Attributes: "twi-aotz--", MetadataPercent: percent(12)},
+			}},
+			wantSeverity: SeverityInfo,
+			wantMessage:  "lvm.available",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewLVMCheck().evaluate(tt.data, tt.layout, diskInfo, tt.vmConfig)
+			if result.Severity != tt.wantSeverity || result.MessageID != tt.wantMessage {
+				t.Fatalf("evaluate() = %s %s (%s), want %s %s", result.Severity, result.MessageID, result.Message, tt.wantSeverity, tt.wantMessage)
+			}
+			if result.Valid != (tt.wantSeverity != SeverityError) {
+				t.Errorf("Valid = %v for severity %s", result.Valid, result.Severity)
+			}
+			if tt.wantDetails != nil && strings.Join(result.Details, "\n") != strings.Join(tt.wantDetails, "\n") {
+				t.Errorf("Details = %q, want %q", result.Details, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func TestParseLVMDevice(t *testing.T) {
+	tests := []struct {
+		device string
+		vg, lv string
+		ok     bool
+	}{
+		{"/dev/mapper/rhel-root", "rhel", "root", true},
+		{"/dev/mapper/my--vg-my--lv", "my-vg", "my-lv", true},
+		{"/dev/rhel/swap", "rhel", "swap", true},
+		{"/dev/mapper/luks-0f1e", "", "", false},
+		{"/dev/mapper/mpatha", "", "", false},
+		{"/dev/disk/by-uuid/abc", "", "", false},
+		{"/dev/sda1", "", "", false},
+	}
+	for _, tt := range tests {
+		vg, lv, ok := parseLVMDevice(tt.device)
+		if vg != tt.vg || lv != tt.lv || ok != tt.ok {
+			t.Errorf("parseLVMDevice(%q) = %q, %q, %v, want %q, %q, %v", tt.device, vg, lv, ok, tt.vg, tt.lv, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// networkConfigPaths lists guest network configuration files and directories to read
+var networkConfigPaths = []string{
+	"/etc/sysconfig/network-scripts",
+	"/etc/sysconfig/network",
+	"/etc/NetworkManager/system-connections",
+	"/etc/netplan",
+	"/etc/network/interfaces",
+	"/etc/systemd/network",
+	"/etc/udev/rules.d/70-persistent-net.rules",
+}
+
+// macPinningPatterns match configuration lines that pin an interface to a MAC address, per config format
+var (
+	ifcfgMACPattern    = regexp.MustCompile(`^\s*(HWADDR|MACADDR)\s*=\s*"?[0-9A-Fa-f:]{17}`)
+	keyfileMACPattern  = regexp.MustCompile(`^\s*mac-address\s*=\s*[0-9A-Fa-f:]{17}`)
+	netplanMACPattern  = regexp.MustCompile(`^\s*macaddress\s*:\s*["']?[0-9A-Fa-f:]{17}`)
+	ifupdownMACPattern = regexp.MustCompile(`^\s*hwaddress\s+(ether\s+)?[0-9A-Fa-f:]{17}`)
+	networkdMACPattern = regexp.MustCompile(`^\s*(MACAddress|PermanentMACAddress)\s*=\s*[0-9A-Fa-f:]{17}`)
+	udevMACPattern     = regexp.MustCompile(`ATTR\{address\}\s*==\s*"[0-9A-Fa-f:]{17}"`)
+)
+
+// MACNetworkCheck flags guest network interfaces pinned to a MAC address
+// Such interfaces lose their configuration if the target assigns new MAC addresses
+type MACNetworkCheck struct{}
+
+// NewMACNetworkCheck creates a new MACNetworkCheck
+func NewMACNetworkCheck() *MACNetworkCheck {
+	return &MACNetworkCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *MACNetworkCheck) ID() string {
+	return "mac-pinned-network"
+}
+
+// Category returns the category of the check
+func (c *MACNetworkCheck) Category() string {
+	return CategoryNetwork
+}
+
+// Tags returns the tags of the check
+func (c *MACNetworkCheck) Tags() []string {
+	return []string{"linux"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *MACNetworkCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *MACNetworkCheck) Name() string {
+	return "MAC-Pinned Network Configuration"
+}
+
+// Describe returns the metadata of the check
+func (c *MACNetworkCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Flags guest network interfaces pinned to a MAC address. Such interfaces lose their configuration if the target assigns new MAC addresses.", OSFamilyLinux)
+}
+
+// Run reads the guest network configuration and reports MAC-pinned interfaces
+func (c *MACNetworkCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	files, err := params.readGuestFiles(ctx, networkConfigPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(files), nil
+}
+
+// evaluate scans network configuration files for MAC address matches
+func (c *MACNetworkCheck) evaluate(files map[string][]byte) CheckResult {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var details []string
+	for _, p := range paths {
+		pattern := macPatternFor(p)
+		if pattern == nil {
+			continue
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(files[p]))
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := scanner.Text()
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+				continue
+			}
+			if pattern.MatchString(line) {
+				details = append(details, fmt.Sprintf("%s:%d pins interface to MAC address: %s", p, lineNum, trimmed))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("mac-pinned-network.pinned")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("mac-pinned-network.none")
+}
+
+// macPatternFor returns the MAC pinning pattern for the configuration format of the file, or nil to skip it
+func macPatternFor(file string) *regexp.Regexp {
+	base := path.Base(file)
+	switch {
+	case strings.HasPrefix(base, "ifcfg-"):
+		return ifcfgMACPattern
+	case strings.HasPrefix(file, "/etc/NetworkManager/system-connections/"):
+		return keyfileMACPattern
+	case strings.HasPrefix(file, "/etc/netplan/"):
+		return netplanMACPattern
+	case file == "/etc/network/interfaces":
+		return ifupdownMACPattern
+	case strings.HasPrefix(file, "/etc/systemd/network/"):
+		return networkdMACPattern
+	case strings.HasPrefix(file, "/etc/udev/rules.d/"):
+		return udevMACPattern
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package checks
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// Catalog maps message IDs to message templates in one language
+// Templates refer to message arguments by name in braces, e.g. "found {count} Raw Device Mapping disk(s)"
+type Catalog map[string]string
+
+// DefaultLanguage is the language of CheckResult.Message
+const DefaultLanguage = "en"
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = map[string]Catalog{DefaultLanguage: defaultCatalog}
+)
+
+// RegisterCatalog registers the message catalog of a language, replacing any catalog registered for it
+// Messages missing from the catalog are rendered in the default language
+func RegisterCatalog(language string, catalog Catalog) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+	catalogs[language] = catalog
+}
+
+// DefaultCatalog returns the messages of the built-in checks in the default language
+// The result is a copy and can be used as a starting point for translations
+func DefaultCatalog() Catalog {
+	return maps.Clone(defaultCatalog)
+}
+
+// Localize renders the message of a result in the given language
+// Results without a message ID, or whose message is not translated, keep their default message
+func Localize(result CheckResult, language string) string {
+	if result.MessageID == "" {
+		return result.Message
+	}
+
+	catalogsMu.RLock()
+	template, found := catalogs[language][result.MessageID]
+	catalogsMu.RUnlock()
+
+	if !found {
+		return result.Message
+	}
+	return renderMessage(template, result.MessageArgs)
+}
+
+// renderMessage replaces the {name} placeholders of a template with the named arguments
+func renderMessage(template string, args map[string]string) string {
+	if len(args) == 0 {
+		return template
+	}
+	replacements := make([]string, 0, 2*len(args))
+	for name, value := range args {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// withMessage sets the message of a result from the default catalog
+// args alternate argument names and values, e.g. withMessage("rdm-disks.found", "count", 2)
+func (r CheckResult) withMessage(id string, args ...any) CheckResult {
+	template, found := defaultCatalog[id]
+	if !found {
+		panic(fmt.Sprintf("message %q is not in the default catalog", id))
+	}
+	r.MessageID = id
+	if len(args) > 0 {
+		r.MessageArgs = make(map[string]string, len(args)/2)
+		for i := 0; i+1 < len(args); i += 2 {
+			r.MessageArgs[fmt.Sprint(args[i])] = fmt.Sprint(args[i+1])
+		}
+	}
+	r.Message = renderMessage(template, r.MessageArgs)
+	return r
+}
+
+// defaultCatalog holds the messages of the built-in checks in the default language
+var defaultCatalog = Catalog{
+	"check.failed": "check could not be performed",
+
+	"antivirus-agents.found": "antivirus/EDR agents found; their driver-level filters may need re-registration or removal after conversion",
+	"antivirus-agents.none":  "no antivirus/EDR agents found",
+
+	"application-blacklist.found": "found {count} blacklisted application(s)",
+	"application-blacklist.none":  "no blacklisted applications found",
+
+	"cloud-init-datasources.default-datasources":   "cloud-init is installed with the default datasource detection",
+	"cloud-init-datasources.no-vmware-datasources": "cloud-init is installed without VMware datasources",
+	"cloud-init-datasources.not-installed":         "cloud-init is not installed",
+	"cloud-init-datasources.vmware-datasources":    "cloud-init has VMware datasources enabled ({datasources}) which could reset networking or hostname on first boot in the target",
+
+	"compute-limits.exceeded":      "VM CPU or memory exceeds target cluster limits",
+	"compute-limits.settings-lost": "VM fits the target cluster limits but hot-add or NUMA settings will be lost",
+	"compute-limits.within-limits": "VM CPU and memory are within target cluster limits",
+
+	"crypttab-device-references.no-crypttab":  "no /etc/crypttab found",
+	"crypttab-device-references.non-portable": "encrypted mappings reference devices that will not be available after conversion",
+	"crypttab-device-references.portable":     "all {count} crypttab mapping(s) use portable references",
+
+	"custom-kernel.custom-kernels":       "guest has custom or vendor kernels that may lack virtio support and are not updated by virt-v2v",
+	"custom-kernel.distribution-kernels": "all kernels are provided by distribution packages",
+	"custom-kernel.no-kernels":           "no Linux kernels to check",
+
+	"disk-limits.exceeded":      "VM disks exceed target platform limits",
+	"disk-limits.within-limits": "{count} disk(s) totaling {total} are within target platform limits",
+
+	"domain-controller.domain-controller":     "guest is an Active Directory domain controller; migrating it from a snapshot risks USN rollback, demote it or follow a DC-safe migration procedure",
+	"domain-controller.not-domain-controller": "guest is not an Active Directory domain controller",
+	"domain-controller.not-windows":           "not a Windows guest",
+
+	"eol-os.eol":       "guest operating system is end-of-life: {systems}",
+	"eol-os.supported": "guest operating system is not end-of-life",
+
+	"filesystem-types.supported":   "all filesystem types are supported",
+	"filesystem-types.unsupported": "guest uses filesystem types the conversion pipeline cannot handle: {types}",
+
+	"free-space.insufficient": "insufficient free space for virt-v2v to install drivers and rebuild the initramfs",
+	"free-space.root-unknown": "could not determine free space on the root filesystem",
+	"free-space.sufficient":   "sufficient free space on root and boot filesystems",
+
+	"fstab-by-path.no-fstab":     "no /etc/fstab found",
+	"fstab-by-path.non-portable": "fstab references devices that will not exist after conversion",
+	"fstab-by-path.portable":     "all {count} fstab entries use portable device references",
+
+	"grub-device-references.no-config":    "no GRUB configuration found",
+	"grub-device-references.non-portable": "bootloader configuration references devices that will not exist after conversion",
+	"grub-device-references.portable":     "no problematic device references found in {count} bootloader file(s)",
+
+	"guest-agents.conflicting": "found {count} guest agent(s) of other platforms that may conflict on the target",
+	"guest-agents.none":        "no cloud or hypervisor guest agents found",
+	"guest-agents.suitable":    "only guest agents suitable for the target are installed",
+
+	"guest-id-mismatch.generic":  "configured guestId \"{guest_id}\" is not specific enough to compare with the detected OS",
+	"guest-id-mismatch.match":    "configured guestId {guest_id} matches the detected OS",
+	"guest-id-mismatch.mismatch": "configured guestId does not match the detected OS; this often indicates template drift and leads to wrong conversion parameters",
+	"guest-id-mismatch.no-os":    "no operating system detected to compare with the configured guestId",
+
+	"hostname.invalid": "names do not follow RFC 1123 / Kubernetes naming rules and must be adjusted for the target",
+	"hostname.valid":   "names follow RFC 1123 / Kubernetes naming rules",
+
+	"independent-disks.found": "found {count} independent disk(s) that are excluded from snapshots; change them to dependent mode before migration",
+	"independent-disks.none":  "no independent disks found",
+
+	"iscsi-initiator.initiator": "guest uses an in-guest iSCSI initiator; its LUNs are not migrated and dependent fstab entries must be reviewed",
+	"iscsi-initiator.none":      "no active iSCSI initiator configuration found",
+
+	"kdump.non-portable":   "kdump configuration will be invalid after migration",
+	"kdump.not-configured": "kdump is not configured in the guest",
+	"kdump.portable":       "kdump configuration is portable",
+
+	"kernel-inventory.needs-review": "found {count} problem(s) that may prevent virt-v2v from updating the default kernel",
+	"kernel-inventory.no-kernels":   "no Linux kernels to check",
+	"kernel-inventory.no-packages":  "no installed kernel packages found; virt-v2v cannot install virtio drivers",
+	"kernel-inventory.updatable":    "found {count} installed kernel(s) that virt-v2v can update",
+
+	"luks-encryption.data-encrypted": "found {count} LUKS-encrypted device(s), none used for root or boot",
+	"luks-encryption.none":           "no LUKS-encrypted devices found",
+	"luks-encryption.root-encrypted": "root or boot volume is LUKS-encrypted; keys must be provided for conversion",
+
+	"lvm.available":     "all {count} logical volume(s) are available",
+	"lvm.missing-pvs":   "volume group(s) {volume_groups} have missing physical volumes or span disks not included in the snapshot",
+	"lvm.none":          "no LVM logical volumes found",
+	"lvm.thin-metadata": "thin pool(s) {pools} have metadata usage above {percent}%, a full metadata volume makes the pool read-only",
+
+	"mac-pinned-network.none":   "no MAC-pinned network configuration found",
+	"mac-pinned-network.pinned": "network interfaces are pinned to MAC addresses and will lose connectivity if the target assigns new MACs",
+
+	"multipath.found": "mountpoints use dm-multipath devices that will not exist after conversion",
+	"multipath.none":  "no multipath devices found in mountpoints",
+
+	"nvme-controller.bus-change":     "the NVMe to virtio disk bus change may break the guest configuration; disks are renamed from nvme0n1 to vda/sda",
+	"nvme-controller.no-controllers": "VM has no NVMe controllers",
+	"nvme-controller.supported":      "guest supports the target virtio disk bus and does not reference NVMe device names",
+
+	"partition-table.compatible":        "partition tables are compatible with {firmware} firmware",
+	"partition-table.conflict":          "partition table scheme conflicts with {firmware} firmware",
+	"partition-table.possible-conflict": "partition table scheme may conflict with {firmware} firmware",
+
+	"passthrough-devices.found": "found {count} host device(s) that cannot be carried to the target automatically",
+	"passthrough-devices.none":  "no USB, PCI passthrough or SR-IOV devices found",
+
+	"policy.satisfied": "policy \"{policy}\" is satisfied",
+	"policy.violated":  "policy \"{policy}\" is violated",
+
+	"pvscsi-driver.no-controllers": "VM has no VMware paravirtual SCSI controllers",
+	"pvscsi-driver.supported":      "guest has drivers for the target virtio-scsi controller",
+	"pvscsi-driver.unsupported":    "the PVSCSI to virtio-scsi controller change may leave the guest unbootable: {reasons}",
+
+	"rdm-disks.found": "found {count} Raw Device Mapping disk(s) that cannot be snapshotted or migrated via VDDK",
+	"rdm-disks.none":  "no Raw Device Mapping disks found",
+
+	"root-filesystem.found":    "root filesystem identified on {root}",
+	"root-filesystem.multiple": "multiple root filesystems found ({roots}); virt-v2v requires a single root",
+	"root-filesystem.none":     "no root filesystem could be identified",
+
+	"secure-boot.disabled": "Secure Boot is not enabled on the source VM",
+	"secure-boot.signed":   "Secure Boot is enabled and the guest has signed boot components",
+	"secure-boot.unsigned": "Secure Boot is enabled but must be disabled after migration: {reasons}",
+
+	"selinux-relabel.custom-modules": "SELinux is enforcing with custom policy modules; an autorelabel will be required after conversion and custom labels should be verified",
+	"selinux-relabel.disabled":       "SELinux is disabled; no relabel required",
+	"selinux-relabel.not-configured": "SELinux is not configured in the guest",
+	"selinux-relabel.relabel":        "SELinux is {mode}; an autorelabel will be required after conversion",
+
+	"shared-disks.found": "VM uses shared disks, likely as part of a cluster; shared-disk clusters cannot be migrated one VM at a time",
+	"shared-disks.none":  "no shared disks found",
+
+	"snapshot-chain.consolidation-needed": "VM disks need consolidation; consolidate the snapshots before migration",
+	"snapshot-chain.long-chain":           "long snapshot delta chain will make inspection and conversion slow",
+	"snapshot-chain.within-limits":        "snapshot chain is within limits",
+
+	"supported-os.supported":   "guest operating system is supported",
+	"supported-os.unsupported": "unsupported guest operating system: {systems}",
+
+	"suspended-vm.not-suspended": "VM is not suspended",
+	"suspended-vm.power-state":   "VM power state is {state}",
+	"suspended-vm.suspended":     "VM is suspended and its memory state cannot be migrated; resume it or shut it down cleanly before conversion",
+
+	"swap-device-references.no-swap":      "no swap entries found in /etc/fstab",
+	"swap-device-references.non-portable": "swap configuration references devices that will not exist after conversion",
+	"swap-device-references.portable":     "all {count} swap entries use portable device references",
+
+	"target-storage.insufficient":     "insufficient target storage: {required} required, {available} available",
+	"target-storage.sufficient":       "target storage is sufficient: {required} required, {available} available",
+	"target-storage.unknown-capacity": "disk capacity is unknown; cannot estimate required target storage",
+
+	"uefi-esp.bios":             "VM boots with BIOS firmware",
+	"uefi-esp.esp-found":        "VM boots with UEFI and has an EFI System Partition",
+	"uefi-esp.no-esp":           "VM boots with UEFI but no EFI System Partition was found; the guest will not boot on the target",
+	"uefi-esp.unknown-firmware": "could not determine VM firmware type (\"{firmware}\")",
+
+	"vgpu.found": "found {count} vGPU or GPU passthrough device(s); GPU resources must be re-planned on the target platform",
+	"vgpu.none":  "no vGPU or GPU passthrough devices found",
+
+	"virtio-initramfs.missing-modules": "initramfs lacks virtio modules and will need dracut regeneration during conversion",
+	"virtio-initramfs.modules-present": "all {count} initramfs image(s) contain virtio modules",
+	"virtio-initramfs.no-images":       "no initramfs images found",
+
+	"virtio-kernel.invalid-min-version": "invalid minimum kernel version \"{version}\"",
+	"virtio-kernel.no-kernels":          "no Linux kernels to check",
+	"virtio-kernel.supported":           "guest kernel supports virtio",
+	"virtio-kernel.too-old":             "guest kernel predates virtio driver availability: {kernels}",
+
+	"vm-name.exists":  "a VM named \"{name}\" already exists in the target namespace",
+	"vm-name.invalid": "VM name does not follow Kubernetes naming rules and must be changed for the target",
+	"vm-name.valid":   "VM name follows Kubernetes naming rules",
+
+	"vmware-services.found": "found {count} enabled VMware-dependent service(s) that should be disabled or removed after conversion",
+	"vmware-services.none":  "no enabled VMware-dependent services found",
+
+	"vmxnet3-driver.supported":   "guest has drivers for the target virtio-net NIC",
+	"vmxnet3-driver.unsupported": "guest may not work with the target virtio-net NIC; review vmxnet3-specific settings and driver support",
+
+	"vsphere-cbt.disabled": "Changed Block Tracking is not enabled for the VM and all disks; warm migration and incremental copies will not be possible",
+	"vsphere-cbt.enabled":  "Changed Block Tracking is enabled for the VM and all disks",
+
+	"vsphere-fault-tolerance.configured":     "Fault Tolerance is configured for the VM and prevents snapshots; turn off Fault Tolerance before migration",
+	"vsphere-fault-tolerance.not-configured": "Fault Tolerance is not configured",
+
+	"windows-dynamic-disks.found": "guest uses Windows dynamic disks, which virt-v2v cannot reliably convert",
+	"windows-dynamic-disks.none":  "no Windows dynamic disks found",
+
+	"xorg-vmware-driver.installed": "VMware display driver is installed but not pinned in the Xorg configuration",
+	"xorg-vmware-driver.none":      "no VMware display driver configuration found",
+	"xorg-vmware-driver.pinned":    "Xorg is configured for the VMware display driver; the graphical console may not start on the target until reconfigured",
+
+	"zfs.none":  "no ZFS filesystems found",
+	"zfs.pools": "guest uses ZFS pools, which virt-v2v cannot reliably convert",
+	"zfs.root":  "guest root filesystem is on ZFS, which virt-v2v cannot reliably convert",
+}
@@ -0,0 +1,162 @@
+package checks
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// placeholderPattern matches the {name} placeholders of message templates
+var placeholderPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// messageCall is a withMessage call of the package
+type messageCall struct {
+	position string
+	id       string
+	args     []string // Argument names
+}
+
+// messageCalls returns the withMessage calls of the package sources
+func messageCalls(t *testing.T) []messageCall {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var calls []messageCall
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || selector.Sel.Name != "withMessage" || len(call.Args) == 0 {
+				return true
+			}
+			position := fset.Position(call.Pos()).String()
+			id, ok := stringLiteral(call.Args[0])
+			if !ok {
+				t.Errorf("%s: message ID is not a string literal", position)
+				return true
+			}
+			found := messageCall{position: position, id: id}
+			for idx := 1; idx < len(call.Args); idx += 2 {
+				name, ok := stringLiteral(call.Args[idx])
+				if !ok {
+					t.Errorf("%s: argument name is not a string literal", position)
+					continue
+				}
+				found.args = append(found.args, name)
+			}
+			if len(call.Args)%2 == 0 {
+				t.Errorf("%s: argument %q has no value", position, found.args[len(found.args)-1])
+			}
+			calls = append(calls, found)
+			return true
+		})
+	}
+	return calls
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	return value, err == nil
+}
+
+func TestMessageCatalogPlaceholders(t *testing.T) {
+	calls := messageCalls(t)
+	if len(calls) == 0 {
+		t.Fatal("no withMessage calls found")
+	}
+	used := make(map[string]bool)
+	for _, call := range calls {
+		used[call.id] = true
+		template, found := defaultCatalog[call.id]
+		if !found {
+			t.Errorf("%s: message %q is not in the default catalog", call.position, call.id)
+			continue
+		}
+		var placeholders []string
+		for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+			placeholders = append(placeholders, match[1])
+		}
+		for _, placeholder := range placeholders {
+			if !slices.Contains(call.args, placeholder) {
+				t.Errorf("%s: message %q is rendered without {%s}", call.position, call.id, placeholder)
+			}
+		}
+		for _, arg := range call.args {
+			if !slices.Contains(placeholders, arg) {
+				t.Errorf("%s: message %q has no placeholder for argument %q", call.position, call.id, arg)
+			}
+		}
+	}
+	for id := range defaultCatalog {
+		if !used[id] {
+			t.Errorf("message %q of the default catalog is never used", id)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	result := CheckResult{Valid: true, Severity: SeverityInfo}.withMessage("fstab-by-path.portable", "count", 3)
+	if result.Message != "all 3 fstab entries use portable device references" {
+		t.Errorf("Message = %q", result.Message)
+	}
+
+	catalog := Catalog{"fstab-by-path.portable": "les {count} entrées fstab sont portables"}
+	RegisterCatalog("fr-test", catalog)
+	if got := Localize(result, "fr-test"); got != "les 3 entrées fstab sont portables" {
+		t.Errorf("Localize(fr-test) = %q", got)
+	}
+	other := CheckResult{}.withMessage("grub-device-references.no-config")
+	if got := Localize(other, "fr-test"); got != other.Message {
+		t.Errorf("Localize() of an untranslated message = %q, want the default message", got)
+	}
+	if got := Localize(CheckResult{Message: "custom"}, "fr-test"); got != "custom" {
+		t.Errorf("Localize() of a result without message ID = %q", got)
+	}
+
+	translations := DefaultCatalog()
+	translations["fstab-by-path.portable"] = "changed"
+	if defaultCatalog["fstab-by-path.portable"] == "changed" {
+		t.Error("DefaultCatalog() returned the default catalog instead of a copy")
+	}
+}
+
+func TestRenderMessage(t *testing.T) {
+	tests := []struct {
+		template string
+		args     map[string]string
+		want     string
+	}{
+		{"found {count} disk(s)", map[string]string{"count": "2"}, "found 2 disk(s)"},
+		{"{a} and {b}", map[string]string{"a": "{b}", "b": "x"}, "{b} and x"},
+		{"no placeholders", nil, "no placeholders"},
+		{"missing {name}", map[string]string{"other": "x"}, "missing {name}"},
+	}
+	for _, tt := range tests {
+		if got := renderMessage(tt.template, tt.args); got != tt.want {
+			t.Errorf("renderMessage(%q, %v) = %q, want %q", tt.template, tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// multipathWWIDPattern matches multipath maps named by WWID when user_friendly_names is disabled
+var multipathWWIDPattern = regexp.MustCompile(`^/dev/mapper/3[0-9a-f]{16,32}(p?\d+)?$`)
+
+// MultipathCheck flags mountpoints on dm-multipath devices
+// Multipath topologies do not carry over to the converted VM, so these mounts will fail
+type MultipathCheck struct{}
+
+// NewMultipathCheck creates a new MultipathCheck
+func NewMultipathCheck() *MultipathCheck {
+	return &MultipathCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *MultipathCheck) ID() string {
+	return "multipath"
+}
+
+// Category returns the category of the check
+func (c *MultipathCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *MultipathCheck) Tags() []string {
+	return []string{"linux"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *MultipathCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *MultipathCheck) Name() string {
+	return "Multipath Devices"
+}
+
+// Describe returns the metadata of the check
+func (c *MultipathCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Flags mountpoints on dm-multipath devices. Multipath topologies do not carry over to the converted VM, so these mounts will fail.", OSFamilyLinux)
+}
+
+// Run inspects the VM snapshot and reports mountpoints on multipath devices
+func (c *MultipathCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks every mountpoint device for multipath naming
+func (c *MultipathCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var details []string
+	for _, guest := range data.Operatingsystems {
+		for _, mp := range guest.Mountpoints.Mountpoint {
+			if isMultipathDevice(mp.Device) {
+				details = append(details, fmt.Sprintf("%s is mounted from multipath device %s", mp.MountPoint, mp.Device))
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("multipath.found")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("multipath.none")
+}
+
+// isMultipathDevice returns true for device names created by dm-multipath
+func isMultipathDevice(device string) bool {
+	return strings.HasPrefix(device, "/dev/mapper/mpath") ||
+		strings.HasPrefix(device, "/dev/disk/by-id/dm-uuid-mpath-") ||
+		strings.HasPrefix(device, "/dev/disk/by-id/dm-name-mpath") ||
+		multipathWWIDPattern.MatchString(strings.ToLower(device))
+}
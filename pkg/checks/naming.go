@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dns1123LabelMaxLength is the maximum length of an RFC 1123 label (and a Kubernetes DNS label name)
+const dns1123LabelMaxLength = 63
+
+// dns1123SubdomainMaxLength is the maximum length of an RFC 1123 subdomain
+const dns1123SubdomainMaxLength = 253
+
+// dns1123LabelProblems returns the reasons why name is not a valid RFC 1123 label as used for Kubernetes names
+func dns1123LabelProblems(name string) []string {
+	var problems []string
+	if name == "" {
+		return []string{"must not be empty"}
+	}
+	if len(name) > dns1123LabelMaxLength {
+		problems = append(problems, fmt.Sprintf("is %d characters long, at most %d allowed", len(name), dns1123LabelMaxLength))
+	}
+	if invalid := invalidLabelChars(name); len(invalid) > 0 {
+		problems = append(problems, fmt.Sprintf("contains invalid characters %s (only lowercase a-z, 0-9 and '-' allowed)", quoteChars(invalid)))
+	}
+	if !isAlphanumeric(name[0]) {
+		problems = append(problems, fmt.Sprintf("must start with a lowercase letter or digit, not %q", name[0]))
+	}
+	if len(name) > 1 && !isAlphanumeric(name[len(name)-1]) {
+		problems = append(problems, fmt.Sprintf("must end with a lowercase letter or digit, not %q", name[len(name)-1]))
+	}
+	return problems
+}
+
+// dns1123SubdomainProblems returns the reasons why name is not a valid RFC 1123 subdomain
+func dns1123SubdomainProblems(name string) []string {
+	if name == "" {
+		return []string{"must not be empty"}
+	}
+	var problems []string
+	if len(name) > dns1123SubdomainMaxLength {
+		problems = append(problems, fmt.Sprintf("is %d characters long, at most %d allowed", len(name), dns1123SubdomainMaxLength))
+	}
+	for _, label := range strings.Split(name, ".") {
+		for _, problem := range dns1123LabelProblems(label) {
+			problems = append(problems, fmt.Sprintf("label %q %s", label, problem))
+		}
+	}
+	return problems
+}
+
+// invalidLabelChars returns the distinct characters not allowed in an RFC 1123 label, in order of appearance
+func invalidLabelChars(name string) []rune {
+	var invalid []rune
+	seen := make(map[rune]struct{})
+	for _, r := range name {
+		if r < 128 && (isAlphanumeric(byte(r)) || r == '-') {
+			continue
+		}
+		if _, ok := seen[r]; !ok {
+			seen[r] = struct{}{}
+			invalid = append(invalid, r)
+		}
+	}
+	return invalid
+}
+
+// isAlphanumeric returns true for lowercase ASCII letters and digits
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// quoteChars formats characters as a comma separated list of quoted characters
+func quoteChars(chars []rune) string {
+	quoted := make([]string, 0, len(chars))
+	for _, r := range chars {
+		quoted = append(quoted, fmt.Sprintf("%q", r))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sanitizeDNS1123Label converts name into a valid RFC 1123 label
+// Invalid characters are replaced by '-', repeated '-' are collapsed and the result is truncated
+// to the maximum label length
+func sanitizeDNS1123Label(name string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, r := range strings.ToLower(name) {
+		if r < 128 && isAlphanumeric(byte(r)) {
+			b.WriteRune(r)
+			lastDash = false
+			continue
+		}
+		if !lastDash {
+			b.WriteByte('-')
+			lastDash = true
+		}
+	}
+	sanitized := strings.Trim(b.String(), "-")
+	if len(sanitized) > dns1123LabelMaxLength {
+		sanitized = strings.TrimRight(sanitized[:dns1123LabelMaxLength], "-")
+	}
+	if sanitized == "" {
+		return "vm"
+	}
+	return sanitized
+}
@@ -0,0 +1,146 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// nvmeDevicePrefix is the kernel name prefix of NVMe block devices (e.g., /dev/nvme0n1p1)
+const nvmeDevicePrefix = "/dev/nvme"
+
+// NVMeCheck detects NVMe controllers and verifies the guest supports the target's virtio disk bus
+// Disks move from nvme0n1 to vda/sda names, breaking configuration that references NVMe device names
+// Controller data comes from the vSphere VM configuration
+type NVMeCheck struct{}
+
+// NewNVMeCheck creates a new NVMeCheck
+func NewNVMeCheck() *NVMeCheck {
+	return &NVMeCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *NVMeCheck) ID() string {
+	return "nvme-controller"
+}
+
+// Category returns the category of the check
+func (c *NVMeCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *NVMeCheck) Tags() []string {
+	return []string{"vsphere-config", "linux", "drivers", "device-names"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *NVMeCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceGuestFilesystem, SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *NVMeCheck) Name() string {
+	return "NVMe Controller Compatibility"
+}
+
+// Describe returns the metadata of the check
+func (c *NVMeCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects NVMe controllers and verifies the guest supports the target's virtio disk bus. Disks move from nvme0n1 to vda/sda names, breaking configuration that references NVMe device names. Controller data comes from the vSphere VM configuration.", OSFamilyLinux)
+}
+
+// Run validates target disk bus support for VMs using NVMe controllers
+func (c *NVMeCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	var controllers []string
+	for _, controller := range config.Controllers {
+		if strings.EqualFold(controller.Type, "nvme") {
+			controllers = append(controllers, controller.Label)
+		}
+	}
+	if len(controllers) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("nvme-controller.no-controllers"), nil
+	}
+
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	files, err := params.readGuestFiles(ctx, append([]string{"/etc/fstab"}, grubConfigPaths...)...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(controllers, data, files), nil
+}
+
+// evaluate checks virtio support and configuration referencing NVMe device names
+func (c *NVMeCheck) evaluate(controllers []string, data *types.VirtInspectorXML, files map[string][]byte) CheckResult {
+	details := []string{fmt.Sprintf("NVMe controllers: %s", strings.Join(controllers, ", "))}
+	var problems []string
+
+	minVersion, _ := parseKernelVersion(DefaultMinVirtioKernel)
+	for _, guest := range data.Operatingsystems {
+		desc := describeOS(guest)
+		if guest.Name != "linux" {
+			// virt-v2v injects virtio-win storage drivers into Windows guests
+			details = append(details, fmt.Sprintf("%s: virtio storage drivers are injected during conversion", desc))
+			continue
+		}
+		supported := false
+		for _, kernel := range installedKernels(guest) {
+			if version, ok := parseKernelVersion(kernel); ok && compareKernelVersions(version, minVersion) >= 0 {
+				supported = true
+			}
+		}
+		if !supported {
+			problems = append(problems, fmt.Sprintf("%s: no kernel with virtio disk support found", desc))
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		scanner := bufio.NewScanner(bytes.NewReader(files[p]))
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "#") || !strings.Contains(line, nvmeDevicePrefix) {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s:%d references an NVMe device name that will change on the target: %s", p, lineNum, line))
+		}
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(problems, details...),
+		}.withMessage("nvme-controller.bus-change")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("nvme-controller.supported")
+}
@@ -0,0 +1,139 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+const (
+	partitionTableGPT = "gpt"
+	partitionTableMBR = "msdos"
+)
+
+// PartitionTableCheck reports the partition table scheme per disk and flags
+// combinations that conflict with the firmware type
+// Firmware comes from the vSphere VM configuration when available, otherwise from virt-v2v-inspector
+type PartitionTableCheck struct{}
+
+// NewPartitionTableCheck creates a new PartitionTableCheck
+func NewPartitionTableCheck() *PartitionTableCheck {
+	return &PartitionTableCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *PartitionTableCheck) ID() string {
+	return "partition-table"
+}
+
+// Category returns the category of the check
+func (c *PartitionTableCheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *PartitionTableCheck) Tags() []string {
+	return []string{"storage", "firmware"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *PartitionTableCheck) Sources() []Source {
+	return []Source{SourceVirtV2vInspector, SourceGuestFilesystem, SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *PartitionTableCheck) Name() string {
+	return "Partition Table Scheme"
+}
+
+// Describe returns the metadata of the check
+func (c *PartitionTableCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Reports the partition table scheme per disk and flags combinations that conflict with the firmware type. Firmware comes from the vSphere VM configuration when available, otherwise from virt-v2v-inspector.")
+}
+
+// Run reads the partition tables and validates them against the firmware type
+func (c *PartitionTableCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	firmware, err := c.firmware(ctx, params)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	tables, err := params.partitionTables(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(firmware, tables), nil
+}
+
+// firmware determines the VM firmware type ("bios" or "uefi")
+func (c *PartitionTableCheck) firmware(ctx context.Context, params InspectionParams) (string, error) {
+	if params.VMConfig != nil && params.VMConfig.Firmware != "" {
+		// vSphere reports UEFI firmware as "efi"
+		if strings.EqualFold(params.VMConfig.Firmware, "efi") {
+			return firmwareUEFI, nil
+		}
+		return strings.ToLower(params.VMConfig.Firmware), nil
+	}
+	v2vData, err := params.inspectWithVirtV2v(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(v2vData.Firmware.Type), nil
+}
+
+// evaluate checks each disk's partition table against the firmware type
+func (c *PartitionTableCheck) evaluate(firmware string, tables []types.PartitionTable) CheckResult {
+	var details []string
+	var conflicts []string
+	var warnings []string
+
+	for _, table := range tables {
+		switch {
+		case table.Type == "":
+			details = append(details, fmt.Sprintf("%s: unpartitioned", table.Device))
+		case table.Type == partitionTableMBR && firmware == firmwareUEFI:
+			conflicts = append(conflicts, fmt.Sprintf("%s: MBR partition table on a UEFI VM", table.Device))
+		case table.Type == partitionTableGPT && firmware == firmwareBIOS:
+			warnings = append(warnings, fmt.Sprintf("%s: GPT partition table on a BIOS VM requires a BIOS boot partition", table.Device))
+		default:
+			details = append(details, fmt.Sprintf("%s: %s", table.Device, describePartitionTable(table.Type)))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   append(append(conflicts, warnings...), details...),
+		}.withMessage("partition-table.conflict", "firmware", firmware)
+	}
+
+	if len(warnings) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(warnings, details...),
+		}.withMessage("partition-table.possible-conflict", "firmware", firmware)
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("partition-table.compatible", "firmware", firmware)
+}
+
+// describePartitionTable returns a human readable partition table name
+func describePartitionTable(tableType string) string {
+	switch tableType {
+	case partitionTableGPT:
+		return "GPT"
+	case partitionTableMBR:
+		return "MBR"
+	}
+	return tableType
+}
@@ -0,0 +1,102 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// Passthrough device types reported in the vSphere VM configuration
+const (
+	deviceTypeUSB   = "usb"
+	deviceTypePCI   = "pci"
+	deviceTypeSRIOV = "sriov"
+	deviceTypeVGPU  = "vgpu"
+)
+
+// PassthroughCheck detects USB devices, PCI passthrough devices and SR-IOV adapters
+// Host devices cannot be carried to the target automatically
+type PassthroughCheck struct{}
+
+// NewPassthroughCheck creates a new PassthroughCheck
+func NewPassthroughCheck() *PassthroughCheck {
+	return &PassthroughCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *PassthroughCheck) ID() string {
+	return "passthrough-devices"
+}
+
+// Category returns the category of the check
+func (c *PassthroughCheck) Category() string {
+	return CategoryPlatform
+}
+
+// Tags returns the tags of the check
+func (c *PassthroughCheck) Tags() []string {
+	return []string{"vsphere-config", "devices"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *PassthroughCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *PassthroughCheck) Name() string {
+	return "USB and PCI Passthrough Devices"
+}
+
+// Describe returns the metadata of the check
+func (c *PassthroughCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Detects USB devices, PCI passthrough devices and SR-IOV adapters. Host devices cannot be carried to the target automatically.")
+}
+
+// Run validates attached host devices from the vSphere configuration
+func (c *PassthroughCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config.Devices), nil
+}
+
+// evaluate reports each attached host device
+func (c *PassthroughCheck) evaluate(devices []types.PassthroughDevice) CheckResult {
+	var details []string
+	for _, device := range devices {
+		var kind string
+		switch device.Type {
+		case deviceTypeUSB:
+			kind = "USB device"
+		case deviceTypePCI:
+			kind = "PCI passthrough device"
+		case deviceTypeSRIOV:
+			kind = "SR-IOV adapter"
+		default:
+			continue
+		}
+		detail := fmt.Sprintf("%s: %s", device.Label, kind)
+		if device.Backing != "" {
+			detail += fmt.Sprintf(" backed by %s", device.Backing)
+		}
+		details = append(details, detail)
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("passthrough-devices.found", "count", len(details))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("passthrough-devices.none")
+}
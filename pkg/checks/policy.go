@@ -0,0 +1,168 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// PolicyEvaluator evaluates a policy expression against the policy input
+// The default evaluator is a CELEvaluator; custom evaluators declare the "inspection" and "vsphere"
+// variables and return the boolean result
+type PolicyEvaluator interface {
+	Evaluate(ctx context.Context, expression string, input map[string]any) (bool, error)
+}
+
+// PolicyCompiler is implemented by evaluators that can validate an expression before it is evaluated
+// NewPolicyCheck compiles the expression of the policy, so invalid expressions fail when policies load
+type PolicyCompiler interface {
+	Compile(expression string) error
+}
+
+// Policy is a user-supplied validation written as a CEL expression
+// The VM passes the policy when the expression evaluates to true, for example:
+//
+//	!inspection.operatingsystems.exists(os, os.applications.applications.exists(a, a.name == "oracle-database"))
+type Policy struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Expression string   `json:"expression"`
+	Message    string   `json:"message"`            // Reported when the expression evaluates to false
+	Severity   Severity `json:"severity,omitempty"` // Severity reported when the expression evaluates to false (defaults to SeverityError)
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// PolicyCheck evaluates a policy against the parsed inspection data and vSphere configuration
+// The policy input has two variables:
+//   - inspection: virt-inspector data in its JSON form (see types.VirtInspectorXML)
+//   - vsphere: the vSphere VM configuration (see types.VMConfig), null if not provided
+type PolicyCheck struct {
+	policy    Policy
+	evaluator PolicyEvaluator
+}
+
+// NewPolicyCheck creates a new PolicyCheck
+// evaluator defaults to a shared CELEvaluator when nil. The expression is compiled and type-checked if the
+// evaluator implements PolicyCompiler
+func NewPolicyCheck(policy Policy, evaluator PolicyEvaluator) (*PolicyCheck, error) {
+	if policy.ID == "" {
+		return nil, fmt.Errorf("policy ID is required")
+	}
+	if policy.Expression == "" {
+		return nil, fmt.Errorf("policy %q has no expression", policy.ID)
+	}
+	if evaluator == nil {
+		celEvaluator, err := defaultPolicyEvaluator()
+		if err != nil {
+			return nil, err
+		}
+		evaluator = celEvaluator
+	}
+	if compiler, ok := evaluator.(PolicyCompiler); ok {
+		if err := compiler.Compile(policy.Expression); err != nil {
+			return nil, fmt.Errorf("policy %q: %w", policy.ID, err)
+		}
+	}
+	if policy.Severity == "" {
+		policy.Severity = SeverityError
+	}
+	if policy.Name == "" {
+		policy.Name = policy.ID
+	}
+	return &PolicyCheck{
+		policy:    policy,
+		evaluator: evaluator,
+	}, nil
+}
+
+// ID returns the stable identifier of the check
+func (c *PolicyCheck) ID() string {
+	return c.policy.ID
+}
+
+// Category returns the category of the check
+func (c *PolicyCheck) Category() string {
+	return CategoryPolicy
+}
+
+// Tags returns the tags of the check
+func (c *PolicyCheck) Tags() []string {
+	return c.policy.Tags
+}
+
+// Sources returns the inspection sources the check needs
+func (c *PolicyCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *PolicyCheck) Name() string {
+	return c.policy.Name
+}
+
+// Describe returns the metadata of the check
+func (c *PolicyCheck) Describe() Metadata {
+	return describe(c, c.policy.Severity, c.policy.Message)
+}
+
+// Run inspects the VM snapshot and evaluates the policy expression
+func (c *PolicyCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	input, err := policyInput(data, params.VMConfig)
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	passed, err := c.evaluator.Evaluate(ctx, c.policy.Expression, input)
+	if err != nil {
+		return CheckResult{}, fmt.Errorf("policy evaluation failed: %w", err)
+	}
+	return c.evaluate(passed), nil
+}
+
+// evaluate builds the result from the expression outcome
+func (c *PolicyCheck) evaluate(passed bool) CheckResult {
+	if !passed {
+		result := CheckResult{
+			CheckName: c.Name(),
+			Valid:     c.policy.Severity != SeverityError,
+			Severity:  c.policy.Severity,
+			Details:   []string{fmt.Sprintf("expression: %s", c.policy.Expression)},
+		}
+		// A user-supplied message has no message ID as it cannot be translated by the catalog
+		if c.policy.Message != "" {
+			result.Message = c.policy.Message
+			return result
+		}
+		return result.withMessage("policy.violated", "policy", c.policy.ID)
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("policy.satisfied", "policy", c.policy.ID)
+}
+
+// policyInput converts the inspection data and vSphere configuration into policy variables
+// Values are converted through JSON so expressions see plain maps, lists and scalars
+func policyInput(data *types.VirtInspectorXML, config *types.VMConfig) (map[string]any, error) {
+	input := make(map[string]any, 2)
+	for name, value := range map[string]any{"inspection": data, "vsphere": config} {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode policy input %q: %w", name, err)
+		}
+		var converted any
+		if err := json.Unmarshal(raw, &converted); err != nil {
+			return nil, fmt.Errorf("failed to decode policy input %q: %w", name, err)
+		}
+		input[name] = converted
+	}
+	return input, nil
+}
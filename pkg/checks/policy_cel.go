@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+)
+
+// celInterruptCheckFrequency is how many comprehension iterations run between checks of the evaluation context
+const celInterruptCheckFrequency = 100
+
+// CELEvaluator evaluates policy expressions with cel-go
+// The "inspection" and "vsphere" variables are declared as dynamic values; expressions must evaluate to a
+// boolean. Compiled programs are cached by expression, so an evaluator can be shared by all policy checks
+type CELEvaluator struct {
+	env      *cel.Env
+	programs sync.Map // expression -> cel.Program
+}
+
+// defaultPolicyEvaluator returns the CEL evaluator used by policy checks without an evaluator
+var defaultPolicyEvaluator = sync.OnceValues(NewCELEvaluator)
+
+// NewCELEvaluator creates a CEL evaluator declaring the policy variables
+func NewCELEvaluator() (*CELEvaluator, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("inspection", cel.DynType),
+		cel.Variable("vsphere", cel.DynType),
+		// JSON numbers are doubles, compare them with integer literals such as vsphere.NumCPUs > 4
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	return &CELEvaluator{env: env}, nil
+}
+
+// Compile parses and type-checks expression, which must evaluate to a boolean
+func (e *CELEvaluator) Compile(expression string) error {
+	_, err := e.program(expression)
+	return err
+}
+
+// Evaluate evaluates expression against the policy input
+// Evaluation stops when ctx is done
+func (e *CELEvaluator) Evaluate(ctx context.Context, expression string, input map[string]any) (bool, error) {
+	program, err := e.program(expression)
+	if err != nil {
+		return false, err
+	}
+	value, _, err := program.ContextEval(ctx, input)
+	if err != nil {
+		return false, err
+	}
+	passed, ok := value.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %s, expected a boolean", value.Type().TypeName())
+	}
+	return passed, nil
+}
+
+// program returns the compiled program of expression
+func (e *CELEvaluator) program(expression string) (cel.Program, error) {
+	if program, found := e.programs.Load(expression); found {
+		return program.(cel.Program), nil
+	}
+	ast, issues := e.env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression: %w", issues.Err())
+	}
+	if output := ast.OutputType(); !output.IsExactType(types.BoolType) && !output.IsExactType(types.DynType) {
+		return nil, fmt.Errorf("invalid expression: evaluates to %s, expected a boolean", output)
+	}
+	program, err := e.env.Program(ast, cel.InterruptCheckFrequency(celInterruptCheckFrequency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	e.programs.Store(expression, program)
+	return program, nil
+}
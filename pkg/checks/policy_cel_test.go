@@ -0,0 +1,61 @@
+package checks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+func TestCELEvaluator(t *testing.T) {
+	evaluator, err := NewCELEvaluator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := policyInput(&types.VirtInspectorXML{
+		Operatingsystems: []types.VirtInspectorOS{{Name: "linux", Distro: "rhel", MajorVersion: "9"}},
+	}, &types.VMConfig{NumCPUs: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+		wantErr    string
+	}{
+		{expression: `inspection.operatingsystems.exists(os, os.distro == "rhel")`, want: true},
+		{expression: `inspection.operatingsystems.all(os, int(os.major_version) >= 8)`, want: true},
+		{expression: `vsphere.NumCPUs > 16`, want: false},
+		{expression: `"rhel"`, wantErr: "expected a boolean"},
+		{expression: `inspection.operatingsystems.exists(`, wantErr: "invalid expression"},
+		{expression: `unknown == 1`, wantErr: "undeclared reference"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := evaluator.Evaluate(context.Background(), tt.expression, input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Evaluate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPolicyCheckCompilesExpression(t *testing.T) {
+	if _, err := NewPolicyCheck(Policy{ID: "bad", Expression: `inspection.`}, nil); err == nil {
+		t.Fatal("NewPolicyCheck() accepted an invalid expression")
+	}
+	if _, err := NewPolicyCheck(Policy{ID: "good", Expression: `vsphere == null`}, nil); err != nil {
+		t.Fatalf("NewPolicyCheck() error = %v", err)
+	}
+}
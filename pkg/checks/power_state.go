@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// PowerStateCheck flags suspended VMs
+// The memory state of a suspended VM cannot be migrated
+type PowerStateCheck struct{}
+
+// NewPowerStateCheck creates a new PowerStateCheck
+func NewPowerStateCheck() *PowerStateCheck {
+	return &PowerStateCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *PowerStateCheck) ID() string {
+	return "suspended-vm"
+}
+
+// Category returns the category of the check
+func (c *PowerStateCheck) Category() string {
+	return CategoryPlatform
+}
+
+// Tags returns the tags of the check
+func (c *PowerStateCheck) Tags() []string {
+	return []string{"vsphere-config"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *PowerStateCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *PowerStateCheck) Name() string {
+	return "Suspended VM State"
+}
+
+// Describe returns the metadata of the check
+func (c *PowerStateCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Flags suspended VMs. The memory state of a suspended VM cannot be migrated.")
+}
+
+// Run validates the power state from the vSphere configuration
+func (c *PowerStateCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config), nil
+}
+
+// evaluate reports a suspended power state
+func (c *PowerStateCheck) evaluate(config *types.VMConfig) CheckResult {
+	if config.PowerState == "suspended" {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+		}.withMessage("suspended-vm.suspended")
+	}
+
+	result := CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}
+	if config.PowerState == "" {
+		return result.withMessage("suspended-vm.not-suspended")
+	}
+	return result.withMessage("suspended-vm.power-state", "state", config.PowerState)
+}
@@ -0,0 +1,40 @@
+package checks
+
+// Phase identifies a stage of a validation run
+type Phase string
+
+const (
+	// PhaseSnapshotInfo is reported once the snapshot disk info of the VM is available
+	PhaseSnapshotInfo Phase = "snapshot-info"
+	// PhaseInspectionStarted is reported before an inspection source runs
+	// NBD sessions to the snapshot disks are opened as part of each inspection
+	PhaseInspectionStarted Phase = "inspection-started"
+	// PhaseInspectionCompleted is reported after an inspection source finished, successfully or not
+	PhaseInspectionCompleted Phase = "inspection-completed"
+	// PhaseCheckCompleted is reported after each check finished
+	PhaseCheckCompleted Phase = "check-completed"
+)
+
+// Progress describes a phase transition of a validation run
+type Progress struct {
+	Phase     Phase
+	Source    Source // Inspection source, for inspection phases
+	CheckID   string // Completed check, for PhaseCheckCompleted
+	Completed int    // Number of completed inspections or checks
+	Total     int    // Total number of inspections or checks
+	Err       error  // Inspection or check error, if any
+}
+
+// ProgressReporter receives progress of a validation run
+// ReportProgress is called synchronously from CheckRunner.Run and should return quickly
+type ProgressReporter interface {
+	ReportProgress(progress Progress)
+}
+
+// ProgressFunc adapts a function to a ProgressReporter
+type ProgressFunc func(progress Progress)
+
+// ReportProgress calls f(progress)
+func (f ProgressFunc) ReportProgress(progress Progress) {
+	f(progress)
+}
@@ -0,0 +1,141 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// minVirtioSCSIKernel is the first upstream kernel shipping the virtio_scsi driver
+const minVirtioSCSIKernel = "3.4"
+
+// rhelFamilyDistros lists distributions that backported virtio_scsi to their 2.6.32 kernels (6.4 and later)
+var rhelFamilyDistros = []string{"rhel", "centos", "oraclelinux", "scientificlinux"}
+
+// PVSCSICheck detects VMware paravirtual SCSI controllers and verifies the guest
+// has drivers for the target's virtio-scsi controller
+// Controller data comes from the vSphere VM configuration
+type PVSCSICheck struct{}
+
+// NewPVSCSICheck creates a new PVSCSICheck
+func NewPVSCSICheck() *PVSCSICheck {
+	return &PVSCSICheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *PVSCSICheck) ID() string {
+	return "pvscsi-driver"
+}
+
+// Category returns the category of the check
+func (c *PVSCSICheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *PVSCSICheck) Tags() []string {
+	return []string{"vsphere-config", "linux", "drivers"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *PVSCSICheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *PVSCSICheck) Name() string {
+	return "Paravirtual SCSI Driver Availability"
+}
+
+// Describe returns the metadata of the check
+func (c *PVSCSICheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects VMware paravirtual SCSI controllers and verifies the guest has drivers for the target's virtio-scsi controller. Controller data comes from the vSphere VM configuration.", OSFamilyLinux)
+}
+
+// Run validates virtio-scsi driver availability for VMs using PVSCSI controllers
+func (c *PVSCSICheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	var controllers []string
+	for _, controller := range config.Controllers {
+		if strings.EqualFold(controller.Type, "pvscsi") {
+			controllers = append(controllers, controller.Label)
+		}
+	}
+	if len(controllers) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("pvscsi-driver.no-controllers"), nil
+	}
+
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(controllers, data), nil
+}
+
+// evaluate checks each Linux guest for virtio-scsi support
+func (c *PVSCSICheck) evaluate(controllers []string, data *types.VirtInspectorXML) CheckResult {
+	details := []string{fmt.Sprintf("PVSCSI controllers: %s", strings.Join(controllers, ", "))}
+	var unsupported []string
+
+	for _, guest := range data.Operatingsystems {
+		desc := describeOS(guest)
+		if guest.Name != "linux" {
+			// virt-v2v injects virtio-win storage drivers into Windows guests
+			details = append(details, fmt.Sprintf("%s: virtio storage drivers are injected during conversion", desc))
+			continue
+		}
+		if supportsVirtioSCSI(guest) {
+			details = append(details, fmt.Sprintf("%s: kernel supports virtio-scsi", desc))
+			continue
+		}
+		unsupported = append(unsupported, desc)
+		details = append(details, fmt.Sprintf("%s: no kernel with virtio-scsi support found", desc))
+	}
+
+	if len(unsupported) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("pvscsi-driver.unsupported", "reasons", strings.Join(unsupported, ", "))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("pvscsi-driver.supported")
+}
+
+// supportsVirtioSCSI returns true if the Linux guest has a kernel with the virtio_scsi driver
+func supportsVirtioSCSI(guest types.VirtInspectorOS) bool {
+	for _, distro := range rhelFamilyDistros {
+		if !strings.EqualFold(guest.Distro, distro) {
+			continue
+		}
+		major, minor, err := parseOSVersion(guest)
+		if err == nil && (major > 6 || (major == 6 && minor >= 4)) {
+			return true
+		}
+	}
+
+	minVersion, _ := parseKernelVersion(minVirtioSCSIKernel)
+	for _, kernel := range installedKernels(guest) {
+		if version, ok := parseKernelVersion(kernel); ok && compareKernelVersions(version, minVersion) >= 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// RDMCheck detects Raw Device Mapping disks in the vSphere VM configuration
+// RDMs cannot be snapshotted or read via VDDK and are therefore not migrated
+type RDMCheck struct{}
+
+// NewRDMCheck creates a new RDMCheck
+func NewRDMCheck() *RDMCheck {
+	return &RDMCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *RDMCheck) ID() string {
+	return "rdm-disks"
+}
+
+// Category returns the category of the check
+func (c *RDMCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *RDMCheck) Tags() []string {
+	return []string{"vsphere-config"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *RDMCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *RDMCheck) Name() string {
+	return "Raw Device Mapping Disks"
+}
+
+// Describe returns the metadata of the check
+func (c *RDMCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Detects Raw Device Mapping disks in the vSphere VM configuration. RDMs cannot be snapshotted or read via VDDK and are therefore not migrated.")
+}
+
+// Run validates the VM disks from the vSphere configuration
+func (c *RDMCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config.Disks), nil
+}
+
+// evaluate reports disks backed by Raw Device Mappings
+func (c *RDMCheck) evaluate(disks []types.VirtualDisk) CheckResult {
+	var details []string
+	for _, disk := range disks {
+		if disk.RDMMode == "" {
+			continue
+		}
+		detail := fmt.Sprintf("%s (%s) is a Raw Device Mapping in %s", disk.Label, disk.FileName, disk.RDMMode)
+		if disk.RDMDevice != "" {
+			detail += fmt.Sprintf(" backed by %s", disk.RDMDevice)
+		}
+		details = append(details, detail)
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("rdm-disks.found", "count", len(details))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("rdm-disks.none")
+}
@@ -0,0 +1,190 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Registration describes a check available in the registry
+type Registration struct {
+	ID   string                // Stable identifier (e.g., "fstab-by-path")
+	Name string                // Human readable name
+	New  func() (Check, error) // Creates the check with its default settings
+
+	// Configure creates the check with parameters from configuration (nil if the check has no parameters)
+	Configure func(params json.RawMessage) (Check, error)
+}
+
+var (
+	registryMu    sync.RWMutex
+	registry      = make(map[string]Registration)
+	registryOrder []string
+)
+
+func init() {
+	builtins := []Registration{
+		builtin(func() Check { return NewFstabCheck() }),
+		builtin(func() Check { return NewLUKSCheck() }),
+		builtin(func() Check { return NewLVMCheck() }),
+		configurable(func(p supportedOSParams) (Check, error) { return NewSupportedOSCheck(p.Matrix), nil }),
+		configurable(func(p eolParams) (Check, error) { return NewEOLCheck(p.Table), nil }),
+		builtin(func() Check { return NewUEFICheck() }),
+		builtin(func() Check { return NewSecureBootCheck() }),
+		builtin(func() Check { return NewGrubCheck() }),
+		configurable(func(p virtioKernelParams) (Check, error) { return NewVirtioKernelCheck(p.MinVersion), nil }),
+		builtin(func() Check { return NewVirtioInitramfsCheck() }),
+		builtin(func() Check { return NewMACNetworkCheck() }),
+		builtin(func() Check { return NewMultipathCheck() }),
+		builtin(func() Check { return NewISCSICheck() }),
+		builtin(func() Check { return NewSwapCheck() }),
+		builtin(func() Check { return NewCrypttabCheck() }),
+		builtin(func() Check { return NewZFSCheck() }),
+		configurable(func(p filesystemTypeParams) (Check, error) { return NewFilesystemTypeCheck(p.Allowed, p.Severity), nil }),
+		configurable(func(p freeSpaceParams) (Check, error) {
+			return NewFreeSpaceCheck(p.MinRootFreeBytes, p.MinBootFreeBytes), nil
+		}),
+		configurable(func(p DiskLimits) (Check, error) { return NewDiskLimitsCheck(p), nil }),
+		builtin(func() Check { return NewPartitionTableCheck() }),
+		builtin(func() Check { return NewDynamicDisksCheck() }),
+		builtin(func() Check { return NewDomainControllerCheck() }),
+		configurable(func(p antivirusParams) (Check, error) { return NewAntivirusCheck(p.Products), nil }),
+		configurable(func(p applicationBlacklistParams) (Check, error) {
+			return NewApplicationBlacklistCheck(p.Patterns, p.Severity)
+		}),
+		configurable(func(p hostnameParams) (Check, error) { return NewHostnameCheck(p.CheckVMName), nil }),
+		builtin(func() Check { return NewSELinuxCheck() }),
+		builtin(func() Check { return NewRootFilesystemCheck() }),
+		builtin(func() Check { return NewPVSCSICheck() }),
+		builtin(func() Check { return NewXorgDriverCheck() }),
+		builtin(func() Check { return NewCustomKernelCheck() }),
+		builtin(func() Check { return NewKdumpCheck() }),
+		builtin(func() Check { return NewCloudInitCheck() }),
+		builtin(func() Check { return NewVMwareServicesCheck() }),
+		configurable(func(p guestAgentsParams) (Check, error) { return NewGuestAgentsCheck(p.Agents), nil }),
+		configurable(func(p kernelInventoryParams) (Check, error) { return NewKernelInventoryCheck(p.MinBootFreeBytes), nil }),
+		builtin(func() Check { return NewRDMCheck() }),
+		builtin(func() Check { return NewIndependentDisksCheck() }),
+		builtin(func() Check { return NewSharedDisksCheck() }),
+		builtin(func() Check { return NewCBTCheck() }),
+		builtin(func() Check { return NewPassthroughCheck() }),
+		builtin(func() Check { return NewVGPUCheck() }),
+		builtin(func() Check { return NewFaultToleranceCheck() }),
+		builtin(func() Check { return NewPowerStateCheck() }),
+		configurable(func(p snapshotChainParams) (Check, error) { return NewSnapshotChainCheck(p.MaxDepth, p.MaxBytes), nil }),
+		builtin(func() Check { return NewGuestIDCheck() }),
+		builtin(func() Check { return NewNVMeCheck() }),
+		builtin(func() Check { return NewVMXNET3Check() }),
+		configurable(func(p vmNameParams) (Check, error) { return NewVMNameCheck(p.ExistingNames), nil }),
+		configurable(func(p ComputeLimits) (Check, error) { return NewComputeLimitsCheck(p), nil }),
+		configurable(func(p TargetStorage) (Check, error) { return NewTargetStorageCheck(p), nil }),
+	}
+	for _, reg := range builtins {
+		if err := Register(reg); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// builtin creates a registration for a built-in check whose constructor cannot fail
+func builtin(newCheck func() Check) Registration {
+	check := newCheck()
+	return Registration{
+		ID:   check.ID(),
+		Name: check.Name(),
+		New: func() (Check, error) {
+			return newCheck(), nil
+		},
+	}
+}
+
+// configurable creates a registration for a built-in check with parameters
+// The zero value of the parameters selects the check's default settings
+func configurable[P any](newCheck func(P) (Check, error)) Registration {
+	var defaults P
+	check, err := newCheck(defaults)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create built-in check: %v", err))
+	}
+	return Registration{
+		ID:   check.ID(),
+		Name: check.Name(),
+		New: func() (Check, error) {
+			var params P
+			return newCheck(params)
+		},
+		Configure: func(raw json.RawMessage) (Check, error) {
+			var params P
+			if err := decodeParams(raw, &params); err != nil {
+				return nil, err
+			}
+			return newCheck(params)
+		},
+	}
+}
+
+// Register adds a check to the registry
+// Returns an error if the registration is incomplete or the ID is already registered
+func Register(reg Registration) error {
+	if reg.ID == "" {
+		return fmt.Errorf("check ID is required")
+	}
+	if reg.New == nil {
+		return fmt.Errorf("check %q has no constructor", reg.ID)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[reg.ID]; exists {
+		return fmt.Errorf("check %q is already registered", reg.ID)
+	}
+	registry[reg.ID] = reg
+	registryOrder = append(registryOrder, reg.ID)
+	return nil
+}
+
+// Lookup returns the registration of the check with the given ID
+func Lookup(id string) (Registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	reg, found := registry[id]
+	return reg, found
+}
+
+// Registrations returns all registered checks in registration order
+func Registrations() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	regs := make([]Registration, 0, len(registryOrder))
+	for _, id := range registryOrder {
+		regs = append(regs, registry[id])
+	}
+	return regs
+}
+
+// NewChecks creates the checks with the given IDs using their default settings
+// All registered checks are created if no IDs are given
+func NewChecks(ids ...string) ([]Check, error) {
+	if len(ids) == 0 {
+		for _, reg := range Registrations() {
+			ids = append(ids, reg.ID)
+		}
+	}
+
+	checks := make([]Check, 0, len(ids))
+	for _, id := range ids {
+		reg, found := Lookup(id)
+		if !found {
+			return nil, fmt.Errorf("unknown check %q", id)
+		}
+		check, err := reg.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create check %q: %w", id, err)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
@@ -0,0 +1,109 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// RootFilesystemCheck fails when inspection cannot identify a single root filesystem
+// virt-v2v requires exactly one identifiable root to convert the guest
+type RootFilesystemCheck struct{}
+
+// NewRootFilesystemCheck creates a new RootFilesystemCheck
+func NewRootFilesystemCheck() *RootFilesystemCheck {
+	return &RootFilesystemCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *RootFilesystemCheck) ID() string {
+	return "root-filesystem"
+}
+
+// Category returns the category of the check
+func (c *RootFilesystemCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *RootFilesystemCheck) Tags() []string {
+	return []string{"linux", "windows", "filesystem"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *RootFilesystemCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *RootFilesystemCheck) Name() string {
+	return "Identifiable Root Filesystem"
+}
+
+// Describe returns the metadata of the check
+func (c *RootFilesystemCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Fails when inspection cannot identify a single root filesystem. virt-v2v requires exactly one identifiable root to convert the guest.", OSFamilyLinux, OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and validates the root filesystem
+func (c *RootFilesystemCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks that exactly one operating system with a root device was detected
+func (c *RootFilesystemCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var roots []string
+	for _, guest := range data.Operatingsystems {
+		if strings.TrimSpace(guest.Root) != "" {
+			roots = append(roots, guest.Root)
+		}
+	}
+
+	switch {
+	case len(roots) == 0:
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   rootDiagnostics(data),
+		}.withMessage("root-filesystem.none")
+	case len(roots) > 1 || len(data.Operatingsystems) > 1:
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   rootDiagnostics(data),
+		}.withMessage("root-filesystem.multiple", "roots", strings.Join(roots, ", "))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("root-filesystem.found", "root", roots[0])
+}
+
+// rootDiagnostics describes the detected operating systems, filesystems and mountpoints
+func rootDiagnostics(data *types.VirtInspectorXML) []string {
+	var details []string
+	for _, guest := range data.Operatingsystems {
+		root := guest.Root
+		if root == "" {
+			root = "<none>"
+		}
+		details = append(details, fmt.Sprintf("operating system %s with root %s", describeOS(guest), root))
+		for _, fs := range guest.Filesystems.Filesystem {
+			details = append(details, fmt.Sprintf("filesystem %s (%s)", fs.Device, fs.Type))
+		}
+		for _, mp := range guest.Mountpoints.Mountpoint {
+			details = append(details, fmt.Sprintf("mountpoint %s on %s", mp.MountPoint, mp.Device))
+		}
+	}
+	return details
+}
@@ -0,0 +1,368 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// CheckRunner runs a set of checks against one VM snapshot
+// virt-inspector and virt-v2v-inspector run at most once per Run and their parsed
+// data is shared by all checks, instead of every check inspecting the VM on its own
+type CheckRunner struct {
+	checks       []Check
+	progress     ProgressReporter
+	timeout      time.Duration
+	suppressions *Suppressions
+	failOn       []Severity
+}
+
+// NewCheckRunner creates a new CheckRunner for the given checks
+func NewCheckRunner(checks []Check) *CheckRunner {
+	return &CheckRunner{
+		checks: checks,
+	}
+}
+
+// WithTags returns a runner restricted to the checks whose category or tags match any of the given tags
+func (r *CheckRunner) WithTags(tags ...string) *CheckRunner {
+	var selected []Check
+	for _, check := range r.checks {
+		if matchesTags(check, tags) {
+			selected = append(selected, check)
+		}
+	}
+	runner := *r
+	runner.checks = selected
+	return &runner
+}
+
+// WithProgress returns a runner reporting the progress of each run to reporter
+func (r *CheckRunner) WithProgress(reporter ProgressReporter) *CheckRunner {
+	runner := *r
+	runner.progress = reporter
+	return &runner
+}
+
+// WithCheckTimeout returns a runner limiting each check to timeout, unless the check has its own
+// configured timeout (see CheckConfig.Timeout)
+// The context of a check is cancelled on expiry and the check is recorded as failed with a timeout error
+func (r *CheckRunner) WithCheckTimeout(timeout time.Duration) *CheckRunner {
+	runner := *r
+	runner.timeout = timeout
+	return &runner
+}
+
+// WithFailOn returns a runner failing validations with results of the given severities
+// (DefaultFailOn if none), e.g. SeverityWarning and SeverityError for pipelines that must not
+// migrate VMs with warnings
+func (r *CheckRunner) WithFailOn(severities ...Severity) *CheckRunner {
+	runner := *r
+	runner.failOn = severities
+	return &runner
+}
+
+// WithSuppressions returns a runner applying suppressions to the findings of each run
+// Downgraded findings stay in the results, hidden findings are moved to ValidationReport.Suppressed
+func (r *CheckRunner) WithSuppressions(suppressions *Suppressions) *CheckRunner {
+	runner := *r
+	runner.suppressions = suppressions
+	return &runner
+}
+
+// Checks returns the checks run by the runner
+func (r *CheckRunner) Checks() []Check {
+	return r.checks
+}
+
+// RequiredSources returns the inspection sources needed by the checks of the runner
+func (r *CheckRunner) RequiredSources() []Source {
+	var sources []Source
+	for _, check := range r.checks {
+		for _, source := range check.Sources() {
+			if !slices.Contains(sources, source) {
+				sources = append(sources, source)
+			}
+		}
+	}
+	return sources
+}
+
+// Plan lists what a CheckRunner would do for a VM
+type Plan struct {
+	Checks  []PlannedCheck `json:"checks"`
+	Sources []Source       `json:"sources"` // Inspection sources the checks need
+}
+
+// PlannedCheck is a check a CheckRunner would run
+type PlannedCheck struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Severity Severity      `json:"default_severity"`
+	Sources  []Source      `json:"sources"`
+	Timeout  time.Duration `json:"timeout,omitempty"` // Zero for no timeout
+}
+
+// DryRun returns the checks the runner would run, in order, with their inspection sources
+// Nothing is inspected and vCenter is not contacted, so this can be used to validate configuration files
+func (r *CheckRunner) DryRun() *Plan {
+	plan := &Plan{
+		Checks:  make([]PlannedCheck, 0, len(r.checks)),
+		Sources: r.RequiredSources(),
+	}
+	for _, check := range r.checks {
+		metadata := check.Describe()
+		plan.Checks = append(plan.Checks, PlannedCheck{
+			ID:       metadata.ID,
+			Name:     metadata.Title,
+			Severity: metadata.DefaultSeverity,
+			Sources:  metadata.Sources,
+			Timeout:  r.checkTimeout(check),
+		})
+	}
+	return plan
+}
+
+// Run runs all checks in order and returns the validation report
+// Only the inspections required by the checks are performed, once, before the checks run
+func (r *CheckRunner) Run(ctx context.Context, params InspectionParams) *ValidationReport {
+	startedAt := time.Now().UTC()
+	shared := &sharedInspection{
+		inspector: params.newInspector(),
+		runCtx:    ctx,
+	}
+	params.shared = shared
+	if params.Inspector == nil {
+		// NBD sessions of an inspector provided by the caller may be reused for other runs
+		defer shared.closeSessions()
+	}
+
+	// Inspection errors are reported by each check that needs the failed source
+	var timings []InspectionTiming
+	if params.DiskInfo != nil {
+		r.report(Progress{Phase: PhaseSnapshotInfo})
+
+		var inspections []Source
+		for _, source := range r.RequiredSources() {
+			if source == SourceVirtInspector || source == SourceVirtV2vInspector {
+				inspections = append(inspections, source)
+			}
+		}
+		for idx, source := range inspections {
+			r.report(Progress{Phase: PhaseInspectionStarted, Source: source, Completed: idx, Total: len(inspections)})
+			timing := InspectionTiming{Source: source, StartedAt: time.Now().UTC()}
+			var err error
+			if source == SourceVirtInspector {
+				_, err = shared.inspectWithVirt(ctx, params)
+			} else {
+				_, err = shared.inspectWithVirtV2v(ctx, params)
+			}
+			timing.Duration = time.Since(timing.StartedAt)
+			if err != nil {
+				timing.Error = err.Error()
+			}
+			timings = append(timings, timing)
+			r.report(Progress{Phase: PhaseInspectionCompleted, Source: source, Completed: idx + 1, Total: len(inspections), Err: err})
+		}
+	}
+
+	results := make([]CheckResult, 0, len(r.checks))
+	for idx, check := range r.checks {
+		if params.Logger != nil {
+			params.Logger.WithFields(logrus.Fields{
+				"vm_name":  params.VMName,
+				"check_id": check.ID(),
+			}).Debug("Running check")
+		}
+		checkStartedAt := time.Now().UTC()
+		result, err := runCheck(ctx, check, params, r.checkTimeout(check), shared)
+		if err != nil {
+			if params.Logger != nil {
+				params.Logger.WithFields(logrus.Fields{
+					"vm_name":  params.VMName,
+					"check_id": check.ID(),
+				}).WithError(err).Warn("Check could not be performed")
+			}
+			result = checkFailed(check.Name(), err)
+		}
+		result.CheckID = check.ID()
+		result.StartedAt = checkStartedAt
+		result.Duration = time.Since(checkStartedAt)
+		results = append(results, result)
+		r.report(Progress{Phase: PhaseCheckCompleted, CheckID: check.ID(), Completed: idx + 1, Total: len(r.checks), Err: err})
+	}
+
+	results, suppressed := r.suppressions.apply(results, startedAt)
+	report := NewValidationReport(params.vmIdentity(), startedAt, results)
+	report.Inspections = timings
+	report.Suppressed = suppressed
+	if params.DiskInfo != nil {
+		// Record the versions of the tools and VDDK reading the snapshot disks for troubleshooting
+		if versions := shared.inspector.ToolVersions(ctx); len(versions) > 0 {
+			report.ToolVersions = versions
+		}
+	}
+	if len(r.failOn) > 0 {
+		report.applyFailOn(r.failOn)
+	}
+	return report
+}
+
+// checkTimeout returns the timeout of a check, zero for no timeout
+func (r *CheckRunner) checkTimeout(check Check) time.Duration {
+	if c, ok := check.(interface{ Timeout() time.Duration }); ok {
+		return c.Timeout()
+	}
+	return r.timeout
+}
+
+// runCheck runs a check with an optional timeout
+// A check that does not return once its context is cancelled is abandoned, so a hanging check cannot
+// block the validation run. Abandoned checks are tracked by shared, which keeps the NBD sessions open
+// until they return
+func runCheck(ctx context.Context, check Check, params InspectionParams, timeout time.Duration, shared *sharedInspection) (CheckResult, error) {
+	if timeout <= 0 {
+		return check.Run(ctx, params)
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		result CheckResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	shared.track(func() {
+		result, err := check.Run(checkCtx, params)
+		done <- outcome{result: result, err: err}
+	})
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-checkCtx.Done():
+		if ctx.Err() != nil {
+			return CheckResult{}, ctx.Err()
+		}
+		return CheckResult{}, fmt.Errorf("check timed out after %s: %w", timeout, checkCtx.Err())
+	}
+}
+
+// report sends progress to the progress reporter of the runner, if any
+func (r *CheckRunner) report(progress Progress) {
+	if r.progress != nil {
+		r.progress.ReportProgress(progress)
+	}
+}
+
+// matchesTags returns true if the category or any tag of the check is one of tags
+func matchesTags(check Check, tags []string) bool {
+	for _, tag := range tags {
+		if check.Category() == tag || slices.Contains(check.Tags(), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedInspection holds inspection results shared by all checks of a CheckRunner run
+type sharedInspection struct {
+	inspector Inspector
+	runCtx    context.Context // Context of the run, shared inspections are not bound to the check starting them
+
+	virt sharedResult[*types.VirtInspectorXML]
+	v2v  sharedResult[*types.VirtV2VInspectorXML]
+
+	tasks   sync.WaitGroup
+	running atomic.Int64
+}
+
+// inspectWithVirt runs virt-inspector on first use and returns the shared result afterwards
+func (s *sharedInspection) inspectWithVirt(ctx context.Context, p InspectionParams) (*types.VirtInspectorXML, error) {
+	return s.virt.get(ctx, s, func(runCtx context.Context) (*types.VirtInspectorXML, error) {
+		return s.inspector.InspectWithVirt(runCtx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo)
+	})
+}
+
+// inspectWithVirtV2v runs virt-v2v-inspector on first use and returns the shared result afterwards
+func (s *sharedInspection) inspectWithVirtV2v(ctx context.Context, p InspectionParams) (*types.VirtV2VInspectorXML, error) {
+	return s.v2v.get(ctx, s, func(runCtx context.Context) (*types.VirtV2VInspectorXML, error) {
+		return s.inspector.InspectWithVirtV2v(runCtx, p.VMName, p.SnapshotName, p.Datacenter, p.DiskInfo, p.SSLVerify)
+	})
+}
+
+// track runs fn in a goroutine that may outlive the check starting it
+func (s *sharedInspection) track(fn func()) {
+	s.running.Add(1)
+	s.tasks.Add(1)
+	go func() {
+		defer s.tasks.Done()
+		defer s.running.Add(-1)
+		fn()
+	}()
+}
+
+// closeSessions closes the NBD sessions of the inspector once no abandoned check or shared inspection
+// uses them; without any, the sessions are closed before returning
+func (s *sharedInspection) closeSessions() {
+	if s.running.Load() == 0 {
+		s.inspector.CloseSessions()
+		return
+	}
+	go func() {
+		s.tasks.Wait()
+		s.inspector.CloseSessions()
+	}()
+}
+
+// sharedResult is the result of an inspection run once for all checks
+type sharedResult[T any] struct {
+	mu   sync.Mutex
+	call *sharedCall[T]
+}
+
+// sharedCall is a run of a shared inspection
+type sharedCall[T any] struct {
+	done  chan struct{} // Closed once value and err are set
+	value T
+	err   error
+}
+
+// get starts inspect with the run context on first use and waits for its result until ctx is done
+// A check timing out stops waiting without stopping the inspection, which other checks still use.
+// Inspections interrupted by their context are not cached, the next check runs them again
+func (r *sharedResult[T]) get(ctx context.Context, shared *sharedInspection, inspect func(context.Context) (T, error)) (T, error) {
+	r.mu.Lock()
+	call := r.call
+	if call == nil {
+		call = &sharedCall[T]{done: make(chan struct{})}
+		r.call = call
+		shared.track(func() {
+			call.value, call.err = inspect(shared.runCtx)
+			if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+				r.mu.Lock()
+				if r.call == call {
+					r.call = nil
+				}
+				r.mu.Unlock()
+			}
+			close(call.done)
+		})
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
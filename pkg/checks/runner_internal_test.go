@@ -0,0 +1,111 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// slowInspector runs virt-inspector for delay and counts its runs and closed sessions
+type slowInspector struct {
+	Inspector // Methods not used by the tests panic
+	delay     time.Duration
+	runs      atomic.Int32
+	closed    atomic.Int32
+	inUse     atomic.Bool
+}
+
+func (i *slowInspector) InspectWithVirt(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) (*types.VirtInspectorXML, error) {
+	i.runs.Add(1)
+	select {
+	case <-time.After(i.delay):
+		return &types.VirtInspectorXML{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (i *slowInspector) CloseSessions() {
+	if i.inUse.Load() {
+		panic("sessions closed while in use")
+	}
+	i.closed.Add(1)
+}
+
+func TestSharedInspectionOutlivesCheckTimeout(t *testing.T) {
+	inspector := &slowInspector{delay: 50 * time.Millisecond}
+	shared := &sharedInspection{inspector: inspector, runCtx: context.Background()}
+	params := InspectionParams{VMName: "vm", DiskInfo: &types.SnapshotDiskInfo{}}
+
+	// The first check gives up waiting, the inspection keeps running for the next check
+	checkCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := shared.inspectWithVirt(checkCtx, params); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("inspectWithVirt() error = %v, want the check deadline", err)
+	}
+	data, err := shared.inspectWithVirt(context.Background(), params)
+	if err != nil || data == nil {
+		t.Fatalf("inspectWithVirt() = %v, %v after a timed out check", data, err)
+	}
+	if runs := inspector.runs.Load(); runs != 1 {
+		t.Errorf("virt-inspector ran %d times, want 1", runs)
+	}
+}
+
+func TestSharedInspectionDoesNotCacheContextErrors(t *testing.T) {
+	inspector := &slowInspector{delay: time.Hour}
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	shared := &sharedInspection{inspector: inspector, runCtx: runCtx}
+	params := InspectionParams{VMName: "vm", DiskInfo: &types.SnapshotDiskInfo{}}
+
+	cancelRun()
+	if _, err := shared.inspectWithVirt(context.Background(), params); !errors.Is(err, context.Canceled) {
+		t.Fatalf("inspectWithVirt() error = %v, want context.Canceled", err)
+	}
+
+	inspector.delay = 0
+	shared.runCtx = context.Background()
+	if _, err := shared.inspectWithVirt(context.Background(), params); err != nil {
+		t.Fatalf("inspectWithVirt() error = %v, the cancelled inspection was cached", err)
+	}
+	if runs := inspector.runs.Load(); runs != 2 {
+		t.Errorf("virt-inspector ran %d times, want 2", runs)
+	}
+}
+
+func TestSharedInspectionClosesSessionsAfterAbandonedChecks(t *testing.T) {
+	inspector := &slowInspector{}
+	shared := &sharedInspection{inspector: inspector, runCtx: context.Background()}
+
+	shared.closeSessions()
+	if closed := inspector.closed.Load(); closed != 1 {
+		t.Fatalf("sessions closed %d times without abandoned checks, want 1", closed)
+	}
+
+	release := make(chan struct{})
+	returned := make(chan struct{})
+	inspector.inUse.Store(true)
+	shared.track(func() {
+		<-release
+		inspector.inUse.Store(false)
+		close(returned)
+	})
+	shared.closeSessions()
+	if closed := inspector.closed.Load(); closed != 1 {
+		t.Fatal("sessions closed while an abandoned check was running")
+	}
+
+	close(release)
+	<-returned
+	deadline := time.Now().Add(5 * time.Second)
+	for inspector.closed.Load() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("sessions not closed after the abandoned check returned")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
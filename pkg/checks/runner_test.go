@@ -0,0 +1,190 @@
+package checks_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/checks"
+	"github.com/nirarg/v2v-vm-validations/pkg/fake"
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// hangingCheck blocks until its context is cancelled
+type hangingCheck struct{}
+
+func (hangingCheck) ID() string               { return "hanging" }
+func (hangingCheck) Name() string             { return "Hanging" }
+func (hangingCheck) Category() string         { return checks.CategoryGuestOS }
+func (hangingCheck) Tags() []string           { return nil }
+func (hangingCheck) Sources() []checks.Source { return nil }
+func (c hangingCheck) Describe() checks.Metadata {
+	return checks.Metadata{ID: c.ID(), Title: c.Name(), DefaultSeverity: checks.SeverityError}
+}
+func (hangingCheck) Run(ctx context.Context, params checks.InspectionParams) (checks.CheckResult, error) {
+	<-ctx.Done()
+	return checks.CheckResult{}, ctx.Err()
+}
+
+// runParams returns the inspection parameters of a VM inspected by inspector
+func runParams(inspector checks.Inspector) checks.InspectionParams {
+	return checks.InspectionParams{
+		VMName:       "rhel9",
+		SnapshotName: "pre-migration",
+		DiskInfo:     &types.SnapshotDiskInfo{DiskPath: "[ds] rhel9/rhel9.vmdk", BaseDiskPath: "[ds] rhel9/rhel9.vmdk"},
+		Inspector:    inspector,
+	}
+}
+
+// resultOf returns the result of the check with id
+func resultOf(t *testing.T, report *checks.ValidationReport, id string) checks.CheckResult {
+	t.Helper()
+	for _, result := range report.Results {
+		if result.CheckID == id {
+			return result
+		}
+	}
+	t.Fatalf("no result for check %s", id)
+	return checks.CheckResult{}
+}
+
+func TestCheckRunnerSharesInspections(t *testing.T) {
+	inspector := fake.RHEL9()
+	runner := checks.NewCheckRunner([]checks.Check{checks.NewLVMCheck(), checks.NewLUKSCheck(), checks.NewSELinuxCheck()})
+
+	report := runner.Run(context.Background(), runParams(inspector))
+	if !report.Passed {
+		t.Errorf("Passed = false for the RHEL 9 fixture: %+v", report.Results)
+	}
+	inspections := 0
+	for _, call := range inspector.Calls() {
+		if call.Method == "InspectWithVirt" {
+			inspections++
+		}
+	}
+	if inspections != 1 {
+		t.Errorf("virt-inspector ran %d times, want 1", inspections)
+	}
+	if len(report.Inspections) != 1 || report.Inspections[0].Source != checks.SourceVirtInspector {
+		t.Errorf("Inspections = %+v, want one virt-inspector run", report.Inspections)
+	}
+}
+
+func TestCheckRunnerTimeout(t *testing.T) {
+	runner := checks.NewCheckRunner([]checks.Check{hangingCheck{}, checks.NewLVMCheck()}).WithCheckTimeout(20 * time.Millisecond)
+
+	startedAt := time.Now()
+	report := runner.Run(context.Background(), runParams(fake.RHEL9()))
+	if elapsed := time.Since(startedAt); elapsed > 5*time.Second {
+		t.Fatalf("Run took %s with a hanging check", elapsed)
+	}
+
+	hanging := resultOf(t, report, "hanging")
+	if hanging.Valid || !strings.Contains(hanging.Error, "check timed out after 20ms") {
+		t.Errorf("hanging check result = %+v, want a timeout error", hanging)
+	}
+	if lvm := resultOf(t, report, "lvm"); lvm.Error != "" || !lvm.Valid {
+		t.Errorf("lvm result after a timed out check = %+v", lvm)
+	}
+	if report.Passed {
+		t.Error("Passed = true with a check that could not be performed")
+	}
+}
+
+func TestCheckRunnerInspectionFailure(t *testing.T) {
+	inspector := fake.RHEL9()
+	inspector.Errors = map[string]error{"InspectWithVirt": context.DeadlineExceeded}
+	runner := checks.NewCheckRunner([]checks.Check{checks.NewLVMCheck(), checks.NewLUKSCheck()})
+
+	report := runner.Run(context.Background(), runParams(inspector))
+	for _, id := range []string{"lvm", "luks-encryption"} {
+		if result := resultOf(t, report, id); result.Error == "" {
+			t.Errorf("%s result = %+v, want the inspection error", id, result)
+		}
+	}
+}
+
+// brokenLVM returns a RHEL 9 inspector whose data volume group lost a physical volume
+func brokenLVM() *fake.Inspector {
+	inspector := fake.RHEL9()
+	inspector.LVM.PhysicalVolumes = append(inspector.LVM.PhysicalVolumes, types.LVMPhysicalVolume{VolumeGroup: "data", Missing: true})
+	return inspector
+}
+
+func TestCheckRunnerSuppressions(t *testing.T) {
+	tests := []struct {
+		name           string
+		action         checks.SuppressionAction
+		expires        time.Time
+		wantSeverity   checks.Severity
+		wantSuppressed int
+		wantPassed     bool
+	}{
+		{name: "downgrade", action: checks.SuppressionDowngrade, wantSeverity: checks.SeverityInfo, wantPassed: true},
+		{name: "hide", action: checks.SuppressionHide, wantSuppressed: 1, wantPassed: true},
+		{name: "expired", action: checks.SuppressionDowngrade, expires: time.Now().Add(-time.Hour), wantSeverity: checks.SeverityError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suppressions, err := checks.NewSuppressions(checks.Suppression{
+				CheckID:       "lvm",
+				Match:         "volume group data",
+				Action:        tt.action,
+				Expires:       tt.expires,
+				Justification: "data volume group is recreated on the target",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			runner := checks.NewCheckRunner([]checks.Check{checks.NewLVMCheck()}).WithSuppressions(suppressions)
+
+			report := runner.Run(context.Background(), runParams(brokenLVM()))
+			if len(report.Suppressed) != tt.wantSuppressed {
+				t.Errorf("Suppressed = %+v, want %d", report.Suppressed, tt.wantSuppressed)
+			}
+			if tt.wantSuppressed == 0 {
+				if result := resultOf(t, report, "lvm"); result.Severity != tt.wantSeverity {
+					t.Errorf("lvm severity = %s, want %s", result.Severity, tt.wantSeverity)
+				}
+			}
+			if report.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v", report.Passed, tt.wantPassed)
+			}
+		})
+	}
+}
+
+func TestCheckRunnerFailOn(t *testing.T) {
+	inspector := fake.RHEL9()
+	metadataPercent := 85.0
+	inspector.LVM.LogicalVolumes = append(inspector.LVM.LogicalVolumes, types.LVMLogicalVolume{
+		VolumeGroup: "rhel", Name: "pool", Attributes: "twi-aotz--", MetadataPercent: &metadataPercent,
+	})
+	lvm := []checks.Check{checks.NewLVMCheck()}
+
+	if report := checks.NewCheckRunner(lvm).Run(context.Background(), runParams(inspector)); !report.Passed {
+		t.Error("Passed = false for a warning with the default fail-on severities")
+	}
+	report := checks.NewCheckRunner(lvm).WithFailOn(checks.SeverityWarning, checks.SeverityError).Run(context.Background(), runParams(inspector))
+	if report.Passed {
+		t.Error("Passed = true for a warning failing the validation")
+	}
+	if result := resultOf(t, report, "lvm"); result.Severity != checks.SeverityWarning {
+		t.Errorf("lvm severity = %s, want warning", result.Severity)
+	}
+}
+
+func TestCheckWithoutRunnerSharesInspector(t *testing.T) {
+	if _, err := checks.NewLVMCheck().Run(context.Background(), runParams(nil)); err == nil || !strings.Contains(err.Error(), "Inspector is required") {
+		t.Errorf("Run() error = %v without an Inspector, want it required", err)
+	}
+
+	inspector := fake.RHEL9()
+	if _, err := checks.NewLVMCheck().Run(context.Background(), runParams(inspector)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(inspector.Calls()) < 2 {
+		t.Errorf("Calls() = %+v, want the inspections of the check on the provided Inspector", inspector.Calls())
+	}
+}
@@ -0,0 +1,136 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// signedBootPackages lists packages providing a Secure Boot signed bootloader on Linux guests
+var signedBootPackages = []string{
+	"shim",
+	"shim-x64",
+	"shim-aa64",
+	"shim-signed",
+	"grub-efi-amd64-signed",
+}
+
+// SecureBootCheck validates Secure Boot readiness of the guest
+// Secure Boot enablement comes from the vSphere VM configuration and the signed
+// bootloader components from the virt-inspector application inventory
+type SecureBootCheck struct{}
+
+// NewSecureBootCheck creates a new SecureBootCheck
+func NewSecureBootCheck() *SecureBootCheck {
+	return &SecureBootCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *SecureBootCheck) ID() string {
+	return "secure-boot"
+}
+
+// Category returns the category of the check
+func (c *SecureBootCheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *SecureBootCheck) Tags() []string {
+	return []string{"vsphere-config", "firmware", "security"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *SecureBootCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *SecureBootCheck) Name() string {
+	return "Secure Boot Readiness"
+}
+
+// Describe returns the metadata of the check
+func (c *SecureBootCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Validates Secure Boot readiness of the guest. Secure Boot enablement comes from the vSphere VM configuration and the signed bootloader components from the virt-inspector application inventory.")
+}
+
+// Run inspects the VM snapshot and validates signed boot components when Secure Boot is enabled
+func (c *SecureBootCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	if !config.SecureBoot {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("secure-boot.disabled"), nil
+	}
+
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks that every guest has signed boot components
+func (c *SecureBootCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var unsigned []string
+	var details []string
+
+	for _, guest := range data.Operatingsystems {
+		desc := describeOS(guest)
+		switch guest.Name {
+		case "windows":
+			major, minor, err := parseOSVersion(guest)
+			// Secure Boot is supported starting with Windows 8 / Server 2012 (NT 6.2)
+			if err != nil || major < 6 || (major == 6 && minor < 2) {
+				unsigned = append(unsigned, desc)
+				details = append(details, fmt.Sprintf("%s does not support Secure Boot", desc))
+				continue
+			}
+			details = append(details, fmt.Sprintf("%s supports Secure Boot", desc))
+		default:
+			pkg := findApplication(guest, signedBootPackages)
+			if pkg == "" {
+				unsigned = append(unsigned, desc)
+				details = append(details, fmt.Sprintf("%s has no signed shim bootloader installed", desc))
+				continue
+			}
+			details = append(details, fmt.Sprintf("%s has signed bootloader package %s", desc, pkg))
+		}
+	}
+
+	if len(unsigned) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("secure-boot.unsigned", "reasons", strings.Join(unsigned, ", "))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("secure-boot.signed")
+}
+
+// findApplication returns the first installed application matching one of the given names
+func findApplication(guest types.VirtInspectorOS, names []string) string {
+	for _, app := range guest.Applications.Application {
+		for _, name := range names {
+			if strings.EqualFold(app.Name, name) {
+				return app.Name
+			}
+		}
+	}
+	return ""
+}
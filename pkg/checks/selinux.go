@@ -0,0 +1,170 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// selinuxCustomModuleDirs lists directories holding locally installed SELinux policy modules (priority 400)
+var selinuxCustomModuleDirs = []string{
+	"/etc/selinux/%s/active/modules/400",
+	"/var/lib/selinux/%s/active/modules/400",
+}
+
+// SELinuxCheck reports whether an SELinux relabel will be required after conversion
+// Enforcing guests with locally installed policy modules are flagged as a warning,
+// since custom labels may not survive the relabel
+type SELinuxCheck struct{}
+
+// NewSELinuxCheck creates a new SELinuxCheck
+func NewSELinuxCheck() *SELinuxCheck {
+	return &SELinuxCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *SELinuxCheck) ID() string {
+	return "selinux-relabel"
+}
+
+// Category returns the category of the check
+func (c *SELinuxCheck) Category() string {
+	return CategoryGuestOS
+}
+
+// Tags returns the tags of the check
+func (c *SELinuxCheck) Tags() []string {
+	return []string{"linux", "security"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *SELinuxCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *SELinuxCheck) Name() string {
+	return "SELinux Relabel"
+}
+
+// Describe returns the metadata of the check
+func (c *SELinuxCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Reports whether an SELinux relabel will be required after conversion. Enforcing guests with locally installed policy modules are flagged as a warning, since custom labels may not survive the relabel.", OSFamilyLinux)
+}
+
+// Run reads the guest SELinux configuration and policy modules
+func (c *SELinuxCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	files, err := params.readGuestFiles(ctx, "/etc/selinux/config", "/.autorelabel")
+	if err != nil {
+		return CheckResult{}, err
+	}
+	config, found := files["/etc/selinux/config"]
+	if !found {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("selinux-relabel.not-configured"), nil
+	}
+
+	mode, policy := parseSELinuxConfig(config)
+	var modules map[string][]byte
+	if mode == "enforcing" && policy != "" {
+		var moduleDirs []string
+		for _, dir := range selinuxCustomModuleDirs {
+			moduleDirs = append(moduleDirs, fmt.Sprintf(dir, policy))
+		}
+		modules, err = params.readGuestFiles(ctx, moduleDirs...)
+		if err != nil {
+			return CheckResult{}, err
+		}
+	}
+
+	_, autorelabel := files["/.autorelabel"]
+	return c.evaluate(mode, policy, autorelabel, customSELinuxModules(modules)), nil
+}
+
+// evaluate reports the relabel requirement based on the SELinux mode and custom modules
+func (c *SELinuxCheck) evaluate(mode string, policy string, autorelabel bool, customModules []string) CheckResult {
+	details := []string{fmt.Sprintf("SELinux mode: %s, policy: %s", mode, policy)}
+	if autorelabel {
+		details = append(details, "/.autorelabel is already present in the guest")
+	}
+
+	if mode == "disabled" || mode == "" {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+			Details:   details,
+		}.withMessage("selinux-relabel.disabled")
+	}
+
+	if mode == "enforcing" && len(customModules) > 0 {
+		for _, module := range customModules {
+			details = append(details, fmt.Sprintf("custom policy module: %s", module))
+		}
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("selinux-relabel.custom-modules")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("selinux-relabel.relabel", "mode", mode)
+}
+
+// parseSELinuxConfig extracts SELINUX and SELINUXTYPE from /etc/selinux/config
+func parseSELinuxConfig(config []byte) (string, string) {
+	var mode, policy string
+	scanner := bufio.NewScanner(bytes.NewReader(config))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		value = strings.ToLower(strings.Trim(strings.TrimSpace(value), `"'`))
+		switch strings.TrimSpace(key) {
+		case "SELINUX":
+			mode = value
+		case "SELINUXTYPE":
+			policy = value
+		}
+	}
+	return mode, policy
+}
+
+// customSELinuxModules returns the names of custom policy modules from files under the module directories
+// Each module is stored as <dir>/<module>/<files>
+func customSELinuxModules(files map[string][]byte) []string {
+	seen := make(map[string]struct{})
+	for file := range files {
+		idx := strings.Index(file, "/modules/400/")
+		if idx < 0 {
+			continue
+		}
+		module := strings.SplitN(file[idx+len("/modules/400/"):], "/", 2)[0]
+		if module != "" {
+			seen[module] = struct{}{}
+		}
+	}
+	modules := make([]string, 0, len(seen))
+	for module := range seen {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
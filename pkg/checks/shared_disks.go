@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// SharedDisksCheck detects disks shared between VMs with multi-writer or SCSI bus sharing
+// Shared-disk clusters cannot be migrated one VM at a time
+type SharedDisksCheck struct{}
+
+// NewSharedDisksCheck creates a new SharedDisksCheck
+func NewSharedDisksCheck() *SharedDisksCheck {
+	return &SharedDisksCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *SharedDisksCheck) ID() string {
+	return "shared-disks"
+}
+
+// Category returns the category of the check
+func (c *SharedDisksCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *SharedDisksCheck) Tags() []string {
+	return []string{"vsphere-config", "cluster"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *SharedDisksCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *SharedDisksCheck) Name() string {
+	return "Shared Multi-Writer Disks"
+}
+
+// Describe returns the metadata of the check
+func (c *SharedDisksCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Detects disks shared between VMs with multi-writer or SCSI bus sharing. Shared-disk clusters cannot be migrated one VM at a time.")
+}
+
+// Run validates the VM disks and controllers from the vSphere configuration
+func (c *SharedDisksCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config), nil
+}
+
+// evaluate reports multi-writer disks and controllers with bus sharing
+func (c *SharedDisksCheck) evaluate(config *types.VMConfig) CheckResult {
+	var details []string
+	for _, disk := range config.Disks {
+		if disk.Sharing == "sharingMultiWriter" {
+			details = append(details, fmt.Sprintf("%s (%s) has multi-writer sharing enabled", disk.Label, disk.FileName))
+		}
+	}
+	for _, controller := range config.Controllers {
+		if controller.BusSharing == "virtualSharing" || controller.BusSharing == "physicalSharing" {
+			details = append(details, fmt.Sprintf("%s has SCSI bus sharing %s", controller.Label, controller.BusSharing))
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("shared-disks.found")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("shared-disks.none")
+}
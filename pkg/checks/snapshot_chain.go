@@ -0,0 +1,135 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+const (
+	// DefaultMaxSnapshotDepth is the snapshot tree depth above which inspection and conversion get slow
+	DefaultMaxSnapshotDepth = 3
+	// DefaultMaxSnapshotBytes is the combined snapshot size above which inspection and conversion get slow
+	DefaultMaxSnapshotBytes uint64 = 100 * 1024 * 1024 * 1024
+)
+
+// SnapshotChainCheck examines the existing snapshot tree of the VM
+// Long delta chains slow down inspection and conversion, and disks needing consolidation should be consolidated first
+type SnapshotChainCheck struct {
+	maxDepth int
+	maxBytes uint64
+}
+
+// NewSnapshotChainCheck creates a new SnapshotChainCheck
+// maxDepth: maximum snapshot tree depth (uses DefaultMaxSnapshotDepth if zero)
+// maxBytes: maximum combined snapshot size (uses DefaultMaxSnapshotBytes if zero)
+func NewSnapshotChainCheck(maxDepth int, maxBytes uint64) *SnapshotChainCheck {
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxSnapshotDepth
+	}
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxSnapshotBytes
+	}
+	return &SnapshotChainCheck{
+		maxDepth: maxDepth,
+		maxBytes: maxBytes,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *SnapshotChainCheck) ID() string {
+	return "snapshot-chain"
+}
+
+// Category returns the category of the check
+func (c *SnapshotChainCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *SnapshotChainCheck) Tags() []string {
+	return []string{"vsphere-config", "snapshot"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *SnapshotChainCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *SnapshotChainCheck) Name() string {
+	return "Snapshot Chain"
+}
+
+// Describe returns the metadata of the check
+func (c *SnapshotChainCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Examines the existing snapshot tree of the VM. Long delta chains slow down inspection and conversion, and disks needing consolidation should be consolidated first.")
+}
+
+// Run validates the snapshot tree from the vSphere configuration
+func (c *SnapshotChainCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config), nil
+}
+
+// evaluate reports deep or large snapshot trees and disks needing consolidation
+func (c *SnapshotChainCheck) evaluate(config *types.VMConfig) CheckResult {
+	depth, count, size := snapshotTreeStats(config.Snapshots)
+	details := []string{fmt.Sprintf("%d snapshot(s), depth %d, %s of delta files", count, depth, formatBytes(size))}
+	for _, disk := range config.Disks {
+		if disk.ChainLength > 1 {
+			details = append(details, fmt.Sprintf("%s (%s): delta chain of %d file(s)", disk.Label, disk.FileName, disk.ChainLength))
+		}
+	}
+
+	if config.ConsolidationNeeded {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("snapshot-chain.consolidation-needed")
+	}
+
+	var problems []string
+	if depth > c.maxDepth {
+		problems = append(problems, fmt.Sprintf("snapshot tree depth %d exceeds %d", depth, c.maxDepth))
+	}
+	if size > c.maxBytes {
+		problems = append(problems, fmt.Sprintf("snapshots total %s, more than %s", formatBytes(size), formatBytes(c.maxBytes)))
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(problems, details...),
+		}.withMessage("snapshot-chain.long-chain")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("snapshot-chain.within-limits")
+}
+
+// snapshotTreeStats returns the depth, number and combined size of snapshots in a snapshot tree
+func snapshotTreeStats(snapshots []types.Snapshot) (depth int, count int, size uint64) {
+	for _, snapshot := range snapshots {
+		childDepth, childCount, childSize := snapshotTreeStats(snapshot.Children)
+		depth = max(depth, childDepth+1)
+		count += childCount + 1
+		size += childSize
+		if snapshot.SizeBytes > 0 {
+			size += uint64(snapshot.SizeBytes)
+		}
+	}
+	return depth, count, size
+}
@@ -0,0 +1,173 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// OSVersionRange describes a range of supported versions for a guest distribution
+type OSVersionRange struct {
+	Distro   string `json:"distro"` // Distro as reported by virt-inspector (e.g., "rhel", "windows")
+	MinMajor int    `json:"min_major"`
+	MinMinor int    `json:"min_minor,omitempty"`
+	MaxMajor int    `json:"max_major,omitempty"` // No upper bound if zero
+}
+
+// DefaultSupportedOSMatrix lists guest operating systems supported by virt-v2v and the target platform
+var DefaultSupportedOSMatrix = []OSVersionRange{
+	{Distro: "rhel", MinMajor: 7},
+	{Distro: "centos", MinMajor: 7},
+	{Distro: "rocky", MinMajor: 8},
+	{Distro: "almalinux", MinMajor: 8},
+	{Distro: "oraclelinux", MinMajor: 7},
+	{Distro: "fedora", MinMajor: 30},
+	{Distro: "sles", MinMajor: 12},
+	{Distro: "opensuse", MinMajor: 15},
+	{Distro: "ubuntu", MinMajor: 18},
+	{Distro: "debian", MinMajor: 10},
+	{Distro: "windows", MinMajor: 6, MinMinor: 1}, // Windows 7 / Server 2008 R2 and later
+}
+
+// SupportedOSCheck validates the detected guest OS against a matrix of supported versions
+type SupportedOSCheck struct {
+	matrix []OSVersionRange
+}
+
+// NewSupportedOSCheck creates a new SupportedOSCheck
+// matrix: supported OS versions (uses DefaultSupportedOSMatrix if nil)
+func NewSupportedOSCheck(matrix []OSVersionRange) *SupportedOSCheck {
+	if matrix == nil {
+		matrix = DefaultSupportedOSMatrix
+	}
+	return &SupportedOSCheck{
+		matrix: matrix,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *SupportedOSCheck) ID() string {
+	return "supported-os"
+}
+
+// Category returns the category of the check
+func (c *SupportedOSCheck) Category() string {
+	return CategoryGuestOS
+}
+
+// Tags returns the tags of the check
+func (c *SupportedOSCheck) Tags() []string {
+	return []string{"windows", "linux"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *SupportedOSCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *SupportedOSCheck) Name() string {
+	return "Supported Guest OS"
+}
+
+// Describe returns the metadata of the check
+func (c *SupportedOSCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Validates the detected guest OS against a matrix of supported versions.", OSFamilyLinux, OSFamilyWindows)
+}
+
+// Run inspects the VM snapshot and validates the guest OS version
+func (c *SupportedOSCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks every detected operating system against the support matrix
+func (c *SupportedOSCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var unsupported []string
+	var details []string
+
+	for _, guest := range data.Operatingsystems {
+		desc := describeOS(guest)
+		supported, err := c.isSupported(guest)
+		if err != nil {
+			unsupported = append(unsupported, desc)
+			details = append(details, fmt.Sprintf("%s: %v", desc, err))
+			continue
+		}
+		if !supported {
+			unsupported = append(unsupported, desc)
+			details = append(details, fmt.Sprintf("%s is not supported for migration", desc))
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s is supported", desc))
+	}
+
+	if len(unsupported) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("supported-os.unsupported", "systems", strings.Join(unsupported, ", "))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("supported-os.supported")
+}
+
+// isSupported returns true if the guest OS version falls within a matrix entry for its distro
+func (c *SupportedOSCheck) isSupported(guest types.VirtInspectorOS) (bool, error) {
+	major, minor, err := parseOSVersion(guest)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range c.matrix {
+		if !strings.EqualFold(entry.Distro, guest.Distro) {
+			continue
+		}
+		if major < entry.MinMajor || (major == entry.MinMajor && minor < entry.MinMinor) {
+			continue
+		}
+		if entry.MaxMajor != 0 && major > entry.MaxMajor {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// parseOSVersion parses the major and minor version reported by virt-inspector
+func parseOSVersion(guest types.VirtInspectorOS) (int, int, error) {
+	major, err := strconv.Atoi(strings.TrimSpace(guest.MajorVersion))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid major version %q", guest.MajorVersion)
+	}
+
+	minor := 0
+	if guest.MinorVersion != "" {
+		minor, err = strconv.Atoi(strings.TrimSpace(guest.MinorVersion))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid minor version %q", guest.MinorVersion)
+		}
+	}
+	return major, minor, nil
+}
+
+// describeOS returns a short human readable description of the guest OS
+func describeOS(guest types.VirtInspectorOS) string {
+	if guest.Product != "" {
+		return guest.Product
+	}
+	return fmt.Sprintf("%s %s.%s", guest.Distro, guest.MajorVersion, guest.MinorVersion)
+}
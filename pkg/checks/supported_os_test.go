@@ -0,0 +1,58 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+func TestParseOSVersion(t *testing.T) {
+	tests := []struct {
+		major, minor         string
+		wantMajor, wantMinor int
+		wantErr              bool
+	}{
+		{major: "9", minor: "2", wantMajor: 9, wantMinor: 2},
+		{major: " 8 ", minor: "", wantMajor: 8},
+		{major: "10", minor: "0", wantMajor: 10},
+		{major: "6", minor: "1", wantMajor: 6, wantMinor: 1},
+		{major: "", wantErr: true},
+		{major: "unknown", wantErr: true},
+		{major: "7", minor: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		major, minor, err := parseOSVersion(types.VirtInspectorOS{MajorVersion: tt.major, MinorVersion: tt.minor})
+		if (err != nil) != tt.wantErr || major != tt.wantMajor || minor != tt.wantMinor {
+			t.Errorf("parseOSVersion(%q, %q) = %d, %d, %v, want %d, %d, error %v",
+				tt.major, tt.minor, major, minor, err, tt.wantMajor, tt.wantMinor, tt.wantErr)
+		}
+	}
+}
+
+func TestSupportedOSCheckIsSupported(t *testing.T) {
+	check := NewSupportedOSCheck(nil)
+	tests := []struct {
+		distro, major, minor string
+		want                 bool
+	}{
+		{"rhel", "9", "2", true},
+		{"rhel", "6", "10", false},
+		{"RHEL", "7", "0", true},
+		{"windows", "6", "1", true},
+		{"windows", "6", "0", false},
+		{"windows", "10", "0", true},
+		{"ubuntu", "16", "04", false},
+		{"archlinux", "1", "0", false},
+	}
+	for _, tt := range tests {
+		supported, err := check.isSupported(types.VirtInspectorOS{Distro: tt.distro, MajorVersion: tt.major, MinorVersion: tt.minor})
+		if err != nil || supported != tt.want {
+			t.Errorf("isSupported(%s %s.%s) = %v, %v, want %v", tt.distro, tt.major, tt.minor, supported, err, tt.want)
+		}
+	}
+
+	bounded := NewSupportedOSCheck([]OSVersionRange{{Distro: "rhel", MinMajor: 8, MaxMajor: 9}})
+	if supported, _ := bounded.isSupported(types.VirtInspectorOS{Distro: "rhel", MajorVersion: "10"}); supported {
+		t.Error("isSupported(rhel 10) = true above MaxMajor")
+	}
+}
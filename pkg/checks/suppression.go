@@ -0,0 +1,188 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SuppressionAction is what happens to a finding matched by a suppression
+type SuppressionAction string
+
+const (
+	// SuppressionDowngrade keeps the finding in the results with the severity of the suppression
+	SuppressionDowngrade SuppressionAction = "downgrade"
+	// SuppressionHide moves the finding from the results to the suppressed results of the report
+	SuppressionHide SuppressionAction = "hide"
+)
+
+// Suppression accepts known findings of a check so they no longer affect the verdict
+// Suppressed findings are still recorded in the validation report for audit
+type Suppression struct {
+	CheckID       string            `json:"check_id"`
+	Match         string            `json:"match,omitempty"`    // Regular expression matched against the message and details, all findings of the check if empty
+	Action        SuppressionAction `json:"action,omitempty"`   // Defaults to SuppressionDowngrade
+	Severity      Severity          `json:"severity,omitempty"` // Severity of downgraded findings (defaults to SeverityInfo)
+	Expires       time.Time         `json:"expires"`            // Zero for a suppression that does not expire
+	Justification string            `json:"justification"`      // Why the finding is accepted
+
+	match *regexp.Regexp
+}
+
+// Suppressions is a suppression (baseline) file
+//
+//	{
+//	  "suppressions": [
+//	    {
+//	      "check_id": "eol-os",
+//	      "match": "centos 7",
+//	      "expires": "2027-01-01T00:00:00Z",
+//	      "justification": "extended support contract until the end of 2026"
+//	    }
+//	  ]
+//	}
+type Suppressions struct {
+	Suppressions []Suppression `json:"suppressions"`
+}
+
+// NewSuppressions validates suppressions and applies their defaults
+func NewSuppressions(suppressions ...Suppression) (*Suppressions, error) {
+	compiled := make([]Suppression, len(suppressions))
+	copy(compiled, suppressions)
+	for i := range compiled {
+		if err := compiled[i].compile(); err != nil {
+			return nil, fmt.Errorf("suppression %d: %w", i, err)
+		}
+	}
+	return &Suppressions{Suppressions: compiled}, nil
+}
+
+// LoadSuppressions reads and validates a suppression file
+func LoadSuppressions(r io.Reader) (*Suppressions, error) {
+	var suppressions Suppressions
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&suppressions); err != nil {
+		return nil, fmt.Errorf("failed to parse suppressions: %w", err)
+	}
+	return NewSuppressions(suppressions.Suppressions...)
+}
+
+// LoadSuppressionsFile reads and validates a suppression file from a path
+func LoadSuppressionsFile(path string) (*Suppressions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open suppressions: %w", err)
+	}
+	defer f.Close()
+	return LoadSuppressions(f)
+}
+
+// compile validates the suppression, applies defaults and compiles its matcher
+func (s *Suppression) compile() error {
+	if s.CheckID == "" {
+		return fmt.Errorf("check ID is required")
+	}
+	if strings.TrimSpace(s.Justification) == "" {
+		return fmt.Errorf("suppression of %q has no justification", s.CheckID)
+	}
+	switch s.Action {
+	case "":
+		s.Action = SuppressionDowngrade
+	case SuppressionDowngrade, SuppressionHide:
+	default:
+		return fmt.Errorf("suppression of %q has invalid action %q", s.CheckID, s.Action)
+	}
+	switch s.Severity {
+	case "":
+		s.Severity = SeverityInfo
+	case SeverityInfo, SeverityWarning:
+	default:
+		return fmt.Errorf("suppression of %q has invalid severity %q", s.CheckID, s.Severity)
+	}
+	if s.Match != "" {
+		match, err := regexp.Compile(s.Match)
+		if err != nil {
+			return fmt.Errorf("suppression of %q has invalid match: %w", s.CheckID, err)
+		}
+		s.match = match
+	}
+	return nil
+}
+
+// matches returns true if the suppression applies to a result at the given time
+func (s *Suppression) matches(result CheckResult, now time.Time) bool {
+	// Checks that could not be performed are never suppressed, their error must be addressed
+	if s.CheckID != result.CheckID || !isFinding(result) || result.Error != "" {
+		return false
+	}
+	if !s.Expires.IsZero() && !now.Before(s.Expires) {
+		return false
+	}
+	if s.match == nil {
+		return true
+	}
+	if s.match.MatchString(result.Message) {
+		return true
+	}
+	for _, detail := range result.Details {
+		if s.match.MatchString(detail) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply applies the first matching unexpired suppression to each result
+// Returns the results to report and the hidden results
+func (s *Suppressions) apply(results []CheckResult, now time.Time) ([]CheckResult, []CheckResult) {
+	if s == nil || len(s.Suppressions) == 0 {
+		return results, nil
+	}
+
+	var reported, hidden []CheckResult
+	for _, result := range results {
+		for i := range s.Suppressions {
+			suppression := &s.Suppressions[i]
+			if !suppression.matches(result, now) {
+				continue
+			}
+			result.Suppression = &AppliedSuppression{
+				Action:           suppression.Action,
+				Justification:    suppression.Justification,
+				Expires:          suppression.Expires,
+				OriginalSeverity: result.Severity,
+				OriginalValid:    result.Valid,
+			}
+			if suppression.Action == SuppressionDowngrade {
+				result.Severity = suppression.Severity
+				result.Valid = true
+			}
+			break
+		}
+		if result.Suppression != nil && result.Suppression.Action == SuppressionHide {
+			hidden = append(hidden, result)
+			continue
+		}
+		reported = append(reported, result)
+	}
+	return reported, hidden
+}
+
+// AppliedSuppression records the suppression applied to a finding
+type AppliedSuppression struct {
+	Action           SuppressionAction `json:"action"`
+	Justification    string            `json:"justification"`
+	Expires          time.Time         `json:"expires"`
+	OriginalSeverity Severity          `json:"original_severity"`
+	OriginalValid    bool              `json:"original_valid"`
+}
+
+// isFinding returns true for results that need attention
+func isFinding(result CheckResult) bool {
+	return !result.Valid || result.Severity != SeverityInfo
+}
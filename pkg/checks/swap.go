@@ -0,0 +1,138 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SwapCheck validates swap entries in /etc/fstab and the resume= kernel parameter
+// Swap lines often use different device naming than regular mounts, for example
+// by-path or by-id names generated by the installer
+type SwapCheck struct{}
+
+// NewSwapCheck creates a new SwapCheck
+func NewSwapCheck() *SwapCheck {
+	return &SwapCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *SwapCheck) ID() string {
+	return "swap-device-references"
+}
+
+// Category returns the category of the check
+func (c *SwapCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *SwapCheck) Tags() []string {
+	return []string{"linux", "device-names"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *SwapCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *SwapCheck) Name() string {
+	return "Swap Device References"
+}
+
+// Describe returns the metadata of the check
+func (c *SwapCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Validates swap entries in /etc/fstab and the resume= kernel parameter. Swap lines often use different device naming than regular mounts, for example by-path or by-id names generated by the installer.", OSFamilyLinux)
+}
+
+// Run reads the guest fstab and GRUB defaults and reports non-portable swap device references
+func (c *SwapCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	files, err := params.readGuestFiles(ctx, "/etc/fstab", "/etc/default/grub")
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(files["/etc/fstab"], files["/etc/default/grub"]), nil
+}
+
+// evaluate checks swap partitions, swap files and resume devices
+func (c *SwapCheck) evaluate(fstab []byte, grubDefaults []byte) CheckResult {
+	var problems []string
+	var details []string
+	swapCount := 0
+
+	for _, entry := range parseFstab(fstab) {
+		if entry.Type != "swap" {
+			continue
+		}
+		swapCount++
+		if isSwapFile(entry.Device) {
+			details = append(details, fmt.Sprintf("/etc/fstab:%d swap file %s", entry.LineNum, entry.Device))
+			continue
+		}
+		if issue := nonPortableDeviceIssue(entry.Device); issue != "" {
+			problems = append(problems, fmt.Sprintf("/etc/fstab:%d swap partition %s", entry.LineNum, issue))
+			continue
+		}
+		details = append(details, fmt.Sprintf("/etc/fstab:%d swap partition %s", entry.LineNum, entry.Device))
+	}
+
+	for _, resume := range resumeDevices(grubDefaults) {
+		if issue := nonPortableDeviceIssue(resume); issue != "" {
+			problems = append(problems, fmt.Sprintf("/etc/default/grub resume device %s %s", resume, issue))
+		}
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   append(problems, details...),
+		}.withMessage("swap-device-references.non-portable")
+	}
+
+	if swapCount == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("swap-device-references.no-swap")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("swap-device-references.portable", "count", swapCount)
+}
+
+// isSwapFile returns true if the swap entry refers to a file rather than a block device
+func isSwapFile(device string) bool {
+	if strings.HasPrefix(device, "/dev/") {
+		return false
+	}
+	return strings.HasPrefix(device, "/")
+}
+
+// resumeDevices extracts resume= kernel parameters from /etc/default/grub
+func resumeDevices(grubDefaults []byte) []string {
+	var devices []string
+	scanner := bufio.NewScanner(bytes.NewReader(grubDefaults))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, field := range strings.Fields(strings.Trim(line, `"'`)) {
+			field = strings.Trim(field, `"'`)
+			if idx := strings.Index(field, "resume="); idx >= 0 {
+				devices = append(devices, field[idx+len("resume="):])
+			}
+		}
+	}
+	return devices
+}
@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResumeDevices(t *testing.T) {
+	grubDefaults := []byte(`GRUB_TIMEOUT=5
+# GRUB_CMDLINE_LINUX="resume=/dev/sdz1"
+GRUB_CMDLINE_LINUX="crashkernel=auto resume=/dev/mapper/rhel-swap rd.lvm.lv=rhel/root rhgb quiet"
+GRUB_CMDLINE_LINUX_DEFAULT='resume=UUID=0d3e4f5a'
+`)
+	want := []string{"/dev/mapper/rhel-swap", "UUID=0d3e4f5a"}
+	if got := resumeDevices(grubDefaults); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("resumeDevices() = %q, want %q", got, want)
+	}
+}
+
+func TestIsSwapFile(t *testing.T) {
+	tests := map[string]bool{
+		"/swapfile":             true,
+		"/var/swap.img":         true,
+		"/dev/mapper/rhel-swap": false,
+		"/dev/sda2":             false,
+		"UUID=0d3e4f5a":         false,
+		"LABEL=swap":            false,
+	}
+	for device, want := range tests {
+		if got := isSwapFile(device); got != want {
+			t.Errorf("isSwapFile(%q) = %v, want %v", device, got, want)
+		}
+	}
+}
+
+func TestSwapCheckEvaluate(t *testing.T) {
+	tests := []struct {
+		name         string
+		fstab        string
+		grubDefaults string
+		wantValid    bool
+		wantMessage  string
+		wantProblems int
+	}{
+		{
+			name:        "no swap",
+			fstab:       "/dev/mapper/rhel-root / xfs defaults 0 0\n",
+			wantValid:   true,
+			wantMessage: "swap-device-references.no-swap",
+		},
+		{
+			name:         "portable swap partition and file",
+			fstab:        "/dev/mapper/rhel-swap none swap defaults 0 0\n/swapfile none swap defaults 0 0\n",
+			grubDefaults: `GRUB_CMDLINE_LINUX="resume=/dev/mapper/rhel-swap"`,
+			wantValid:    true,
+			wantMessage:  "swap-device-references.portable",
+		},
+		{
+			name:         "by-path swap partition",
+			fstab:        "/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:0:0-part2 none swap defaults 0 0\n",
+			wantMessage:  "swap-device-references.non-portable",
+			wantProblems: 1,
+		},
+		{
+			name:         "VMware by-id resume device",
+			fstab:        "UUID=0d3e4f5a none swap defaults 0 0\n",
+			grubDefaults: `GRUB_CMDLINE_LINUX="resume=/dev/disk/by-id/wwn-0x6000c29f0e1d2c3b4a5968778695a4b3-part2"`,
+			wantMessage:  "swap-device-references.non-portable",
+			wantProblems: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewSwapCheck().evaluate([]byte(tt.fstab), []byte(tt.grubDefaults))
+			if result.Valid != tt.wantValid || result.MessageID != tt.wantMessage {
+				t.Fatalf("evaluate() = %v %s (%s), want %v %s", result.Valid, result.MessageID, result.Message, tt.wantValid, tt.wantMessage)
+			}
+			problems := 0
+			for _, detail := range result.Details {
+				if strings.Contains(detail, "references") {
+					problems++
+				}
+			}
+			if problems != tt.wantProblems {
+				t.Errorf("Details = %q, want %d problems", result.Details, tt.wantProblems)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// DefaultFilesystemOverhead is the fraction of a filesystem-mode volume reserved for filesystem metadata
+const DefaultFilesystemOverhead = 0.055
+
+// TargetStorage describes the target storage class the disk is copied to
+type TargetStorage struct {
+	AvailableBytes     uint64  `json:"available_bytes"`               // Free capacity of the storage class
+	ThinProvisioned    bool    `json:"thin_provisioned,omitempty"`    // Whether the storage class allocates only the used space of the disk
+	FilesystemOverhead float64 `json:"filesystem_overhead,omitempty"` // Fraction added for filesystem metadata (uses DefaultFilesystemOverhead if zero)
+}
+
+// TargetStorageCheck estimates the storage required on the target from the disk's
+// provisioned and used sizes and compares it against the storage class capacity
+type TargetStorageCheck struct {
+	storage TargetStorage
+}
+
+// NewTargetStorageCheck creates a new TargetStorageCheck
+func NewTargetStorageCheck(storage TargetStorage) *TargetStorageCheck {
+	if storage.FilesystemOverhead == 0 {
+		storage.FilesystemOverhead = DefaultFilesystemOverhead
+	}
+	return &TargetStorageCheck{
+		storage: storage,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *TargetStorageCheck) ID() string {
+	return "target-storage"
+}
+
+// Category returns the category of the check
+func (c *TargetStorageCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *TargetStorageCheck) Tags() []string {
+	return []string{"capacity"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *TargetStorageCheck) Sources() []Source {
+	return nil
+}
+
+// Name returns the name of the check
+func (c *TargetStorageCheck) Name() string {
+	return "Target Storage Requirement"
+}
+
+// Describe returns the metadata of the check
+func (c *TargetStorageCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Estimates the storage required on the target from the disk's provisioned and used sizes and compares it against the storage class capacity.")
+}
+
+// Run estimates required storage from the snapshot disk info
+func (c *TargetStorageCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	if params.DiskInfo == nil {
+		return CheckResult{}, fmt.Errorf("snapshot disk info is required")
+	}
+	return c.evaluate(params.DiskInfo), nil
+}
+
+// evaluate compares the estimated requirement against the available capacity
+func (c *TargetStorageCheck) evaluate(diskInfo *types.SnapshotDiskInfo) CheckResult {
+	if diskInfo.CapacityBytes <= 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+		}.withMessage("target-storage.unknown-capacity")
+	}
+
+	base := uint64(diskInfo.CapacityBytes)
+	source := "provisioned size"
+	if c.storage.ThinProvisioned && diskInfo.UsedBytes > 0 && diskInfo.UsedBytes < diskInfo.CapacityBytes {
+		base = uint64(diskInfo.UsedBytes)
+		source = "used size"
+	}
+	required := base + uint64(float64(base)*c.storage.FilesystemOverhead)
+
+	details := []string{
+		fmt.Sprintf("%s: %s provisioned, %s used", diskInfo.DiskPath, formatBytes(uint64(diskInfo.CapacityBytes)), formatBytes(uint64(max(diskInfo.UsedBytes, 0)))),
+		fmt.Sprintf("required: %s (%s plus %.1f%% filesystem overhead)", formatBytes(required), source, c.storage.FilesystemOverhead*100),
+		fmt.Sprintf("available: %s", formatBytes(c.storage.AvailableBytes)),
+	}
+
+	if required > c.storage.AvailableBytes {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("target-storage.insufficient", "required", formatBytes(required), "available", formatBytes(c.storage.AvailableBytes))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("target-storage.sufficient", "required", formatBytes(required), "available", formatBytes(c.storage.AvailableBytes))
+}
@@ -0,0 +1,140 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+const (
+	firmwareBIOS = "bios"
+	firmwareUEFI = "uefi"
+)
+
+// UEFICheck verifies that UEFI guests have an EFI System Partition
+// Firmware is detected by virt-v2v-inspector and filesystems by virt-inspector
+type UEFICheck struct{}
+
+// NewUEFICheck creates a new UEFICheck
+func NewUEFICheck() *UEFICheck {
+	return &UEFICheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *UEFICheck) ID() string {
+	return "uefi-esp"
+}
+
+// Category returns the category of the check
+func (c *UEFICheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *UEFICheck) Tags() []string {
+	return []string{"firmware"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *UEFICheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceVirtV2vInspector}
+}
+
+// Name returns the name of the check
+func (c *UEFICheck) Name() string {
+	return "UEFI Firmware and ESP"
+}
+
+// Describe returns the metadata of the check
+func (c *UEFICheck) Describe() Metadata {
+	return describe(c, SeverityError, "Verifies that UEFI guests have an EFI System Partition. Firmware is detected by virt-v2v-inspector and filesystems by virt-inspector.")
+}
+
+// Run inspects the VM snapshot and validates firmware against the EFI System Partition
+func (c *UEFICheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	v2vData, err := params.inspectWithVirtV2v(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(v2vData.Firmware.Type, data), nil
+}
+
+// evaluate checks the firmware type against the ESP presence
+func (c *UEFICheck) evaluate(firmware string, data *types.VirtInspectorXML) CheckResult {
+	espDevices := findESPDevices(data)
+	firmware = strings.ToLower(firmware)
+
+	switch firmware {
+	case firmwareUEFI:
+		if len(espDevices) == 0 {
+			return CheckResult{
+				CheckName: c.Name(),
+				Valid:     false,
+				Severity:  SeverityError,
+			}.withMessage("uefi-esp.no-esp")
+		}
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+			Details:   espDetails(espDevices),
+		}.withMessage("uefi-esp.esp-found")
+	case firmwareBIOS:
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+			Details:   espDetails(espDevices),
+		}.withMessage("uefi-esp.bios")
+	default:
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   espDetails(espDevices),
+		}.withMessage("uefi-esp.unknown-firmware", "firmware", firmware)
+	}
+}
+
+// findESPDevices returns devices that look like an EFI System Partition
+// Linux guests mount the ESP on /boot/efi or /efi, Windows guests only report it as a vfat filesystem
+func findESPDevices(data *types.VirtInspectorXML) []string {
+	var devices []string
+	seen := make(map[string]struct{})
+
+	for _, guest := range data.Operatingsystems {
+		for _, mp := range guest.Mountpoints.Mountpoint {
+			if mp.MountPoint == "/boot/efi" || mp.MountPoint == "/efi" {
+				if _, ok := seen[mp.Device]; !ok {
+					seen[mp.Device] = struct{}{}
+					devices = append(devices, mp.Device)
+				}
+			}
+		}
+		for _, fs := range guest.Filesystems.Filesystem {
+			if fs.Type != "vfat" {
+				continue
+			}
+			if _, ok := seen[fs.Device]; !ok {
+				seen[fs.Device] = struct{}{}
+				devices = append(devices, fs.Device)
+			}
+		}
+	}
+	return devices
+}
+
+// espDetails formats the list of EFI System Partitions
+func espDetails(devices []string) []string {
+	details := make([]string, 0, len(devices))
+	for _, dev := range devices {
+		details = append(details, fmt.Sprintf("EFI System Partition: %s", dev))
+	}
+	return details
+}
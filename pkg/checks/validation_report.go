@@ -0,0 +1,171 @@
+package checks
+
+import (
+	"slices"
+	"time"
+)
+
+// Verdict is the overall outcome of validating a VM
+type Verdict string
+
+const (
+	// VerdictMigrateable means no check reported a finding
+	VerdictMigrateable Verdict = "migrateable"
+	// VerdictMigrateableWithWarnings means checks reported warnings that should be reviewed
+	VerdictMigrateableWithWarnings Verdict = "migrateable-with-warnings"
+	// VerdictBlocked means at least one check reported a migration blocker
+	VerdictBlocked Verdict = "blocked"
+)
+
+// VMIdentity identifies the VM and snapshot that was validated
+type VMIdentity struct {
+	Name          string `json:"name"`
+	Moref         string `json:"moref,omitempty"`
+	Snapshot      string `json:"snapshot,omitempty"`
+	SnapshotMoref string `json:"snapshot_moref,omitempty"`
+	Datacenter    string `json:"datacenter,omitempty"`
+}
+
+// ValidationReport aggregates the check results of a VM snapshot with an overall verdict
+type ValidationReport struct {
+	VM           VMIdentity         `json:"vm"`
+	Verdict      Verdict            `json:"verdict"`
+	StartedAt    time.Time          `json:"started_at"`
+	CompletedAt  time.Time          `json:"completed_at"`
+	ToolVersions map[string]string  `json:"tool_versions,omitempty"` // Versions of inspection tools keyed by tool name (e.g., "virt-v2v")
+	Inspections  []InspectionTiming `json:"inspections,omitempty"`   // Inspections performed before the checks run
+	Results      []CheckResult      `json:"results"`
+	Suppressed   []CheckResult      `json:"suppressed,omitempty"` // Findings hidden by suppressions, kept for audit
+
+	// Passed is the pass/fail outcome for automation: false if a result has one of the FailOn
+	// severities or a check could not be performed
+	Passed bool       `json:"passed"`
+	FailOn []Severity `json:"fail_on"`
+}
+
+// InspectionTiming records an inspection performed by CheckRunner
+type InspectionTiming struct {
+	Source    Source        `json:"source"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// DefaultFailOn are the severities failing a validation unless configured otherwise
+// Warnings are reported without failing the validation
+var DefaultFailOn = []Severity{SeverityError}
+
+// NewValidationReport creates a report of the check results and computes its verdict
+// The pass/fail outcome uses DefaultFailOn
+func NewValidationReport(vm VMIdentity, startedAt time.Time, results []CheckResult) *ValidationReport {
+	report := &ValidationReport{
+		VM:          vm,
+		Verdict:     verdictOf(results),
+		StartedAt:   startedAt,
+		CompletedAt: time.Now().UTC(),
+		Results:     results,
+	}
+	report.applyFailOn(DefaultFailOn)
+	return report
+}
+
+// applyFailOn computes the pass/fail outcome of the report for the given failing severities
+func (r *ValidationReport) applyFailOn(failOn []Severity) {
+	r.FailOn = failOn
+	r.Passed = true
+	for _, result := range r.Results {
+		if result.Error != "" || slices.Contains(failOn, result.Severity) {
+			r.Passed = false
+			return
+		}
+	}
+}
+
+// Duration returns how long the validation took
+func (r *ValidationReport) Duration() time.Duration {
+	return r.CompletedAt.Sub(r.StartedAt)
+}
+
+// Exit codes for CLI and CI integrations
+const (
+	ExitCodePass         = 0 // No findings failing the validation and no warnings
+	ExitCodeWarnings     = 1 // Warnings not failing the validation
+	ExitCodeFailures     = 2 // Findings failing the validation (see ValidationReport.FailOn)
+	ExitCodeNotPerformed = 3 // Checks could not be performed (e.g., inspection or vCenter errors)
+)
+
+// Summary counts the results of a validation report
+type Summary struct {
+	Total        int `json:"total"`
+	Info         int `json:"info"`
+	Warnings     int `json:"warnings"`
+	Errors       int `json:"errors"`
+	Failures     int `json:"failures"`      // Results with one of the FailOn severities
+	NotPerformed int `json:"not_performed"` // Checks that could not be performed, also counted as errors
+	Suppressed   int `json:"suppressed"`    // Hidden findings, not counted in Total
+	ExitCode     int `json:"exit_code"`
+}
+
+// Summary counts the results per severity and maps them to an exit code
+func (r *ValidationReport) Summary() Summary {
+	summary := Summary{
+		Total:      len(r.Results),
+		Suppressed: len(r.Suppressed),
+	}
+	for _, result := range r.Results {
+		switch result.Severity {
+		case SeverityInfo:
+			summary.Info++
+		case SeverityWarning:
+			summary.Warnings++
+		case SeverityError:
+			summary.Errors++
+		}
+		if slices.Contains(r.FailOn, result.Severity) {
+			summary.Failures++
+		}
+		if result.Error != "" {
+			summary.NotPerformed++
+		}
+	}
+
+	switch {
+	case summary.NotPerformed > 0:
+		summary.ExitCode = ExitCodeNotPerformed
+	case summary.Failures > 0:
+		summary.ExitCode = ExitCodeFailures
+	case summary.Warnings > 0:
+		summary.ExitCode = ExitCodeWarnings
+	default:
+		summary.ExitCode = ExitCodePass
+	}
+	return summary
+}
+
+// verdictOf computes the overall verdict of check results
+func verdictOf(results []CheckResult) Verdict {
+	verdict := VerdictMigrateable
+	for _, result := range results {
+		if !result.Valid || result.Severity == SeverityError {
+			return VerdictBlocked
+		}
+		if result.Severity == SeverityWarning {
+			verdict = VerdictMigrateableWithWarnings
+		}
+	}
+	return verdict
+}
+
+// vmIdentity builds the VM identity from the inspection parameters
+func (p InspectionParams) vmIdentity() VMIdentity {
+	vm := VMIdentity{
+		Name:       p.VMName,
+		Snapshot:   p.SnapshotName,
+		Datacenter: p.Datacenter,
+	}
+	if p.DiskInfo != nil {
+		vm.Moref = p.DiskInfo.VMMoref
+		vm.SnapshotMoref = p.DiskInfo.SnapshotMoref
+	}
+	return vm
+}
@@ -0,0 +1,91 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// pciClassDisplay is the PCI device class of graphics adapters
+const pciClassDisplay = "display"
+
+// VGPUCheck detects NVIDIA vGPU profiles and GPU passthrough devices
+// GPU resources must be re-planned on the target platform
+type VGPUCheck struct{}
+
+// NewVGPUCheck creates a new VGPUCheck
+func NewVGPUCheck() *VGPUCheck {
+	return &VGPUCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *VGPUCheck) ID() string {
+	return "vgpu"
+}
+
+// Category returns the category of the check
+func (c *VGPUCheck) Category() string {
+	return CategoryPlatform
+}
+
+// Tags returns the tags of the check
+func (c *VGPUCheck) Tags() []string {
+	return []string{"vsphere-config", "devices"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *VGPUCheck) Sources() []Source {
+	return []Source{SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *VGPUCheck) Name() string {
+	return "vGPU and GPU Passthrough"
+}
+
+// Describe returns the metadata of the check
+func (c *VGPUCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Detects NVIDIA vGPU profiles and GPU passthrough devices. GPU resources must be re-planned on the target platform.")
+}
+
+// Run validates attached host devices from the vSphere configuration
+func (c *VGPUCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config.Devices), nil
+}
+
+// evaluate reports vGPU profiles and passed through graphics adapters
+func (c *VGPUCheck) evaluate(devices []types.PassthroughDevice) CheckResult {
+	var details []string
+	for _, device := range devices {
+		switch {
+		case device.Type == deviceTypeVGPU:
+			details = append(details, fmt.Sprintf("%s: vGPU profile %s", device.Label, device.Backing))
+		case device.Type == deviceTypePCI && device.Class == pciClassDisplay:
+			detail := fmt.Sprintf("%s: GPU passthrough device %s", device.Label, device.Backing)
+			if device.Vendor != "" {
+				detail += fmt.Sprintf(" (%s)", device.Vendor)
+			}
+			details = append(details, detail)
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("vgpu.found", "count", len(details))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("vgpu.none")
+}
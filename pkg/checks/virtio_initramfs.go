@@ -0,0 +1,127 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// requiredVirtioModules lists the virtio modules the guest needs to boot and reach the network on the target
+var requiredVirtioModules = []string{
+	"virtio_blk",
+	"virtio_scsi",
+	"virtio_net",
+}
+
+// VirtioInitramfsCheck verifies that virtio modules are present in the guest initramfs images
+// Guests missing them will need their initramfs regenerated with dracut during conversion
+type VirtioInitramfsCheck struct{}
+
+// NewVirtioInitramfsCheck creates a new VirtioInitramfsCheck
+func NewVirtioInitramfsCheck() *VirtioInitramfsCheck {
+	return &VirtioInitramfsCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *VirtioInitramfsCheck) ID() string {
+	return "virtio-initramfs"
+}
+
+// Category returns the category of the check
+func (c *VirtioInitramfsCheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *VirtioInitramfsCheck) Tags() []string {
+	return []string{"linux", "drivers"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *VirtioInitramfsCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *VirtioInitramfsCheck) Name() string {
+	return "Virtio Drivers in Initramfs"
+}
+
+// Describe returns the metadata of the check
+func (c *VirtioInitramfsCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Verifies that virtio modules are present in the guest initramfs images. Guests missing them will need their initramfs regenerated with dracut during conversion.", OSFamilyLinux)
+}
+
+// Run lists the guest initramfs images and reports missing virtio modules
+func (c *VirtioInitramfsCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	images, err := params.listInitramfsFiles(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(images), nil
+}
+
+// evaluate checks every initramfs image for the required virtio modules
+func (c *VirtioInitramfsCheck) evaluate(images map[string][]string) CheckResult {
+	if len(images) == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+		}.withMessage("virtio-initramfs.no-images")
+	}
+
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var details []string
+	for _, name := range names {
+		present := make(map[string]bool)
+		for _, file := range images[name] {
+			if module := kernelModuleName(file); module != "" {
+				present[module] = true
+			}
+		}
+
+		var missing []string
+		for _, module := range requiredVirtioModules {
+			if !present[module] {
+				missing = append(missing, module)
+			}
+		}
+		if len(missing) > 0 {
+			details = append(details, fmt.Sprintf("%s is missing %s", name, strings.Join(missing, ", ")))
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("virtio-initramfs.missing-modules")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("virtio-initramfs.modules-present", "count", len(images))
+}
+
+// kernelModuleName returns the module name of a kernel module file path (e.g., ".../virtio_blk.ko.xz" -> "virtio_blk")
+func kernelModuleName(file string) string {
+	base := path.Base(file)
+	idx := strings.Index(base, ".ko")
+	if idx <= 0 {
+		return ""
+	}
+	// Module names treat "-" and "_" as equivalent
+	return strings.ReplaceAll(base[:idx], "-", "_")
+}
@@ -0,0 +1,141 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// DefaultMinVirtioKernel is the first upstream kernel shipping virtio_blk and virtio_net drivers
+const DefaultMinVirtioKernel = "2.6.25"
+
+// VirtioKernelCheck fails when the guest kernel predates virtio driver availability
+// Such guests cannot boot with virtio disks or networking on the target hypervisor
+type VirtioKernelCheck struct {
+	minVersion string
+}
+
+// NewVirtioKernelCheck creates a new VirtioKernelCheck
+// minVersion: minimum kernel version with virtio support (uses DefaultMinVirtioKernel if empty)
+func NewVirtioKernelCheck(minVersion string) *VirtioKernelCheck {
+	if minVersion == "" {
+		minVersion = DefaultMinVirtioKernel
+	}
+	return &VirtioKernelCheck{
+		minVersion: minVersion,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *VirtioKernelCheck) ID() string {
+	return "virtio-kernel"
+}
+
+// Category returns the category of the check
+func (c *VirtioKernelCheck) Category() string {
+	return CategoryBoot
+}
+
+// Tags returns the tags of the check
+func (c *VirtioKernelCheck) Tags() []string {
+	return []string{"linux", "kernel", "drivers"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *VirtioKernelCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *VirtioKernelCheck) Name() string {
+	return "Minimum Kernel for Virtio"
+}
+
+// Describe returns the metadata of the check
+func (c *VirtioKernelCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Fails when the guest kernel predates virtio driver availability. Such guests cannot boot with virtio disks or networking on the target hypervisor.", OSFamilyLinux)
+}
+
+// Run inspects the VM snapshot and validates the guest kernel version
+func (c *VirtioKernelCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks that each Linux guest has at least one kernel with virtio support
+func (c *VirtioKernelCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	minVersion, ok := parseKernelVersion(c.minVersion)
+	if !ok {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+		}.withMessage("virtio-kernel.invalid-min-version", "version", c.minVersion)
+	}
+
+	var tooOld []string
+	var details []string
+	checked := 0
+
+	for _, guest := range data.Operatingsystems {
+		if guest.Name != "linux" {
+			continue
+		}
+		desc := describeOS(guest)
+		kernels := installedKernels(guest)
+		if len(kernels) == 0 {
+			details = append(details, fmt.Sprintf("%s: no kernel packages found in application inventory", desc))
+			continue
+		}
+		checked++
+
+		newest := ""
+		var newestVersion []int
+		for _, kernel := range kernels {
+			version, ok := parseKernelVersion(kernel)
+			if !ok {
+				continue
+			}
+			if newestVersion == nil || compareKernelVersions(version, newestVersion) > 0 {
+				newest, newestVersion = kernel, version
+			}
+		}
+
+		if newestVersion == nil || compareKernelVersions(newestVersion, minVersion) < 0 {
+			tooOld = append(tooOld, desc)
+			details = append(details, fmt.Sprintf("%s: newest kernel %s predates virtio support (%s)", desc, newest, c.minVersion))
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s: kernel %s supports virtio", desc, newest))
+	}
+
+	if len(tooOld) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("virtio-kernel.too-old", "kernels", strings.Join(tooOld, ", "))
+	}
+
+	if checked == 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+			Details:   details,
+		}.withMessage("virtio-kernel.no-kernels")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("virtio-kernel.supported")
+}
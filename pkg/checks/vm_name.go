@@ -0,0 +1,110 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VMNameCheck validates the vSphere VM name against Kubernetes naming rules and
+// its uniqueness within the target namespace, suggesting a sanitized target name
+type VMNameCheck struct {
+	existingNames map[string]struct{}
+}
+
+// NewVMNameCheck creates a new VMNameCheck
+// existingNames: names of VMs already present in the target namespace (uniqueness is not checked if nil)
+func NewVMNameCheck(existingNames []string) *VMNameCheck {
+	names := make(map[string]struct{}, len(existingNames))
+	for _, name := range existingNames {
+		names[name] = struct{}{}
+	}
+	return &VMNameCheck{
+		existingNames: names,
+	}
+}
+
+// ID returns the stable identifier of the check
+func (c *VMNameCheck) ID() string {
+	return "vm-name"
+}
+
+// Category returns the category of the check
+func (c *VMNameCheck) Category() string {
+	return CategoryPlatform
+}
+
+// Tags returns the tags of the check
+func (c *VMNameCheck) Tags() []string {
+	return []string{"naming", "kubernetes"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *VMNameCheck) Sources() []Source {
+	return nil
+}
+
+// Name returns the name of the check
+func (c *VMNameCheck) Name() string {
+	return "VM Name Validity"
+}
+
+// Describe returns the metadata of the check
+func (c *VMNameCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Validates the vSphere VM name against Kubernetes naming rules and its uniqueness within the target namespace, suggesting a sanitized target name.")
+}
+
+// Run validates the VM name from the inspection parameters
+func (c *VMNameCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	return c.evaluate(params.VMName), nil
+}
+
+// evaluate validates the VM name and suggests a target name
+func (c *VMNameCheck) evaluate(vmName string) CheckResult {
+	var details []string
+	for _, problem := range dns1123LabelProblems(vmName) {
+		details = append(details, fmt.Sprintf("VM name %q: %s", vmName, problem))
+	}
+
+	suggested := c.uniqueName(sanitizeDNS1123Label(vmName))
+	if _, exists := c.existingNames[vmName]; exists {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   append(details, fmt.Sprintf("suggested target name: %s", suggested)),
+		}.withMessage("vm-name.exists", "name", vmName)
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(details, fmt.Sprintf("suggested target name: %s", suggested)),
+		}.withMessage("vm-name.invalid")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("vm-name.valid")
+}
+
+// uniqueName appends a numeric suffix to name until it does not collide with an existing name
+func (c *VMNameCheck) uniqueName(name string) string {
+	candidate := name
+	for n := 2; ; n++ {
+		if _, exists := c.existingNames[candidate]; !exists {
+			return candidate
+		}
+		suffix := "-" + strconv.Itoa(n)
+		base := name
+		if len(base)+len(suffix) > dns1123LabelMaxLength {
+			base = strings.TrimRight(base[:dns1123LabelMaxLength-len(suffix)], "-")
+		}
+		candidate = base + suffix
+	}
+}
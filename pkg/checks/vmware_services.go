@@ -0,0 +1,103 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// vmwareServicePrefixes lists unit name prefixes of VMware-dependent services
+var vmwareServicePrefixes = []string{
+	"vmtoolsd",
+	"vgauthd",
+	"vgauth",
+	"vmware-",
+	"open-vm-tools",
+	"run-vmblock",
+}
+
+// VMwareServicesCheck detects enabled systemd units that depend on VMware
+// These services fail or keep retrying on the target and should be disabled or removed after conversion
+type VMwareServicesCheck struct{}
+
+// NewVMwareServicesCheck creates a new VMwareServicesCheck
+func NewVMwareServicesCheck() *VMwareServicesCheck {
+	return &VMwareServicesCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *VMwareServicesCheck) ID() string {
+	return "vmware-services"
+}
+
+// Category returns the category of the check
+func (c *VMwareServicesCheck) Category() string {
+	return CategoryApplications
+}
+
+// Tags returns the tags of the check
+func (c *VMwareServicesCheck) Tags() []string {
+	return []string{"linux", "vmware-tools"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *VMwareServicesCheck) Sources() []Source {
+	return []Source{SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *VMwareServicesCheck) Name() string {
+	return "VMware Services"
+}
+
+// Describe returns the metadata of the check
+func (c *VMwareServicesCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects enabled systemd units that depend on VMware. These services fail or keep retrying on the target and should be disabled or removed after conversion.", OSFamilyLinux)
+}
+
+// Run inspects the enabled systemd units of the guest
+func (c *VMwareServicesCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	services, err := params.enabledServices(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(services), nil
+}
+
+// evaluate reports enabled VMware-dependent services
+func (c *VMwareServicesCheck) evaluate(services []types.EnabledService) CheckResult {
+	var details []string
+	for _, service := range services {
+		if isVMwareService(service.Name) {
+			details = append(details, fmt.Sprintf("%s enabled by %s", service.Name, service.WantedBy))
+		}
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   details,
+		}.withMessage("vmware-services.found", "count", len(details))
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("vmware-services.none")
+}
+
+// isVMwareService returns true for systemd unit names of VMware-dependent services
+func isVMwareService(unit string) bool {
+	name := strings.ToLower(unit)
+	for _, prefix := range vmwareServicePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
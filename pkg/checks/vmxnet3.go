@@ -0,0 +1,139 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// vmxnet3ConfigPaths lists guest configuration that may carry vmxnet3-specific settings
+// Network configuration is read together with module options and udev rules
+var vmxnet3ConfigPaths = append([]string{
+	"/etc/modprobe.d",
+	"/etc/udev/rules.d",
+}, networkConfigPaths...)
+
+// vmxnet3SettingPattern matches configuration lines that depend on the vmxnet3 driver or tune NIC offloads
+var vmxnet3SettingPattern = regexp.MustCompile(`(?i)vmxnet3|ETHTOOL_OPTS|^\s*ethtool[.\s]|post-up\s+.*ethtool|\[ethtool\]`)
+
+// VMXNET3Check lists the VM's NIC types and verifies the guest has drivers for the target virtio-net NIC
+// Guests depending on vmxnet3-specific offload settings are flagged
+type VMXNET3Check struct{}
+
+// NewVMXNET3Check creates a new VMXNET3Check
+func NewVMXNET3Check() *VMXNET3Check {
+	return &VMXNET3Check{}
+}
+
+// ID returns the stable identifier of the check
+func (c *VMXNET3Check) ID() string {
+	return "vmxnet3-driver"
+}
+
+// Category returns the category of the check
+func (c *VMXNET3Check) Category() string {
+	return CategoryNetwork
+}
+
+// Tags returns the tags of the check
+func (c *VMXNET3Check) Tags() []string {
+	return []string{"vsphere-config", "linux", "drivers"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *VMXNET3Check) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceGuestFilesystem, SourceVSphereConfig}
+}
+
+// Name returns the name of the check
+func (c *VMXNET3Check) Name() string {
+	return "vmxnet3 NIC Driver Availability"
+}
+
+// Describe returns the metadata of the check
+func (c *VMXNET3Check) Describe() Metadata {
+	return describe(c, SeverityWarning, "Lists the VM's NIC types and verifies the guest has drivers for the target virtio-net NIC. Guests depending on vmxnet3-specific offload settings are flagged.", OSFamilyLinux)
+}
+
+// Run validates virtio-net driver availability and vmxnet3-specific guest configuration
+func (c *VMXNET3Check) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	config, err := params.vmConfig()
+	if err != nil {
+		return CheckResult{}, err
+	}
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	files, err := params.readGuestFiles(ctx, vmxnet3ConfigPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(config.NICs, data, files), nil
+}
+
+// evaluate reports NIC types, missing virtio-net support and vmxnet3-specific settings
+func (c *VMXNET3Check) evaluate(nics []types.NetworkAdapter, data *types.VirtInspectorXML, files map[string][]byte) CheckResult {
+	var details []string
+	for _, nic := range nics {
+		details = append(details, fmt.Sprintf("%s: %s on %s (%s)", nic.Label, nic.Type, nic.Network, nic.MACAddress))
+	}
+
+	var problems []string
+	minVersion, _ := parseKernelVersion(DefaultMinVirtioKernel)
+	for _, guest := range data.Operatingsystems {
+		if guest.Name != "linux" {
+			// virt-v2v injects virtio-win network drivers into Windows guests
+			continue
+		}
+		supported := false
+		for _, kernel := range installedKernels(guest) {
+			if version, ok := parseKernelVersion(kernel); ok && compareKernelVersions(version, minVersion) >= 0 {
+				supported = true
+			}
+		}
+		if !supported {
+			problems = append(problems, fmt.Sprintf("%s: no kernel with virtio-net support found", describeOS(guest)))
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		scanner := bufio.NewScanner(bytes.NewReader(files[p]))
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "#") || !vmxnet3SettingPattern.MatchString(line) {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s:%d depends on vmxnet3-specific settings: %s", p, lineNum, line))
+		}
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(problems, details...),
+		}.withMessage("vmxnet3-driver.unsupported")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+		Details:   details,
+	}.withMessage("vmxnet3-driver.supported")
+}
@@ -0,0 +1,134 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// xorgConfigPaths lists Xorg configuration files and directories read from the guest
+var xorgConfigPaths = []string{
+	"/etc/X11/xorg.conf",
+	"/etc/X11/xorg.conf.d",
+}
+
+// vmwareDisplayPackages lists packages providing the VMware Xorg display driver
+var vmwareDisplayPackages = []string{
+	"xorg-x11-drv-vmware",
+	"xserver-xorg-video-vmware",
+	"xf86-video-vmware",
+}
+
+// xorgVMwareDriverPattern matches Device section lines pinning the vmware or vmwgfx driver
+var xorgVMwareDriverPattern = regexp.MustCompile(`(?i)^\s*Driver\s+"(vmware|vmwgfx)"`)
+
+// XorgDriverCheck detects Xorg configuration pinning the VMware display driver
+// The graphical console may not start on the target until it is reconfigured
+type XorgDriverCheck struct{}
+
+// NewXorgDriverCheck creates a new XorgDriverCheck
+func NewXorgDriverCheck() *XorgDriverCheck {
+	return &XorgDriverCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *XorgDriverCheck) ID() string {
+	return "xorg-vmware-driver"
+}
+
+// Category returns the category of the check
+func (c *XorgDriverCheck) Category() string {
+	return CategoryApplications
+}
+
+// Tags returns the tags of the check
+func (c *XorgDriverCheck) Tags() []string {
+	return []string{"linux", "display"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *XorgDriverCheck) Sources() []Source {
+	return []Source{SourceVirtInspector, SourceGuestFilesystem}
+}
+
+// Name returns the name of the check
+func (c *XorgDriverCheck) Name() string {
+	return "Xorg VMware Display Driver"
+}
+
+// Describe returns the metadata of the check
+func (c *XorgDriverCheck) Describe() Metadata {
+	return describe(c, SeverityWarning, "Detects Xorg configuration pinning the VMware display driver. The graphical console may not start on the target until it is reconfigured.", OSFamilyLinux)
+}
+
+// Run inspects installed packages and Xorg configuration of the guest
+func (c *XorgDriverCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	files, err := params.readGuestFiles(ctx, xorgConfigPaths...)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data, files), nil
+}
+
+// evaluate reports pinned VMware display drivers and installed driver packages
+func (c *XorgDriverCheck) evaluate(data *types.VirtInspectorXML, files map[string][]byte) CheckResult {
+	var pinned []string
+	var packages []string
+
+	for _, guest := range data.Operatingsystems {
+		if pkg := findApplication(guest, vmwareDisplayPackages); pkg != "" {
+			packages = append(packages, fmt.Sprintf("installed display driver package: %s", pkg))
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		scanner := bufio.NewScanner(bytes.NewReader(files[p]))
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := scanner.Text()
+			if xorgVMwareDriverPattern.MatchString(line) {
+				pinned = append(pinned, fmt.Sprintf("%s:%d pins display driver: %s", p, lineNum, strings.TrimSpace(line)))
+			}
+		}
+	}
+
+	if len(pinned) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityWarning,
+			Details:   append(pinned, packages...),
+		}.withMessage("xorg-vmware-driver.pinned")
+	}
+
+	if len(packages) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     true,
+			Severity:  SeverityInfo,
+			Details:   packages,
+		}.withMessage("xorg-vmware-driver.installed")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("xorg-vmware-driver.none")
+}
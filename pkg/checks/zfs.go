@@ -0,0 +1,115 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// ZFSCheck detects ZFS pools and ZFS root filesystems in the guest
+// libguestfs and virt-v2v have limited ZFS support, so a ZFS root is unlikely
+// to boot on the target without manual intervention
+type ZFSCheck struct{}
+
+// NewZFSCheck creates a new ZFSCheck
+func NewZFSCheck() *ZFSCheck {
+	return &ZFSCheck{}
+}
+
+// ID returns the stable identifier of the check
+func (c *ZFSCheck) ID() string {
+	return "zfs"
+}
+
+// Category returns the category of the check
+func (c *ZFSCheck) Category() string {
+	return CategoryStorage
+}
+
+// Tags returns the tags of the check
+func (c *ZFSCheck) Tags() []string {
+	return []string{"linux", "filesystem"}
+}
+
+// Sources returns the inspection sources the check needs
+func (c *ZFSCheck) Sources() []Source {
+	return []Source{SourceVirtInspector}
+}
+
+// Name returns the name of the check
+func (c *ZFSCheck) Name() string {
+	return "ZFS Filesystems"
+}
+
+// Describe returns the metadata of the check
+func (c *ZFSCheck) Describe() Metadata {
+	return describe(c, SeverityError, "Detects ZFS pools and ZFS root filesystems in the guest. libguestfs and virt-v2v have limited ZFS support, so a ZFS root is unlikely to boot on the target without manual intervention.", OSFamilyLinux)
+}
+
+// Run inspects the VM snapshot and reports ZFS pools and root filesystems
+func (c *ZFSCheck) Run(ctx context.Context, params InspectionParams) (CheckResult, error) {
+	data, err := params.inspectWithVirt(ctx)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return c.evaluate(data), nil
+}
+
+// evaluate checks filesystems and mountpoints for ZFS
+func (c *ZFSCheck) evaluate(data *types.VirtInspectorXML) CheckResult {
+	var details []string
+	rootOnZFS := false
+
+	for _, guest := range data.Operatingsystems {
+		zfsDevices := make(map[string]struct{})
+		for _, fs := range guest.Filesystems.Filesystem {
+			if isZFSType(fs.Type) {
+				zfsDevices[fs.Device] = struct{}{}
+				details = append(details, fmt.Sprintf("ZFS device: %s (%s)", fs.Device, fs.Type))
+			}
+		}
+
+		for _, mp := range guest.Mountpoints.Mountpoint {
+			if mp.MountPoint != "/" && mp.MountPoint != "/boot" {
+				continue
+			}
+			// ZFS datasets are mounted by name (e.g., "rpool/ROOT/ubuntu") rather than by /dev path
+			_, isZFSDevice := zfsDevices[mp.Device]
+			if isZFSDevice || (mp.Device != "" && !strings.HasPrefix(mp.Device, "/dev/")) {
+				rootOnZFS = true
+				details = append(details, fmt.Sprintf("%s is mounted from ZFS dataset %s", mp.MountPoint, mp.Device))
+			}
+		}
+	}
+
+	if rootOnZFS {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("zfs.root")
+	}
+
+	if len(details) > 0 {
+		return CheckResult{
+			CheckName: c.Name(),
+			Valid:     false,
+			Severity:  SeverityError,
+			Details:   details,
+		}.withMessage("zfs.pools")
+	}
+
+	return CheckResult{
+		CheckName: c.Name(),
+		Valid:     true,
+		Severity:  SeverityInfo,
+	}.withMessage("zfs.none")
+}
+
+// isZFSType returns true for filesystem types reported for ZFS pools and datasets
+func isZFSType(fsType string) bool {
+	return fsType == "zfs" || fsType == "zfs_member"
+}
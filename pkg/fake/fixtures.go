@@ -0,0 +1,125 @@
+package fake
+
+import (
+	"embed"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// fixtures are virt-inspector and virt-v2v-inspector outputs of sample guests
+//
+//go:embed fixtures/*.xml
+var fixtures embed.FS
+
+// ParseVirtInspectorXML parses virt-inspector XML output, e.g. recorded from a real VM, into a result
+func ParseVirtInspectorXML(data []byte) (*types.VirtInspectorXML, error) {
+	var result types.VirtInspectorXML
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse virt-inspector XML: %w", err)
+	}
+	result.RawXML = string(data)
+	return &result, nil
+}
+
+// ParseVirtV2VInspectorXML parses virt-v2v-inspector XML output, e.g. recorded from a real VM, into a result
+func ParseVirtV2VInspectorXML(data []byte) (*types.VirtV2VInspectorXML, error) {
+	var result types.VirtV2VInspectorXML
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse virt-v2v-inspector XML: %w", err)
+	}
+	result.RawXML = string(data)
+	return &result, nil
+}
+
+// NewInspector creates an Inspector returning the results of virt-inspector and virt-v2v-inspector XML
+// output (no result if empty)
+func NewInspector(virtInspectorXML []byte, virtV2vInspectorXML []byte) (*Inspector, error) {
+	inspector := &Inspector{}
+	var err error
+	if len(virtInspectorXML) > 0 {
+		if inspector.VirtInspectorXML, err = ParseVirtInspectorXML(virtInspectorXML); err != nil {
+			return nil, err
+		}
+	}
+	if len(virtV2vInspectorXML) > 0 {
+		if inspector.VirtV2VInspectorXML, err = ParseVirtV2VInspectorXML(virtV2vInspectorXML); err != nil {
+			return nil, err
+		}
+	}
+	return inspector, nil
+}
+
+// RHEL9 returns an Inspector of a RHEL 9.4 UEFI guest with LVM, open-vm-tools and cloud-init
+func RHEL9() *Inspector {
+	inspector := fixture("rhel9")
+	inspector.GuestFiles = map[string][]byte{
+		"/etc/fstab": []byte("/dev/mapper/rhel-root /     xfs  defaults 0 0\n" +
+			"UUID=a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d /boot xfs defaults 0 0\n" +
+			"UUID=7A1B-2C3D /boot/efi vfat umask=0077,shortname=winnt 0 2\n" +
+			"/dev/mapper/rhel-swap none  swap defaults 0 0\n"),
+		"/etc/hostname":          []byte("rhel9.example.com\n"),
+		"/etc/selinux/config":    []byte("SELINUX=enforcing\nSELINUXTYPE=targeted\n"),
+		"/etc/default/grub":      []byte("GRUB_CMDLINE_LINUX=\"crashkernel=1G-4G:192M rd.lvm.lv=rhel/root rd.lvm.lv=rhel/swap\"\n"),
+		"/etc/cloud/cloud.cfg":   []byte("datasource_list: [ VMware, NoCloud, None ]\n"),
+		"/etc/sysconfig/network": []byte("# Created by anaconda\n"),
+	}
+	inspector.Initramfs = map[string][]string{
+		"/boot/initramfs-5.14.0-427.13.1.el9_4.x86_64.img": {
+			"usr/lib/modules/5.14.0-427.13.1.el9_4.x86_64/kernel/drivers/scsi/vmw_pvscsi.ko.xz",
+			"usr/lib/modules/5.14.0-427.13.1.el9_4.x86_64/kernel/drivers/virtio/virtio_pci.ko.xz",
+			"usr/lib/modules/5.14.0-427.13.1.el9_4.x86_64/kernel/drivers/block/virtio_blk.ko.xz",
+			"usr/lib/modules/5.14.0-427.13.1.el9_4.x86_64/kernel/drivers/scsi/virtio_scsi.ko.xz",
+		},
+	}
+	inspector.Filesystems = map[string]types.FilesystemUsage{
+		"/":         {MountPoint: "/", TotalBytes: 40 << 30, FreeBytes: 31 << 30, AvailableBytes: 31 << 30},
+		"/boot":     {MountPoint: "/boot", TotalBytes: 1 << 30, FreeBytes: 700 << 20, AvailableBytes: 700 << 20},
+		"/boot/efi": {MountPoint: "/boot/efi", TotalBytes: 600 << 20, FreeBytes: 590 << 20, AvailableBytes: 590 << 20},
+	}
+	inspector.Partitions = []types.PartitionTable{{Device: "/dev/sda", Type: "gpt"}}
+	inspector.LVM = &types.LVMLayout{
+		Disks:           []string{"/dev/sda"},
+		PhysicalVolumes: []types.LVMPhysicalVolume{{Device: "/dev/sda3", Disk: "/dev/sda", VolumeGroup: "rhel", UUID: "Vd3Kb1-Qm2H-4xJt-Zp8R-c0Lw-7NfS-2yGe0a"}},
+		LogicalVolumes: []types.LVMLogicalVolume{
+			{VolumeGroup: "rhel", Name: "root", Attributes: "-wi-ao----", SizeBytes: 40 << 30},
+			{VolumeGroup: "rhel", Name: "swap", Attributes: "-wi-ao----", SizeBytes: 4 << 30},
+		},
+	}
+	inspector.Services = []types.EnabledService{
+		{Name: "vmtoolsd.service", WantedBy: "multi-user.target"},
+		{Name: "cloud-init.service", WantedBy: "cloud-init.target"},
+		{Name: "sshd.service", WantedBy: "multi-user.target"},
+	}
+	return inspector
+}
+
+// Windows2019 returns an Inspector of a Windows Server 2019 BIOS guest with VMware Tools
+func Windows2019() *Inspector {
+	inspector := fixture("windows2019")
+	inspector.Filesystems = map[string]types.FilesystemUsage{
+		"/": {MountPoint: "/", TotalBytes: 60 << 30, FreeBytes: 38 << 30, AvailableBytes: 38 << 30},
+	}
+	inspector.Partitions = []types.PartitionTable{{Device: "/dev/sda", Type: "msdos"}}
+	return inspector
+}
+
+// fixture creates an Inspector with the embedded XML outputs of a sample guest
+// The fixtures are part of the package, so failing to parse them is a programming error
+func fixture(name string) *Inspector {
+	virtXML, err := fixtures.ReadFile("fixtures/" + name + "-virt-inspector.xml")
+	if err != nil {
+		panic(err)
+	}
+	v2vXML, err := fixtures.ReadFile("fixtures/" + name + "-virt-v2v-inspector.xml")
+	if err != nil {
+		panic(err)
+	}
+	inspector, err := NewInspector(virtXML, v2vXML)
+	if err != nil {
+		panic(err)
+	}
+	inspector.Versions = map[string]string{"virt-inspector": "1.52.0", "guestfish": "1.52.0", "virt-v2v-inspector": "2.5.6"}
+	return inspector
+}
@@ -0,0 +1,106 @@
+package fake_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/checks"
+	"github.com/nirarg/v2v-vm-validations/pkg/fake"
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// guestChecks returns the registered checks that only need guest inspection data, not the vSphere
+// configuration or the target storage
+func guestChecks(t *testing.T) []checks.Check {
+	t.Helper()
+	all, err := checks.NewChecks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var guest []checks.Check
+	for _, check := range all {
+		if check.Category() != checks.CategoryPlatform && check.ID() != "target-storage" && !slices.Contains(check.Sources(), checks.SourceVSphereConfig) {
+			guest = append(guest, check)
+		}
+	}
+	return guest
+}
+
+func TestFixturesRunGuestChecks(t *testing.T) {
+	tests := []struct {
+		name      string
+		inspector *fake.Inspector
+		want      map[string]string
+	}{
+		{
+			name:      "rhel9",
+			inspector: fake.RHEL9(),
+			want: map[string]string{
+				"supported-os":           "supported-os.supported",
+				"lvm":                    "lvm.available",
+				"fstab-by-path":          "fstab-by-path.portable",
+				"uefi-esp":               "uefi-esp.esp-found",
+				"selinux-relabel":        "selinux-relabel.relabel",
+				"cloud-init-datasources": "cloud-init-datasources.vmware-datasources",
+			},
+		},
+		{
+			name:      "windows2019",
+			inspector: fake.Windows2019(),
+			want: map[string]string{
+				"supported-os":          "supported-os.supported",
+				"windows-dynamic-disks": "windows-dynamic-disks.none",
+				"domain-controller":     "domain-controller.not-domain-controller",
+				"fstab-by-path":         "fstab-by-path.no-fstab",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := checks.NewCheckRunner(guestChecks(t)).Run(context.Background(), checks.InspectionParams{
+				VMName:       tt.name,
+				SnapshotName: "pre-migration",
+				DiskInfo:     &types.SnapshotDiskInfo{DiskPath: "[ds] vm/vm.vmdk", BaseDiskPath: "[ds] vm/vm.vmdk"},
+				Inspector:    tt.inspector,
+			})
+			for id := range tt.want {
+				if !slices.ContainsFunc(report.Results, func(result checks.CheckResult) bool { return result.CheckID == id }) {
+					t.Errorf("no result for check %s", id)
+				}
+			}
+			for _, result := range report.Results {
+				if result.Error != "" || !result.Valid {
+					t.Errorf("%s result = %+v, want a valid result", result.CheckID, result)
+				}
+				if want, ok := tt.want[result.CheckID]; ok && result.MessageID != want {
+					t.Errorf("%s message = %s (%s), want %s", result.CheckID, result.MessageID, result.Message, want)
+				}
+			}
+			if !report.Passed {
+				t.Error("Passed = false for a fixture without blocking issues")
+			}
+		})
+	}
+}
+
+func TestNewInspector(t *testing.T) {
+	if _, err := fake.NewInspector([]byte("<operatingsystems>"), nil); err == nil {
+		t.Error("NewInspector() accepted malformed virt-inspector XML")
+	}
+	if _, err := fake.NewInspector(nil, []byte("<v2v-inspection>")); err == nil {
+		t.Error("NewInspector() accepted malformed virt-v2v-inspector XML")
+	}
+
+	inspector, err := fake.NewInspector([]byte(`<operatingsystems><operatingsystem><name>linux</name><distro>rhel</distro></operatingsystem></operatingsystems>`), nil)
+	if err != nil {
+		t.Fatalf("NewInspector() error = %v", err)
+	}
+	data, err := inspector.InspectWithVirt(context.Background(), "vm", "snap", "", &types.SnapshotDiskInfo{})
+	if err != nil || len(data.Operatingsystems) != 1 || data.Operatingsystems[0].Distro != "rhel" {
+		t.Fatalf("InspectWithVirt() = %+v, %v", data, err)
+	}
+	if calls := inspector.Calls(); len(calls) != 1 || calls[0].Method != "InspectWithVirt" || calls[0].VMName != "vm" {
+		t.Errorf("Calls() = %+v", calls)
+	}
+}
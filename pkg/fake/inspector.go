@@ -0,0 +1,203 @@
+// Package fake provides an Inspector returning canned inspection results, so services using the checks can
+// unit test their validation workflows without libguestfs, VDDK or vCenter
+package fake
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/checks"
+	"github.com/nirarg/v2v-vm-validations/pkg/types"
+)
+
+// Inspector implements checks.Inspector with canned results, the same for every VM snapshot
+// Set it as InspectionParams.Inspector. Results are returned as configured, so checks see exactly the
+// fixture; guest file methods return empty results if not configured, inspections fail
+type Inspector struct {
+	VirtInspectorXML    *types.VirtInspectorXML
+	VirtV2VInspectorXML *types.VirtV2VInspectorXML
+
+	// GuestFiles are the guest files by absolute path, read by ReadGuestFiles and also listed by
+	// ListGuestDirectory and PathsExist (with their parent directories)
+	GuestFiles  map[string][]byte
+	Initramfs   map[string][]string              // Files of each initramfs image by image path
+	Filesystems map[string]types.FilesystemUsage // Usage of the guest filesystems by mount point
+	Partitions  []types.PartitionTable
+	Services    []types.EnabledService
+	LVM         *types.LVMLayout  // LVM layout, empty if not configured
+	Versions    map[string]string // Versions of the inspection tools
+
+	// Errors returned instead of the results, by method name (e.g. "InspectWithVirt"), to test
+	// inspection failures
+	Errors map[string]error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Call is a recorded call of the Inspector
+type Call struct {
+	Method       string
+	VMName       string
+	SnapshotName string
+	Args         []string // Paths, directory or mount points of guest file methods
+}
+
+var _ checks.Inspector = (*Inspector)(nil)
+
+// Calls returns the calls of the Inspector in order
+func (f *Inspector) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// record records a call and returns its configured error
+func (f *Inspector) record(method string, vmName string, snapshotName string, args ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: method, VMName: vmName, SnapshotName: snapshotName, Args: args})
+	return f.Errors[method]
+}
+
+// InspectWithVirt returns VirtInspectorXML
+func (f *Inspector) InspectWithVirt(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) (*types.VirtInspectorXML, error) {
+	if err := f.record("InspectWithVirt", vmName, snapshotName); err != nil {
+		return nil, err
+	}
+	if f.VirtInspectorXML == nil {
+		return nil, fmt.Errorf("fake inspector has no virt-inspector result")
+	}
+	return f.VirtInspectorXML, nil
+}
+
+// InspectWithVirtV2v returns VirtV2VInspectorXML
+func (f *Inspector) InspectWithVirtV2v(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, sslVerify string) (*types.VirtV2VInspectorXML, error) {
+	if err := f.record("InspectWithVirtV2v", vmName, snapshotName); err != nil {
+		return nil, err
+	}
+	if f.VirtV2VInspectorXML == nil {
+		return nil, fmt.Errorf("fake inspector has no virt-v2v-inspector result")
+	}
+	return f.VirtV2VInspectorXML, nil
+}
+
+// ReadGuestFiles returns the guest files at paths and under the directories of paths
+func (f *Inspector) ReadGuestFiles(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, paths []string) (map[string][]byte, error) {
+	if err := f.record("ReadGuestFiles", vmName, snapshotName, paths...); err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for _, guestPath := range paths {
+		for file, content := range f.GuestFiles {
+			if file == guestPath || strings.HasPrefix(file, strings.TrimSuffix(guestPath, "/")+"/") {
+				files[file] = content
+			}
+		}
+	}
+	return files, nil
+}
+
+// ListGuestDirectory returns the sorted names of the guest files and directories in dir
+func (f *Inspector) ListGuestDirectory(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, dir string) ([]string, error) {
+	if err := f.record("ListGuestDirectory", vmName, snapshotName, dir); err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	var entries []string
+	for file := range f.GuestFiles {
+		rest, found := strings.CutPrefix(file, prefix)
+		if !found {
+			continue
+		}
+		name, _, _ := strings.Cut(rest, "/")
+		if !slices.Contains(entries, name) {
+			entries = append(entries, name)
+		}
+	}
+	slices.Sort(entries)
+	return entries, nil
+}
+
+// PathsExist reports the paths that are guest files or their parent directories
+func (f *Inspector) PathsExist(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, paths []string) (map[string]bool, error) {
+	if err := f.record("PathsExist", vmName, snapshotName, paths...); err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(paths))
+	for _, guestPath := range paths {
+		exists[guestPath] = false
+		for file := range f.GuestFiles {
+			for ; file != "/" && file != "."; file = path.Dir(file) {
+				if file == path.Clean(guestPath) {
+					exists[guestPath] = true
+				}
+			}
+		}
+	}
+	return exists, nil
+}
+
+// ListInitramfsFiles returns Initramfs
+func (f *Inspector) ListInitramfsFiles(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) (map[string][]string, error) {
+	if err := f.record("ListInitramfsFiles", vmName, snapshotName); err != nil {
+		return nil, err
+	}
+	if f.Initramfs == nil {
+		return map[string][]string{}, nil
+	}
+	return f.Initramfs, nil
+}
+
+// FilesystemUsage returns the usage of the mount points found in Filesystems
+func (f *Inspector) FilesystemUsage(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo, mountPoints []string) (map[string]types.FilesystemUsage, error) {
+	if err := f.record("FilesystemUsage", vmName, snapshotName, mountPoints...); err != nil {
+		return nil, err
+	}
+	usage := make(map[string]types.FilesystemUsage)
+	for _, mountPoint := range mountPoints {
+		if stats, found := f.Filesystems[mountPoint]; found {
+			usage[mountPoint] = stats
+		}
+	}
+	return usage, nil
+}
+
+// PartitionTables returns Partitions
+func (f *Inspector) PartitionTables(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) ([]types.PartitionTable, error) {
+	if err := f.record("PartitionTables", vmName, snapshotName); err != nil {
+		return nil, err
+	}
+	return f.Partitions, nil
+}
+
+// ListEnabledServices returns Services
+func (f *Inspector) ListEnabledServices(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) ([]types.EnabledService, error) {
+	if err := f.record("ListEnabledServices", vmName, snapshotName); err != nil {
+		return nil, err
+	}
+	return f.Services, nil
+}
+
+// LVMLayout returns LVM
+func (f *Inspector) LVMLayout(ctx context.Context, vmName string, snapshotName string, datacenter string, diskInfo *types.SnapshotDiskInfo) (*types.LVMLayout, error) {
+	if err := f.record("LVMLayout", vmName, snapshotName); err != nil {
+		return nil, err
+	}
+	if f.LVM == nil {
+		return &types.LVMLayout{}, nil
+	}
+	return f.LVM, nil
+}
+
+// ToolVersions returns Versions
+func (f *Inspector) ToolVersions(ctx context.Context) map[string]string {
+	return f.Versions
+}
+
+// CloseSessions does nothing, the Inspector has no sessions
+func (f *Inspector) CloseSessions() {}
@@ -8,13 +8,60 @@ import (
 
 // Re-export persistent types
 type (
-	Inspector   = persistent.Inspector
-	Credentials = persistent.Credentials
-	CacheKey    = persistent.CacheKey
-	DB          = persistent.DB
+	Inspector               = persistent.Inspector
+	Credentials             = persistent.Credentials
+	SessionOptions          = persistent.SessionOptions
+	InspectorOptions        = persistent.InspectorOptions
+	VirtInspectorOptions    = persistent.VirtInspectorOptions
+	VirtV2vInspectorOptions = persistent.VirtV2vInspectorOptions
+	DecryptionKey           = persistent.DecryptionKey
+	NBDKitFilters           = persistent.NBDKitFilters
+	VDDKLibrary             = persistent.VDDKLibrary
+	Executor                = persistent.Executor
+	LocalExecutor           = persistent.LocalExecutor
+	ContainerExecutor       = persistent.ContainerExecutor
+	SSHExecutor             = persistent.SSHExecutor
+	VDDKOptions             = persistent.VDDKOptions
+	SSHOptions              = persistent.SSHOptions
+	HTTPSOptions            = persistent.HTTPSOptions
+	RetryPolicy             = persistent.RetryPolicy
+	RetryError              = persistent.RetryError
+	FeatureError            = persistent.FeatureError
+	ToolError               = persistent.ToolError
+	CacheKey                = persistent.CacheKey
+	Recording               = persistent.Recording
+	RecordingMode           = persistent.RecordingMode
+	DB                      = persistent.DB
+)
+
+// Re-export disk transports
+const (
+	TransportVDDK = persistent.TransportVDDK
+	TransportSSH  = persistent.TransportSSH
+)
+
+// Re-export recording modes
+const (
+	RecordingRecord = persistent.RecordingRecord
+	RecordingReplay = persistent.RecordingReplay
+)
+
+// ErrNotRecorded is returned in replay mode for results without a recording
+var ErrNotRecorded = persistent.ErrNotRecorded
+
+// Re-export inspection phases
+const (
+	PhaseAuthFileCreated  = persistent.PhaseAuthFileCreated
+	PhaseNBDServerStarted = persistent.PhaseNBDServerStarted
+	PhaseNBDReady         = persistent.PhaseNBDReady
+	PhaseToolStarted      = persistent.PhaseToolStarted
+	PhaseXMLParsed        = persistent.PhaseXMLParsed
 )
 
 // Re-export constructor functions
 var (
-	NewInspector = persistent.NewInspector
+	NewInspector    = persistent.NewInspector
+	NewMetrics      = persistent.NewMetrics
+	Cleanup         = persistent.Cleanup
+	CleanupOnSignal = persistent.CleanupOnSignal
 )
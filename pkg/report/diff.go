@@ -0,0 +1,95 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/checks"
+)
+
+// ResultChange is the result of one check in two validation reports
+type ResultChange struct {
+	CheckID string              `json:"check_id"`
+	Before  *checks.CheckResult `json:"before,omitempty"`
+	After   *checks.CheckResult `json:"after,omitempty"`
+}
+
+// Diff lists how the results of a VM changed between two validation reports, e.g. before and
+// after remediation or between two snapshots of the same VM
+// A finding is a result that is not valid or has a warning or error severity
+type Diff struct {
+	Before        checks.VMIdentity `json:"before"`
+	After         checks.VMIdentity `json:"after"`
+	VerdictBefore checks.Verdict    `json:"verdict_before"`
+	VerdictAfter  checks.Verdict    `json:"verdict_after"`
+
+	Introduced  []ResultChange `json:"introduced,omitempty"`   // Findings only in the later report
+	Resolved    []ResultChange `json:"resolved,omitempty"`     // Findings only in the earlier report
+	Changed     []ResultChange `json:"changed,omitempty"`      // Findings in both reports with a different severity or message
+	Unchanged   []ResultChange `json:"unchanged,omitempty"`    // Results equal in both reports
+	NotCompared []string       `json:"not_compared,omitempty"` // IDs of checks run for only one of the reports
+}
+
+// Compare compares the results of two validation reports by check ID
+// Results are listed in check ID order
+func Compare(before, after *checks.ValidationReport) *Diff {
+	diff := &Diff{
+		Before:        before.VM,
+		After:         after.VM,
+		VerdictBefore: before.Verdict,
+		VerdictAfter:  after.Verdict,
+	}
+
+	beforeResults := resultsByCheck(before.Results)
+	afterResults := resultsByCheck(after.Results)
+
+	var ids []string
+	for id := range beforeResults {
+		ids = append(ids, id)
+	}
+	for id := range afterResults {
+		if _, found := beforeResults[id]; !found {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		b, inBefore := beforeResults[id]
+		a, inAfter := afterResults[id]
+		if !inBefore || !inAfter {
+			diff.NotCompared = append(diff.NotCompared, id)
+			continue
+		}
+
+		change := ResultChange{CheckID: id, Before: b, After: a}
+		switch {
+		case !isFinding(b) && isFinding(a):
+			diff.Introduced = append(diff.Introduced, change)
+		case isFinding(b) && !isFinding(a):
+			diff.Resolved = append(diff.Resolved, change)
+		case isFinding(b) && (b.Severity != a.Severity || b.Message != a.Message):
+			diff.Changed = append(diff.Changed, change)
+		default:
+			diff.Unchanged = append(diff.Unchanged, change)
+		}
+	}
+	return diff
+}
+
+// resultsByCheck indexes results by check ID, falling back to the check name for results without an ID
+func resultsByCheck(results []checks.CheckResult) map[string]*checks.CheckResult {
+	byCheck := make(map[string]*checks.CheckResult, len(results))
+	for i := range results {
+		id := results[i].CheckID
+		if id == "" {
+			id = results[i].CheckName
+		}
+		byCheck[id] = &results[i]
+	}
+	return byCheck
+}
+
+// isFinding returns true for results that need attention
+func isFinding(result *checks.CheckResult) bool {
+	return !result.Valid || result.Severity != checks.SeverityInfo
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/nirarg/v2v-vm-validations/pkg/checks"
+)
+
+// SchemaVersion is the version of the JSON report document
+// It changes whenever fields are renamed or removed so stored reports can be migrated
+const SchemaVersion = "v1"
+
+// Report is a stable JSON document of a validation report
+type Report struct {
+	SchemaVersion string    `json:"schema_version"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	*checks.ValidationReport
+}
+
+// New creates a JSON report document from a validation report
+// Results are sorted by check ID so reports of the same VM can be diffed
+func New(validation *checks.ValidationReport) *Report {
+	sorted := *validation
+	sorted.Results = make([]checks.CheckResult, len(validation.Results))
+	copy(sorted.Results, validation.Results)
+	sort.SliceStable(sorted.Results, func(i, j int) bool {
+		return sorted.Results[i].CheckID < sorted.Results[j].CheckID
+	})
+	return &Report{
+		SchemaVersion:    SchemaVersion,
+		GeneratedAt:      time.Now().UTC(),
+		ValidationReport: &sorted,
+	}
+}
+
+// Write serializes the report as indented JSON
+func (r *Report) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return nil
+}
+
+// Read parses a JSON report and verifies its schema version
+func Read(r io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+	if report.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported report schema version %q (expected %q)", report.SchemaVersion, SchemaVersion)
+	}
+	if report.ValidationReport == nil {
+		report.ValidationReport = &checks.ValidationReport{}
+	}
+	return &report, nil
+}
@@ -1,5 +1,7 @@
 package types
 
+import "strings"
+
 // SnapshotDiskInfo contains VM moref, snapshot moref, disk path, and compute resource path for inspection
 // This is used by both vm_service (to retrieve the info) and inspection (to use it)
 type SnapshotDiskInfo struct {
@@ -7,5 +9,90 @@ type SnapshotDiskInfo struct {
 	SnapshotMoref       string
 	DiskPath            string
 	BaseDiskPath        string
-	ComputeResourcePath string // Path to compute resource (host/cluster) for vpx:// URL (e.g., "/Datacenter/Cluster/host.example.com")
+	ComputeResourcePath string         // Path to compute resource (host/cluster) for vpx:// URL (e.g., "/Datacenter/Cluster/host.example.com")
+	CapacityBytes       int64          // Provisioned capacity of the disk
+	UsedBytes           int64          // Space committed on the datastore (thin disks use less than their capacity), 0 if unknown
+	ExtraDisks          []SnapshotDisk // Other disks of the VM snapshot, inspected together with the disk above
+}
+
+// SnapshotDisk is a disk of a VM snapshot
+type SnapshotDisk struct {
+	DiskPath      string
+	BaseDiskPath  string
+	CapacityBytes int64
+	UsedBytes     int64
+}
+
+// Disks returns the disk info of each disk of the VM snapshot, starting with the disk of the info
+// The returned infos have no extra disks
+func (d *SnapshotDiskInfo) Disks() []*SnapshotDiskInfo {
+	first := *d
+	first.ExtraDisks = nil
+	disks := []*SnapshotDiskInfo{&first}
+	for _, extra := range d.ExtraDisks {
+		disk := first
+		disk.DiskPath = extra.DiskPath
+		disk.BaseDiskPath = extra.BaseDiskPath
+		disk.CapacityBytes = extra.CapacityBytes
+		disk.UsedBytes = extra.UsedBytes
+		disks = append(disks, &disk)
+	}
+	return disks
+}
+
+// FilesystemUsage contains usage statistics of a mounted guest filesystem
+type FilesystemUsage struct {
+	MountPoint     string `json:"mount_point"`
+	TotalBytes     uint64 `json:"total_bytes"`
+	FreeBytes      uint64 `json:"free_bytes"`      // Free space including blocks reserved for root
+	AvailableBytes uint64 `json:"available_bytes"` // Free space available to unprivileged users
+}
+
+// PartitionTable describes the partition table of a guest disk
+type PartitionTable struct {
+	Device string `json:"device"` // Device name inside the libguestfs appliance (e.g., "/dev/sda")
+	Type   string `json:"type"`   // Partition table type as reported by libguestfs ("gpt", "msdos") or "" if unpartitioned
+}
+
+// EnabledService describes a systemd unit enabled in the guest
+type EnabledService struct {
+	Name     string `json:"name"`      // Unit name (e.g., "vmtoolsd.service")
+	WantedBy string `json:"wanted_by"` // Target or unit whose .wants/.requires directory enables the unit (e.g., "multi-user.target")
+}
+
+// LVMLayout describes the LVM physical and logical volumes found in the guest disks
+type LVMLayout struct {
+	Disks           []string            `json:"disks"` // Disks attached to the appliance in SnapshotDiskInfo.Disks order (e.g., "/dev/sda")
+	PhysicalVolumes []LVMPhysicalVolume `json:"physical_volumes"`
+	LogicalVolumes  []LVMLogicalVolume  `json:"logical_volumes"`
+}
+
+// LVMPhysicalVolume describes an LVM physical volume of a volume group
+type LVMPhysicalVolume struct {
+	Device      string `json:"device"` // Device inside the libguestfs appliance (e.g., "/dev/sda2"), "" if missing
+	Disk        string `json:"disk"`   // Disk holding the device (e.g., "/dev/sda"), "" if missing
+	VolumeGroup string `json:"volume_group"`
+	UUID        string `json:"uuid"`
+	Missing     bool   `json:"missing"` // Recorded in the volume group metadata but not found on the disks
+}
+
+// LVMLogicalVolume describes an LVM logical volume
+type LVMLogicalVolume struct {
+	VolumeGroup     string   `json:"volume_group"`
+	Name            string   `json:"name"`
+	Attributes      string   `json:"attributes"` // lv_attr as reported by lvs (e.g., "-wi-ao----")
+	SizeBytes       uint64   `json:"size_bytes"`
+	Pool            string   `json:"pool,omitempty"`             // Thin pool of a thin volume
+	DataPercent     *float64 `json:"data_percent,omitempty"`     // Data usage of thin pools and thin volumes, nil if unknown
+	MetadataPercent *float64 `json:"metadata_percent,omitempty"` // Metadata usage of thin pools, nil if unknown
+}
+
+// ThinPool reports whether the logical volume is a thin pool
+func (lv LVMLogicalVolume) ThinPool() bool {
+	return strings.HasPrefix(lv.Attributes, "t")
+}
+
+// Partial reports whether the logical volume has extents on missing physical volumes
+func (lv LVMLogicalVolume) Partial() bool {
+	return len(lv.Attributes) > 8 && lv.Attributes[8] == 'p'
 }
@@ -3,6 +3,9 @@ package types
 // VirtInspectorXML represents the XML structure returned by virt-inspector
 type VirtInspectorXML struct {
 	Operatingsystems []VirtInspectorOS `xml:"operatingsystem" json:"operatingsystems"`
+
+	// RawXML is the original virt-inspector output, for elements not modeled by the types
+	RawXML string `xml:"-" json:"raw_xml,omitempty"`
 }
 
 // VirtInspectorOS represents an operating system entry in virt-inspector XML
@@ -71,5 +74,6 @@ type VirtInspectorDrives struct {
 
 // VirtInspectorDrive represents a drive
 type VirtInspectorDrive struct {
-	Name string `xml:"name,attr" json:"name"`
+	Name   string `xml:"name,attr" json:"name"`
+	Device string `xml:",chardata" json:"device,omitempty"`
 }
@@ -2,7 +2,16 @@ package types
 
 // VirtV2VInspectorXML represents the XML structure returned by virt-v2v-inspector
 type VirtV2VInspectorXML struct {
-	OS VirtV2VInspectorOS `xml:"operatingsystem" json:"operatingsystem"`
+	Firmware VirtV2VInspectorFirmware `xml:"firmware" json:"firmware"`
+	OS       VirtV2VInspectorOS       `xml:"operatingsystem" json:"operatingsystem"`
+
+	// RawXML is the original virt-v2v-inspector output, for elements not modeled by the types
+	RawXML string `xml:"-" json:"raw_xml,omitempty"`
+}
+
+// VirtV2VInspectorFirmware represents the firmware detected by virt-v2v-inspector
+type VirtV2VInspectorFirmware struct {
+	Type string `xml:"type,attr" json:"type"` // "bios" or "uefi"
 }
 
 // VirtV2VInspectorOS represents an operating system entry in virt-v2v-inspector XML
@@ -0,0 +1,86 @@
+package types
+
+import "regexp"
+
+// VMConfig contains vSphere VM configuration relevant for migration validation
+// This is retrieved by vm_service from the vSphere API and passed to checks
+type VMConfig struct {
+	GuestID     string // Configured guest OS identifier (config.guestId, e.g., "rhel8_64Guest")
+	Firmware    string // Firmware type from the VM config ("bios" or "efi")
+	SecureBoot  bool   // Whether UEFI Secure Boot is enabled (uefi.secureBoot.enabled)
+	CBTEnabled  bool   // Whether Changed Block Tracking is enabled for the VM (changeTrackingEnabled)
+	PowerState  string // Power state (runtime.powerState: "poweredOn", "poweredOff", "suspended")
+	FTState     string // Fault Tolerance state (runtime.faultToleranceState, e.g., "notConfigured", "running")
+	NumCPUs     int    // Number of virtual CPUs (config.hardware.numCPU)
+	MemoryBytes int64  // Configured memory (config.hardware.memoryMB)
+
+	CPUHotAddEnabled    bool              // config.cpuHotAddEnabled
+	MemoryHotAddEnabled bool              // config.memoryHotAddEnabled
+	NUMASettings        map[string]string // Advanced numa.* settings from config.extraConfig
+
+	Disks       []VirtualDisk
+	Controllers []StorageController
+	Devices     []PassthroughDevice // Host devices attached to the VM
+	NICs        []NetworkAdapter
+
+	Snapshots           []Snapshot // Root snapshots of the VM snapshot tree (snapshot.rootSnapshotList)
+	ConsolidationNeeded bool       // Whether disks need consolidation (runtime.consolidationNeeded)
+}
+
+// VirtualDisk describes a virtual disk attached to the VM
+type VirtualDisk struct {
+	Label         string // Device label (e.g., "Hard disk 1")
+	FileName      string // Backing file (e.g., "[datastore] vm/vm.vmdk")
+	CapacityBytes int64  // Provisioned capacity of the disk
+	DiskMode      string // Disk mode ("persistent", "independent_persistent", "independent_nonpersistent", ...)
+	CBTEnabled    bool   // Whether Changed Block Tracking is enabled for the disk (scsiX:Y.ctkEnabled)
+	Sharing       string // Disk sharing mode ("sharingNone", "sharingMultiWriter") or "" if not reported
+	ChainLength   int    // Number of files in the disk's delta chain including the base disk (layoutEx)
+	RDMMode       string // Raw Device Mapping compatibility mode ("physicalMode", "virtualMode") or "" for regular disks
+	RDMDevice     string // Host LUN backing a Raw Device Mapping (e.g., "/vmfs/devices/disks/naa.600...")
+}
+
+// StorageController describes a virtual storage controller attached to the VM
+type StorageController struct {
+	Label      string // Device label (e.g., "SCSI controller 0")
+	Type       string // Controller type ("pvscsi", "lsilogic", "lsilogic-sas", "buslogic", "ahci", "ide", "nvme")
+	BusSharing string // SCSI bus sharing ("noSharing", "virtualSharing", "physicalSharing") or "" if not applicable
+}
+
+// NetworkAdapter describes a virtual network adapter attached to the VM
+type NetworkAdapter struct {
+	Label      string // Device label (e.g., "Network adapter 1")
+	Type       string // Adapter type ("vmxnet3", "vmxnet2", "e1000", "e1000e", "pcnet32", "sriov")
+	MACAddress string
+	Network    string // Name of the connected network or port group
+}
+
+// PassthroughDevice describes a host device attached to the VM
+type PassthroughDevice struct {
+	Label   string // Device label (e.g., "PCI device 0")
+	Type    string // Device type ("usb", "pci", "sriov", "vgpu")
+	Backing string // Host device backing the VM device (e.g., PCI address "0000:3b:00.0", USB path or vGPU profile "grid_t4-4q")
+	Vendor  string // Vendor of the host device (e.g., "NVIDIA Corporation") or "" if unknown
+	Class   string // PCI device class of passthrough devices (e.g., "display", "network") or "" if unknown
+}
+
+// Snapshot describes a node of the VM snapshot tree
+type Snapshot struct {
+	Name      string
+	Moref     string
+	SizeBytes int64 // Size of the delta files created by the snapshot (layoutEx)
+	Children  []Snapshot
+}
+
+// deltaDiskSuffix matches the suffix of snapshot delta disk files (e.g., "vm-000001.vmdk")
+var deltaDiskSuffix = regexp.MustCompile(`-\d{6}\.vmdk$`)
+
+// IsDeltaDisk reports whether a datastore path is a snapshot delta disk file
+func IsDeltaDisk(path string) bool {
+	return deltaDiskSuffix.MatchString(path)
+}
+
+// BaseDiskPath returns the path of the base disk of a snapshot delta disk file, or path itself for a base disk
+func BaseDiskPath(path string) string {
+	return deltaDiskSuffix.ReplaceAllString(path, ".vmdk")
+}